@@ -11,10 +11,12 @@ import (
 
 	"github.com/urfave/cli/v2"
 
+	"github.com/UsingCoding/gostore/internal/cli/cmd/agent"
 	"github.com/UsingCoding/gostore/internal/cli/cmd/app"
 	"github.com/UsingCoding/gostore/internal/cli/cmd/core"
 	"github.com/UsingCoding/gostore/internal/cli/cmd/identity"
 	"github.com/UsingCoding/gostore/internal/cli/cmd/mgnt"
+	"github.com/UsingCoding/gostore/internal/cli/cmd/recipients"
 	"github.com/UsingCoding/gostore/internal/cli/cmd/store"
 	"github.com/UsingCoding/gostore/internal/cli/cmd/totp"
 	"github.com/UsingCoding/gostore/internal/cli/tui"
@@ -60,8 +62,10 @@ func runApp(ctx context.Context, args []string) error {
 			core.Main(),
 			mgnt.Main(),
 			identity.Identity(),
+			recipients.Recipients(),
 			store.Store(),
 			totp.TOTP(),
+			agent.Agent(),
 		),
 		Flags: []cli.Flag{
 			&cli.StringFlag{
@@ -79,6 +83,13 @@ func runApp(ctx context.Context, args []string) error {
 					"GOSTORE_STORE_ID",
 				},
 			},
+			&cli.StringFlag{
+				Name:  "agent-socket",
+				Usage: "Path to agent socket, by default agent.sock in gostore dir",
+				EnvVars: []string{
+					"GOSTORE_AGENT_SOCK",
+				},
+			},
 			&cli.UintFlag{
 				Name:    "verbose",
 				Usage:   "Verbose mode: 1, 2, 3",
@@ -0,0 +1,79 @@
+package tests
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/UsingCoding/gostore/cmd/tests/api"
+)
+
+func TestAgent(t *testing.T) {
+	s, err := newSuite()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		s.cleanup()
+	})
+
+	err = s.gostore().Init(api.InitRequest{
+		ID: "main",
+	})
+	require.NoError(t, err)
+
+	const (
+		secretPath = "secret"
+		data       = "data"
+		passphrase = "correct horse battery staple"
+	)
+
+	err = s.gostore().Add(api.AddRequest{
+		Path: secretPath,
+		Data: bytes.NewBufferString(data),
+	})
+	require.NoError(t, err)
+
+	err = s.gostore().ProtectIdentities(api.ProtectIdentitiesRequest{
+		Passphrase: passphrase,
+	})
+	require.NoError(t, err)
+
+	err = s.gostore().StartAgent()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = s.gostore().StopAgent()
+	})
+
+	info, err := os.Stat(path.Join(s.basePath, "agent.sock"))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	_, err = s.gostore().Get(api.ReadRequest{
+		Path: secretPath,
+	})
+	require.Error(t, err, "agent holds nothing before unlock")
+
+	resp, err := s.gostore().
+		WithEnv("GOSTORE_PASSPHRASE=" + passphrase).
+		Get(api.ReadRequest{
+			Path: secretPath,
+		})
+	require.NoError(t, err)
+	require.Equal(t, data, string(resp.Data))
+
+	resp, err = s.gostore().Get(api.ReadRequest{
+		Path: secretPath,
+	})
+	require.NoError(t, err, "agent decrypts with unlocked identity")
+	require.Equal(t, data, string(resp.Data))
+
+	err = s.gostore().StopAgent()
+	require.NoError(t, err)
+
+	_, err = s.gostore().Get(api.ReadRequest{
+		Path: secretPath,
+	})
+	require.Error(t, err, "identity not available after agent stopped")
+}
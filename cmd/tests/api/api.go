@@ -3,6 +3,9 @@ package api
 import (
 	"encoding/json"
 	"io"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/UsingCoding/fpgo/pkg/slices"
 	"github.com/pkg/errors"
@@ -12,20 +15,69 @@ import (
 
 const (
 	gostorePath = "dist/gostore"
+
+	agentStartAttempts = 50
+	agentStartInterval = 100 * time.Millisecond
 )
 
 // API defines api for gostore cli
 type API interface {
 	Init(req InitRequest) error
 	Clone(req CloneRequest) error
+	Sync() error
+	Unpack() error
+	Pack() error
+	Rollback() error
+	SetStoreAuth(req StoreAuthRequest) error
 
 	Add(req AddRequest) error
 	Get(req ReadRequest) (ReadResponse, error)
+	Import(req ImportRequest) error
+	Batch(req BatchRequest) error
+	// Clip copies secret value to clipboard
+	Clip(req ClipRequest) error
 	Remove(req RemoveRequest) error
 	List(req ListRequest) (ListResponse, error)
 
 	Move(req MoveRequest) error
 	Copy(req CopyRequest) error
+
+	Find(req FindRequest) (FindResponse, error)
+	Exec(req ExecRequest) (ExecResponse, error)
+	Render(req RenderRequest) (RenderResponse, error)
+
+	Generate(req GenerateRequest) (GenerateResponse, error)
+	// EditGenerated opens editor with generated password, editor from EDITOR env
+	EditGenerated(req EditGeneratedRequest) error
+	SetPasswordPolicy(req PasswordPolicyRequest) error
+
+	Metadata(req MetadataRequest) (MetadataResponse, error)
+	SetMetadata(req SetMetadataRequest) error
+
+	History(req HistoryRequest) (HistoryResponse, error)
+	Restore(req RestoreRequest) error
+	Diff(req DiffRequest) (DiffResponse, error)
+
+	AddRecipients(req RecipientsRequest) error
+	RemoveRecipients(req RecipientsRequest) error
+	ListRecipients() (ListRecipientsResponse, error)
+	ListScopes() ([]RecipientsScope, error)
+	MigrateEncryption(req MigrateEncryptionRequest) error
+
+	AddTOTP(req AddTOTPRequest) error
+	ExportTOTP(req ExportTOTPRequest) (ExportTOTPResponse, error)
+	TOTPPasscode(req TOTPPasscodeRequest) (TOTPPasscodeResponse, error)
+	ImportTOTPQR(req ImportTOTPQRRequest) error
+
+	ImportIdentity(req ImportIdentityRequest) error
+	ProtectIdentities(req ProtectIdentitiesRequest) error
+
+	// StartAgent starts agent in background and waits until it serves requests
+	StartAgent() error
+	StopAgent() error
+
+	// WithEnv returns API which passes additional env to gostore
+	WithEnv(env ...string) API
 }
 
 func New(basePath string) API {
@@ -38,6 +90,7 @@ func New(basePath string) API {
 type api struct {
 	basePath string
 	storeID  maybe.Maybe[string]
+	env      []string
 }
 
 func (a api) Init(req InitRequest) error {
@@ -53,10 +106,18 @@ func (a api) Init(req InitRequest) error {
 		args = append(args, req.Recipients...)
 	}
 
+	if t, ok := maybe.JustValid(req.StorageType); ok {
+		args = append(args, "--storage-type", t)
+	}
+
 	if r, ok := maybe.JustValid(req.Remote); ok {
 		args = append(args, "--remote", r)
 	}
 
+	if e, ok := maybe.JustValid(req.Encryption); ok {
+		args = append(args, "--encryption", e)
+	}
+
 	_, err := a.gostore(input{
 		args:  args,
 		stdin: nil,
@@ -71,9 +132,14 @@ func (a api) Clone(req CloneRequest) error {
 		"clone",
 		"--id",
 		req.ID,
-		req.Remote,
 	}
 
+	if t, ok := maybe.JustValid(req.StorageType); ok {
+		args = append(args, "--storage-type", t)
+	}
+
+	args = append(args, req.Remote)
+
 	_, err := a.gostore(input{
 		args: args,
 	})
@@ -81,6 +147,63 @@ func (a api) Clone(req CloneRequest) error {
 	return err
 }
 
+func (a api) Sync() error {
+	_, err := a.gostore(input{
+		args: []string{"sync"},
+	})
+	return err
+}
+
+func (a api) Unpack() error {
+	_, err := a.gostore(input{
+		args: []string{"unpack"},
+	})
+	return err
+}
+
+func (a api) Pack() error {
+	_, err := a.gostore(input{
+		args: []string{"pack"},
+	})
+	return err
+}
+
+func (a api) Rollback() error {
+	_, err := a.gostore(input{
+		args: []string{"rollback"},
+	})
+	return err
+}
+
+func (a api) SetStoreAuth(req StoreAuthRequest) error {
+	args := []string{
+		"store",
+		"auth",
+		"set",
+		"--auth",
+		req.Type,
+	}
+
+	if u, ok := maybe.JustValid(req.User); ok {
+		args = append(args, "--auth-user", u)
+	}
+
+	if k, ok := maybe.JustValid(req.SSHKey); ok {
+		args = append(args, "--auth-ssh-key", k)
+	}
+
+	if e, ok := maybe.JustValid(req.SecretEnv); ok {
+		args = append(args, "--auth-secret-env", e)
+	}
+
+	args = append(args, req.StoreID)
+
+	_, err := a.gostore(input{
+		args: args,
+	})
+	return err
+}
+
 func (a api) Add(req AddRequest) error {
 	args := []string{
 		"add",
@@ -102,9 +225,18 @@ func (a api) Add(req AddRequest) error {
 func (a api) Get(req ReadRequest) (ReadResponse, error) {
 	args := []string{
 		"cat",
-		req.Path,
 	}
 
+	if at, ok := maybe.JustValid(req.At); ok {
+		args = append(args, "--at", at)
+	}
+
+	if f, ok := maybe.JustValid(req.Format); ok {
+		args = append(args, "--format", f)
+	}
+
+	args = append(args, req.Path)
+
 	if k, ok := maybe.JustValid(req.Key); ok {
 		args = append(args, k)
 	}
@@ -126,6 +258,61 @@ func (a api) Get(req ReadRequest) (ReadResponse, error) {
 	}, nil
 }
 
+func (a api) Import(req ImportRequest) error {
+	args := []string{
+		"import",
+		"--format",
+		req.Format,
+	}
+
+	if req.Replace {
+		args = append(args, "--replace")
+	}
+
+	args = append(args, req.Path)
+
+	_, err := a.gostore(input{
+		args:  args,
+		stdin: req.Data,
+	})
+	return err
+}
+
+func (a api) Batch(req BatchRequest) error {
+	var stdin strings.Builder
+	for _, op := range req.Ops {
+		data, err := json.Marshal(op)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal operation")
+		}
+		stdin.Write(data)
+		stdin.WriteString("\n")
+	}
+
+	_, err := a.gostore(input{
+		args:  []string{"batch"},
+		stdin: strings.NewReader(stdin.String()),
+	})
+	return err
+}
+
+func (a api) Clip(req ClipRequest) error {
+	args := []string{
+		"get",
+		"--clip",
+		"--clip-timeout",
+		req.Timeout.String(),
+		req.Path,
+	}
+
+	if k, ok := maybe.JustValid(req.Key); ok {
+		args = append(args, k)
+	}
+
+	_, err := a.gostore(input{args: args})
+	return err
+}
+
 func (a api) Remove(req RemoveRequest) error {
 	args := []string{
 		"rm",
@@ -160,8 +347,9 @@ func (a api) List(req ListRequest) (ListResponse, error) {
 	}
 
 	type jsonTreeNode struct {
-		Name  string         `json:"name"`
-		Elems []jsonTreeNode `json:"children,omitempty"`
+		Name     string         `json:"name"`
+		Elems    []jsonTreeNode `json:"children,omitempty"`
+		NoAccess bool           `json:"noAccess,omitempty"`
 	}
 
 	var res jsonTreeNode
@@ -174,8 +362,9 @@ func (a api) List(req ListRequest) (ListResponse, error) {
 	var mapNode func(node jsonTreeNode) ListNode
 	mapNode = func(node jsonTreeNode) ListNode {
 		return ListNode{
-			Name:  node.Name,
-			Nodes: slices.Map(node.Elems, mapNode),
+			Name:     node.Name,
+			Nodes:    slices.Map(node.Elems, mapNode),
+			NoAccess: node.NoAccess,
 		}
 	}
 
@@ -205,3 +394,461 @@ func (a api) Copy(req CopyRequest) error {
 	_, err := a.gostore(input{args: args})
 	return err
 }
+
+func (a api) Find(req FindRequest) (FindResponse, error) {
+	args := []string{
+		"-o", "json",
+		"find",
+	}
+
+	if req.Regex {
+		args = append(args, "--regex")
+	}
+
+	if req.Grep {
+		args = append(args, "--grep", "--yes")
+	}
+
+	args = append(args, req.Query)
+
+	o, err := a.gostore(input{args: args})
+	if err != nil {
+		return FindResponse{}, err
+	}
+
+	var res FindResponse
+	err = json.Unmarshal(o.stdout.Bytes(), &res.Results)
+	return res, errors.Wrap(err, "failed to unmarshal response")
+}
+
+func (a api) Exec(req ExecRequest) (ExecResponse, error) {
+	args := []string{
+		"exec",
+	}
+
+	for _, e := range req.Env {
+		args = append(args, "--env", e)
+	}
+
+	for _, p := range req.EnvFrom {
+		args = append(args, "--env-from", p)
+	}
+
+	args = append(args, "--")
+	args = append(args, req.Command...)
+
+	o, err := a.gostore(input{args: args})
+	if err != nil {
+		return ExecResponse{}, err
+	}
+
+	return ExecResponse{
+		Output: o.stdout.String(),
+	}, nil
+}
+
+func (a api) Render(req RenderRequest) (RenderResponse, error) {
+	o, err := a.gostore(input{
+		args:  []string{"render", "-"},
+		stdin: strings.NewReader(req.Template),
+	})
+	if err != nil {
+		return RenderResponse{}, err
+	}
+
+	return RenderResponse{
+		Output: o.stdout.String(),
+	}, nil
+}
+
+func (a api) Generate(req GenerateRequest) (GenerateResponse, error) {
+	args := []string{
+		"generate",
+	}
+
+	if l, ok := maybe.JustValid(req.Length); ok {
+		args = append(args, "--length", strconv.Itoa(l))
+	}
+
+	if c, ok := maybe.JustValid(req.Charset); ok {
+		args = append(args, "--charset", c)
+	}
+
+	if w, ok := maybe.JustValid(req.Words); ok {
+		args = append(args, "--words", strconv.Itoa(w))
+	}
+
+	if req.Passphrase {
+		args = append(args, "--passphrase")
+	}
+
+	args = append(args, req.Path)
+
+	if k, ok := maybe.JustValid(req.Key); ok {
+		args = append(args, k)
+	}
+
+	o, err := a.gostore(input{args: args})
+	if err != nil {
+		return GenerateResponse{}, err
+	}
+
+	return GenerateResponse{
+		Password: strings.TrimSuffix(o.stdout.String(), "\n"),
+	}, nil
+}
+
+func (a api) EditGenerated(req EditGeneratedRequest) error {
+	args := []string{
+		"edit",
+		"--generate",
+	}
+
+	if l, ok := maybe.JustValid(req.Length); ok {
+		args = append(args, "--length", strconv.Itoa(l))
+	}
+
+	args = append(args, req.Path)
+
+	if k, ok := maybe.JustValid(req.Key); ok {
+		args = append(args, k)
+	}
+
+	_, err := a.gostore(input{args: args})
+	return err
+}
+
+func (a api) SetPasswordPolicy(req PasswordPolicyRequest) error {
+	args := []string{
+		"store",
+		"policy",
+	}
+
+	if l, ok := maybe.JustValid(req.Length); ok {
+		args = append(args, "--length", strconv.Itoa(l))
+	}
+
+	if w, ok := maybe.JustValid(req.Words); ok {
+		args = append(args, "--words", strconv.Itoa(w))
+	}
+
+	for _, c := range req.Required {
+		args = append(args, "--require", c)
+	}
+
+	if req.ExcludeAmbiguous {
+		args = append(args, "--exclude-ambiguous")
+	}
+
+	_, err := a.gostore(input{args: args})
+	return err
+}
+
+func (a api) Metadata(req MetadataRequest) (MetadataResponse, error) {
+	o, err := a.gostore(input{
+		args: []string{"-o", "json", "get", req.Path},
+	})
+	if err != nil {
+		return MetadataResponse{}, err
+	}
+
+	var res struct {
+		Metadata MetadataResponse `json:"metadata"`
+	}
+	err = json.Unmarshal(o.stdout.Bytes(), &res)
+	return res.Metadata, errors.Wrap(err, "failed to unmarshal response")
+}
+
+func (a api) SetMetadata(req SetMetadataRequest) error {
+	args := []string{
+		"meta",
+	}
+
+	for _, tag := range req.Tags {
+		args = append(args, "--tag", tag)
+	}
+
+	if d, ok := maybe.JustValid(req.Description); ok {
+		args = append(args, "--description", d)
+	}
+
+	args = append(args, req.Path)
+
+	_, err := a.gostore(input{args: args})
+	return err
+}
+
+func (a api) AddRecipients(req RecipientsRequest) error {
+	args := []string{
+		"recipients",
+		"add",
+	}
+	if p, ok := maybe.JustValid(req.Path); ok {
+		args = append(args, "--path", p)
+	}
+	// armored PGP keys start with dashes
+	args = append(args, "--")
+	args = append(args, req.Recipients...)
+
+	_, err := a.gostore(input{args: args})
+	return err
+}
+
+func (a api) RemoveRecipients(req RecipientsRequest) error {
+	args := []string{
+		"recipients",
+		"rm",
+	}
+	if p, ok := maybe.JustValid(req.Path); ok {
+		args = append(args, "--path", p)
+	}
+	// armored PGP keys start with dashes
+	args = append(args, "--")
+	args = append(args, req.Recipients...)
+
+	_, err := a.gostore(input{args: args})
+	return err
+}
+
+func (a api) ListRecipients() (ListRecipientsResponse, error) {
+	args := []string{
+		"-o", "json",
+		"recipients",
+		"ls",
+	}
+
+	o, err := a.gostore(input{args: args})
+	if err != nil {
+		return ListRecipientsResponse{}, err
+	}
+
+	var res []string
+	err = json.Unmarshal(o.stdout.Bytes(), &res)
+	if err != nil {
+		return ListRecipientsResponse{}, errors.Wrap(err, "failed to unmarshal response")
+	}
+
+	return ListRecipientsResponse{
+		Recipients: res,
+	}, nil
+}
+
+func (a api) ListScopes() ([]RecipientsScope, error) {
+	args := []string{
+		"-o", "json",
+		"recipients",
+		"ls",
+		"--scopes",
+	}
+
+	o, err := a.gostore(input{args: args})
+	if err != nil {
+		return nil, err
+	}
+
+	var res []RecipientsScope
+	err = json.Unmarshal(o.stdout.Bytes(), &res)
+	return res, errors.Wrap(err, "failed to unmarshal response")
+}
+
+func (a api) MigrateEncryption(req MigrateEncryptionRequest) error {
+	args := []string{
+		"store",
+		"migrate-encryption",
+		"--to",
+		req.Encryption,
+	}
+	for _, r := range req.Recipients {
+		args = append(args, "-r", r)
+	}
+	for p, recipients := range req.ScopeRecipients {
+		for _, r := range recipients {
+			args = append(args, "-s", p+"="+r)
+		}
+	}
+
+	_, err := a.gostore(input{args: args})
+	return err
+}
+
+func (a api) AddTOTP(req AddTOTPRequest) error {
+	args := []string{
+		"totp",
+		"add",
+		"--uri",
+		req.URI,
+	}
+
+	if n, ok := maybe.JustValid(req.Name); ok {
+		args = append(args, n)
+	}
+
+	_, err := a.gostore(input{args: args})
+	return err
+}
+
+func (a api) ExportTOTP(req ExportTOTPRequest) (ExportTOTPResponse, error) {
+	o, err := a.gostore(input{
+		args: []string{"totp", "export", req.Name},
+	})
+	if err != nil {
+		return ExportTOTPResponse{}, err
+	}
+
+	return ExportTOTPResponse{
+		URI: strings.TrimSpace(o.stdout.String()),
+	}, nil
+}
+
+func (a api) TOTPPasscode(req TOTPPasscodeRequest) (TOTPPasscodeResponse, error) {
+	o, err := a.gostore(input{
+		args: []string{"totp", "passcode", req.Name},
+	})
+	if err != nil {
+		return TOTPPasscodeResponse{}, err
+	}
+
+	// output is header line and "Code: <code> Countdown: <n>s"
+	var res TOTPPasscodeResponse
+	for _, line := range strings.Split(o.stdout.String(), "\n") {
+		if code, ok := strings.CutPrefix(line, "Code: "); ok {
+			res.Code, _, _ = strings.Cut(code, " ")
+		}
+	}
+
+	if res.Code == "" {
+		return TOTPPasscodeResponse{}, errors.Errorf("no passcode in output: %s", o.stdout.String())
+	}
+
+	return res, nil
+}
+
+func (a api) ImportTOTPQR(req ImportTOTPQRRequest) error {
+	args := []string{"totp", "import-qr"}
+	if req.Overwrite {
+		args = append(args, "--overwrite")
+	}
+	args = append(args, req.ImagePath)
+
+	_, err := a.gostore(input{args: args})
+	return err
+}
+
+func (a api) ImportIdentity(req ImportIdentityRequest) error {
+	args := []string{
+		"identity",
+		"import",
+		"--provider",
+		req.Provider,
+	}
+
+	_, err := a.gostore(input{
+		args:  args,
+		stdin: req.Data,
+	})
+	return err
+}
+
+func (a api) ProtectIdentities(req ProtectIdentitiesRequest) error {
+	args := []string{
+		"identity",
+		"protect",
+	}
+
+	for _, r := range req.Recipients {
+		args = append(args, "-r", r)
+	}
+
+	_, err := a.gostore(input{
+		args:  args,
+		stdin: strings.NewReader(req.Passphrase + "\n"),
+	})
+	return err
+}
+
+func (a api) StartAgent() error {
+	c := a.command(input{args: []string{"agent", "start"}})
+	err := c.Start()
+	if err != nil {
+		return err
+	}
+	go func() {
+		_ = c.Wait()
+	}()
+
+	for range agentStartAttempts {
+		_, err = a.gostore(input{args: []string{"agent", "clear"}})
+		if err == nil {
+			return nil
+		}
+		time.Sleep(agentStartInterval)
+	}
+
+	_ = c.Process.Kill()
+	return err
+}
+
+func (a api) StopAgent() error {
+	_, err := a.gostore(input{args: []string{"agent", "stop"}})
+	return err
+}
+
+func (a api) WithEnv(env ...string) API {
+	a.env = append(append([]string(nil), a.env...), env...)
+	return a
+}
+
+func (a api) History(req HistoryRequest) (HistoryResponse, error) {
+	args := []string{
+		"-o", "json",
+		"history",
+		req.Path,
+	}
+
+	o, err := a.gostore(input{args: args})
+	if err != nil {
+		return HistoryResponse{}, err
+	}
+
+	var res HistoryResponse
+	err = json.Unmarshal(o.stdout.Bytes(), &res.Revisions)
+	return res, errors.Wrap(err, "failed to unmarshal response")
+}
+
+func (a api) Diff(req DiffRequest) (DiffResponse, error) {
+	args := []string{
+		"-o", "json",
+		"diff",
+	}
+
+	if req.ShowValues {
+		args = append(args, "--show-values")
+	}
+
+	args = append(args, req.Revisions...)
+
+	if p, ok := maybe.JustValid(req.Path); ok {
+		args = append(args, p)
+	}
+
+	o, err := a.gostore(input{args: args})
+	if err != nil {
+		return DiffResponse{}, err
+	}
+
+	var res DiffResponse
+	err = json.Unmarshal(o.stdout.Bytes(), &res.Secrets)
+	return res, errors.Wrap(err, "failed to unmarshal response")
+}
+
+func (a api) Restore(req RestoreRequest) error {
+	args := []string{
+		"restore",
+		"--to",
+		req.To,
+		req.Path,
+	}
+
+	_, err := a.gostore(input{args: args})
+	return err
+}
@@ -2,19 +2,31 @@ package api
 
 import (
 	"io"
+	"time"
 
 	"github.com/UsingCoding/gostore/internal/common/maybe"
 )
 
 type InitRequest struct {
-	ID         string
-	Recipients []string
-	Remote     maybe.Maybe[string]
+	ID          string
+	Recipients  []string
+	Remote      maybe.Maybe[string]
+	StorageType maybe.Maybe[string]
+	Encryption  maybe.Maybe[string]
 }
 
 type CloneRequest struct {
-	ID     string
-	Remote string
+	ID          string
+	Remote      string
+	StorageType maybe.Maybe[string]
+}
+
+type StoreAuthRequest struct {
+	StoreID   string
+	Type      string
+	User      maybe.Maybe[string]
+	SSHKey    maybe.Maybe[string]
+	SecretEnv maybe.Maybe[string]
 }
 
 type AddRequest struct {
@@ -25,14 +37,44 @@ type AddRequest struct {
 }
 
 type ReadRequest struct {
-	Path string
-	Key  maybe.Maybe[string]
+	Path   string
+	Key    maybe.Maybe[string]
+	At     maybe.Maybe[string]
+	Format maybe.Maybe[string]
 }
 
 type ReadResponse struct {
 	Data []byte
 }
 
+type ImportRequest struct {
+	Path    string
+	Format  string
+	Replace bool
+
+	Data io.Reader
+}
+
+type BatchRequest struct {
+	Ops []BatchOp
+}
+
+type BatchOp struct {
+	Op      string            `json:"op"`
+	Path    string            `json:"path,omitempty"`
+	Key     *string           `json:"key,omitempty"`
+	Value   *string           `json:"value,omitempty"`
+	Payload map[string]string `json:"payload,omitempty"`
+	Src     string            `json:"src,omitempty"`
+	Dst     string            `json:"dst,omitempty"`
+}
+
+type ClipRequest struct {
+	Path    string
+	Key     maybe.Maybe[string]
+	Timeout time.Duration
+}
+
 type RemoveRequest struct {
 	Path string
 	Key  maybe.Maybe[string]
@@ -49,6 +91,8 @@ type ListResponse struct {
 type ListNode struct {
 	Name  string
 	Nodes []ListNode
+	// NoAccess marks subtree which secrets can not be decrypted by local identities
+	NoAccess bool
 }
 
 type MoveRequest struct {
@@ -58,3 +102,185 @@ type MoveRequest struct {
 type CopyRequest struct {
 	Src, Dst string
 }
+
+type FindRequest struct {
+	Query string
+	Regex bool
+	Grep  bool
+}
+
+type FindResponse struct {
+	Results []FindResult
+}
+
+type FindResult struct {
+	Path        string   `json:"path"`
+	PathMatched bool     `json:"pathMatched"`
+	Keys        []string `json:"keys"`
+	Tags        []string `json:"tags"`
+	Values      []string `json:"values"`
+	NoAccess    bool     `json:"noAccess"`
+}
+
+type ExecRequest struct {
+	Env     []string
+	EnvFrom []string
+	Command []string
+}
+
+type ExecResponse struct {
+	Output string
+}
+
+type RenderRequest struct {
+	Template string
+}
+
+type RenderResponse struct {
+	Output string
+}
+
+type GenerateRequest struct {
+	Path       string
+	Key        maybe.Maybe[string]
+	Length     maybe.Maybe[int]
+	Charset    maybe.Maybe[string]
+	Words      maybe.Maybe[int]
+	Passphrase bool
+}
+
+type EditGeneratedRequest struct {
+	Path   string
+	Key    maybe.Maybe[string]
+	Length maybe.Maybe[int]
+}
+
+type GenerateResponse struct {
+	Password string
+}
+
+type PasswordPolicyRequest struct {
+	Length           maybe.Maybe[int]
+	Words            maybe.Maybe[int]
+	Required         []string
+	ExcludeAmbiguous bool
+}
+
+type MetadataRequest struct {
+	Path string
+}
+
+type MetadataResponse struct {
+	Created     *time.Time `json:"created"`
+	Updated     *time.Time `json:"updated"`
+	Author      string     `json:"author"`
+	Tags        []string   `json:"tags"`
+	Description string     `json:"description"`
+}
+
+type SetMetadataRequest struct {
+	Path        string
+	Tags        []string
+	Description maybe.Maybe[string]
+}
+
+type RecipientsRequest struct {
+	Recipients []string
+	// Path of subtree scope
+	Path maybe.Maybe[string]
+}
+
+type MigrateEncryptionRequest struct {
+	Encryption string
+	Recipients []string
+	// ScopeRecipients maps scope path to its recipients for new encryption
+	ScopeRecipients map[string][]string
+}
+
+type RecipientsScope struct {
+	Path       string   `json:"path"`
+	Recipients []string `json:"recipients"`
+	Accessible bool     `json:"accessible"`
+}
+
+type ListRecipientsResponse struct {
+	Recipients []string
+}
+
+type ImportIdentityRequest struct {
+	Provider string
+	Data     io.Reader
+}
+
+type ProtectIdentitiesRequest struct {
+	Recipients []string
+	Passphrase string
+}
+
+type HistoryRequest struct {
+	Path string
+}
+
+type HistoryResponse struct {
+	Revisions []Revision
+}
+
+type Revision struct {
+	ID      string `json:"id"`
+	Message string `json:"message"`
+}
+
+type RestoreRequest struct {
+	Path string
+	To   string
+}
+
+type DiffRequest struct {
+	Revisions  []string
+	Path       maybe.Maybe[string]
+	ShowValues bool
+}
+
+type DiffResponse struct {
+	Secrets []SecretDiff
+}
+
+type SecretDiff struct {
+	Path     string    `json:"path"`
+	Status   string    `json:"status"`
+	Keys     []KeyDiff `json:"keys"`
+	NoAccess bool      `json:"noAccess"`
+}
+
+type KeyDiff struct {
+	Name   string  `json:"name"`
+	Status string  `json:"status"`
+	Old    *string `json:"old"`
+	New    *string `json:"new"`
+}
+
+type AddTOTPRequest struct {
+	Name maybe.Maybe[string]
+	URI  string
+}
+
+type ExportTOTPRequest struct {
+	Name string
+}
+
+type ExportTOTPResponse struct {
+	URI string
+}
+
+type TOTPPasscodeRequest struct {
+	Name string
+}
+
+type TOTPPasscodeResponse struct {
+	Code string
+}
+
+type ImportTOTPQRRequest struct {
+	ImagePath string
+	Overwrite bool
+}
@@ -11,16 +11,7 @@ import (
 )
 
 func (a api) gostore(in input) (output, error) {
-	//nolint:gosec
-	c := exec.Command(
-		path.Join("..", "..", gostorePath),
-		in.args...,
-	)
-
-	c.Env = append(
-		os.Environ(),
-		fmt.Sprintf("GOSTORE_STORE_BASE_PATH=%s", a.basePath),
-	)
+	c := a.command(in)
 
 	stdout := &bytes.Buffer{}
 	stderr := &bytes.Buffer{}
@@ -47,6 +38,22 @@ func (a api) gostore(in input) (output, error) {
 	return o, err
 }
 
+func (a api) command(in input) *exec.Cmd {
+	//nolint:gosec
+	c := exec.Command(
+		path.Join("..", "..", gostorePath),
+		in.args...,
+	)
+
+	c.Env = append(
+		os.Environ(),
+		fmt.Sprintf("GOSTORE_STORE_BASE_PATH=%s", a.basePath),
+	)
+	c.Env = append(c.Env, a.env...)
+
+	return c
+}
+
 type input struct {
 	args  []string
 	stdin io.Reader
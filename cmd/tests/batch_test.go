@@ -0,0 +1,101 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/UsingCoding/gostore/cmd/tests/api"
+	"github.com/UsingCoding/gostore/internal/common/maybe"
+)
+
+func TestBatch(t *testing.T) {
+	for _, storageType := range []string{"git", "fs"} {
+		t.Run(storageType, func(t *testing.T) {
+			testBatch(t, storageType)
+		})
+	}
+}
+
+func testBatch(t *testing.T, storageType string) {
+	s, err := newSuite()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		s.cleanup()
+	})
+
+	err = s.gostore().Init(api.InitRequest{
+		ID:          "main",
+		StorageType: maybe.NewJust(storageType),
+	})
+	require.NoError(t, err)
+
+	str := func(s string) *string {
+		return &s
+	}
+
+	err = s.gostore().Add(api.AddRequest{
+		Path: "old",
+		Data: strings.NewReader("old"),
+	})
+	require.NoError(t, err)
+
+	err = s.gostore().Batch(api.BatchRequest{
+		Ops: []api.BatchOp{
+			{Op: "add", Path: "db", Key: str("user"), Value: str("admin")},
+			{Op: "add", Path: "db", Key: str("pass"), Value: str("secret")},
+			{Op: "import", Path: "app", Payload: map[string]string{"A": "1", "B": "2"}},
+			{Op: "copy", Src: "db", Dst: "db-copy"},
+			{Op: "move", Src: "old", Dst: "new"},
+			{Op: "remove", Path: "app", Key: str("B")},
+		},
+	})
+	require.NoError(t, err)
+
+	history, err := s.gostore().History(api.HistoryRequest{Path: "db"})
+	require.NoError(t, err)
+	require.Len(t, history.Revisions, 1)
+
+	for _, p := range []string{"db-copy", "app", "new"} {
+		h, err2 := s.gostore().History(api.HistoryRequest{Path: p})
+		require.NoError(t, err2)
+		require.Len(t, h.Revisions, 1, p)
+		require.Equal(t, history.Revisions[0].ID, h.Revisions[0].ID, p)
+	}
+
+	res, err := s.gostore().Get(api.ReadRequest{Path: "db-copy", Key: maybe.NewJust("pass")})
+	require.NoError(t, err)
+	require.Equal(t, "secret", string(res.Data))
+
+	t.Run("failed batch changes nothing", func(t *testing.T) {
+		err = s.gostore().Batch(api.BatchRequest{
+			Ops: []api.BatchOp{
+				{Op: "add", Path: "created", Value: str("value")},
+				{Op: "add", Path: "db", Key: str("pass"), Value: str("changed")},
+				{Op: "move", Src: "unknown", Dst: "other"},
+			},
+		})
+		require.Error(t, err)
+
+		res, err = s.gostore().Get(api.ReadRequest{Path: "db", Key: maybe.NewJust("pass")})
+		require.NoError(t, err)
+		require.Equal(t, "secret", string(res.Data))
+
+		list, err2 := s.gostore().List(api.ListRequest{})
+		require.NoError(t, err2)
+		require.Equal(t, []string{"app", "db", "db-copy", "new"}, nodeNames(list.Nodes))
+	})
+
+	t.Run("totp issuer added in one change", func(t *testing.T) {
+		err = s.gostore().AddTOTP(api.AddTOTPRequest{
+			Name: maybe.NewJust("github"),
+			URI:  "otpauth://totp/github?secret=JBSWY3DPEHPK3PXP",
+		})
+		require.NoError(t, err)
+
+		h, err2 := s.gostore().History(api.HistoryRequest{Path: "totp/github"})
+		require.NoError(t, err2)
+		require.Len(t, h.Revisions, 1)
+	})
+}
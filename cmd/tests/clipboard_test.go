@@ -0,0 +1,86 @@
+package tests
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/UsingCoding/gostore/cmd/tests/api"
+	"github.com/UsingCoding/gostore/internal/common/maybe"
+)
+
+func TestClipboard(t *testing.T) {
+	s, err := newSuite()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		s.cleanup()
+	})
+
+	// fake xclip keeps clipboard in file
+	clipboardFile := path.Join(s.basePath, "clipboard")
+	binDir := path.Join(s.basePath, "bin")
+	require.NoError(t, os.MkdirAll(binDir, 0o755))
+	require.NoError(t, os.WriteFile(path.Join(binDir, "xclip"), []byte(fmt.Sprintf(`#!/bin/sh
+if [ "$1" = "-in" ]; then
+	cat > %[1]s
+else
+	cat %[1]s
+fi
+`, clipboardFile)), 0o755))
+
+	gostore := s.gostore().WithEnv(
+		"PATH="+binDir+":"+os.Getenv("PATH"),
+		"WAYLAND_DISPLAY=",
+	)
+
+	err = gostore.Init(api.InitRequest{
+		ID: "main",
+	})
+	require.NoError(t, err)
+
+	err = gostore.Add(api.AddRequest{
+		Path: "site",
+		Key:  maybe.NewJust("password"),
+		Data: strings.NewReader("secret"),
+	})
+	require.NoError(t, err)
+
+	readClipboard := func() string {
+		data, err2 := os.ReadFile(clipboardFile)
+		require.NoError(t, err2)
+		return string(data)
+	}
+
+	t.Run("clears after timeout", func(t *testing.T) {
+		err = gostore.Clip(api.ClipRequest{
+			Path:    "site",
+			Key:     maybe.NewJust("password"),
+			Timeout: 500 * time.Millisecond,
+		})
+		require.NoError(t, err)
+		require.Equal(t, "secret", readClipboard())
+
+		require.Eventually(t, func() bool {
+			return readClipboard() == ""
+		}, 5*time.Second, 100*time.Millisecond)
+	})
+
+	t.Run("keeps changed clipboard", func(t *testing.T) {
+		err = gostore.Clip(api.ClipRequest{
+			Path:    "site",
+			Key:     maybe.NewJust("password"),
+			Timeout: 500 * time.Millisecond,
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, os.WriteFile(clipboardFile, []byte("other"), 0o600))
+
+		time.Sleep(1500 * time.Millisecond)
+		require.Equal(t, "other", readClipboard())
+	})
+}
@@ -0,0 +1,139 @@
+package tests
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/UsingCoding/gostore/cmd/tests/api"
+	"github.com/UsingCoding/gostore/internal/common/maybe"
+)
+
+func TestDiff(t *testing.T) {
+	s, err := newSuite()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		s.cleanup()
+	})
+
+	err = s.gostore().Init(api.InitRequest{
+		ID: "main",
+	})
+	require.NoError(t, err)
+
+	add := func(path, key, data string) {
+		err2 := s.gostore().Add(api.AddRequest{
+			Path: path,
+			Key:  maybe.NewJust(key),
+			Data: bytes.NewBufferString(data),
+		})
+		require.NoError(t, err2)
+	}
+
+	add("db/prod", "password", "p1")
+	add("db/prod", "user", "admin")
+	add("db/prod", "password", "p2")
+	// same value re-encrypted should not be shown as changed
+	add("db/prod", "user", "admin")
+	add("api", "token", "t1")
+
+	history, err := s.gostore().History(api.HistoryRequest{
+		Path: "db/prod",
+	})
+	require.NoError(t, err)
+	require.Len(t, history.Revisions, 4)
+
+	t.Run("diff between revisions hides values", func(t *testing.T) {
+		resp, err2 := s.gostore().Diff(api.DiffRequest{
+			Revisions: []string{history.Revisions[2].ID, "HEAD"},
+		})
+		require.NoError(t, err2)
+		require.Equal(t, []api.SecretDiff{
+			{
+				Path:   "api",
+				Status: "added",
+				Keys:   []api.KeyDiff{{Name: "token", Status: "added"}},
+			},
+			{
+				Path:   "db/prod",
+				Status: "changed",
+				Keys:   []api.KeyDiff{{Name: "password", Status: "changed"}},
+			},
+		}, resp.Secrets)
+	})
+
+	t.Run("diff path with values", func(t *testing.T) {
+		resp, err2 := s.gostore().Diff(api.DiffRequest{
+			Revisions:  []string{history.Revisions[3].ID},
+			Path:       maybe.NewJust("db"),
+			ShowValues: true,
+		})
+		require.NoError(t, err2)
+		require.Len(t, resp.Secrets, 1)
+
+		keys := resp.Secrets[0].Keys
+		require.Len(t, keys, 2)
+		require.Equal(t, "password", keys[0].Name)
+		require.Equal(t, "p1", *keys[0].Old)
+		require.Equal(t, "p2", *keys[0].New)
+		require.Equal(t, "user", keys[1].Name)
+		require.Equal(t, "added", keys[1].Status)
+		require.Equal(t, "admin", *keys[1].New)
+	})
+
+	t.Run("no uncommitted changes", func(t *testing.T) {
+		resp, err2 := s.gostore().Diff(api.DiffRequest{})
+		require.NoError(t, err2)
+		require.Empty(t, resp.Secrets)
+	})
+
+	t.Run("path colliding with revision prefix treated as path", func(t *testing.T) {
+		apiHistory, err2 := s.gostore().History(api.HistoryRequest{
+			Path: "api",
+		})
+		require.NoError(t, err2)
+		require.Len(t, apiHistory.Revisions, 1)
+
+		collision := apiHistory.Revisions[0].ID[:7]
+		add(collision, "key", "v")
+
+		resp, err2 := s.gostore().Diff(api.DiffRequest{
+			Revisions: []string{history.Revisions[0].ID},
+			Path:      maybe.NewJust(collision),
+		})
+		require.NoError(t, err2)
+		require.Equal(t, []api.SecretDiff{
+			{
+				Path:   collision,
+				Status: "added",
+				Keys:   []api.KeyDiff{{Name: "key", Status: "added"}},
+			},
+		}, resp.Secrets)
+
+		resp, err2 = s.gostore().Diff(api.DiffRequest{
+			Path: maybe.NewJust(collision),
+		})
+		require.NoError(t, err2)
+		require.Empty(t, resp.Secrets)
+	})
+
+	t.Run("values with format verbs", func(t *testing.T) {
+		const (
+			verbPath = "p%x"
+			verbData = "v%d100%s"
+		)
+		add(verbPath, "key", verbData)
+
+		resp, err2 := s.gostore().Diff(api.DiffRequest{
+			Revisions:  []string{"HEAD~1"},
+			Path:       maybe.NewJust(verbPath),
+			ShowValues: true,
+		})
+		require.NoError(t, err2)
+		require.Len(t, resp.Secrets, 1)
+		require.Equal(t, verbPath, resp.Secrets[0].Path)
+		require.Len(t, resp.Secrets[0].Keys, 1)
+		require.Equal(t, verbData, *resp.Secrets[0].Keys[0].New)
+	})
+}
@@ -0,0 +1,63 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/UsingCoding/gostore/cmd/tests/api"
+	"github.com/UsingCoding/gostore/internal/common/maybe"
+)
+
+func TestExec(t *testing.T) {
+	s, err := newSuite()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		s.cleanup()
+	})
+
+	err = s.gostore().Init(api.InitRequest{
+		ID: "main",
+	})
+	require.NoError(t, err)
+
+	for _, req := range []api.AddRequest{
+		{Path: "db/prod", Key: maybe.NewJust("pass"), Data: strings.NewReader("db-secret")},
+		{Path: "app/prod", Key: maybe.NewJust("api_token"), Data: strings.NewReader("token")},
+		{Path: "app/prod", Key: maybe.NewJust("log-level"), Data: strings.NewReader("debug")},
+		{Path: "github", Data: strings.NewReader("gh-token")},
+	} {
+		err = s.gostore().Add(req)
+		require.NoError(t, err)
+	}
+
+	res, err := s.gostore().Exec(api.ExecRequest{
+		Env: []string{
+			"DB_PASS=db/prod:pass",
+			"GH=github",
+		},
+		EnvFrom: []string{"app/prod"},
+		Command: []string{"sh", "-c", `printf '%s %s %s %s' "$DB_PASS" "$GH" "$API_TOKEN" "$LOG_LEVEL"`},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "db-secret gh-token token debug", res.Output)
+
+	t.Run("explicit var overrides env-from", func(t *testing.T) {
+		res, err = s.gostore().Exec(api.ExecRequest{
+			Env:     []string{"API_TOKEN=db/prod:pass"},
+			EnvFrom: []string{"app/prod"},
+			Command: []string{"sh", "-c", `printf '%s' "$API_TOKEN"`},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "db-secret", res.Output)
+	})
+
+	t.Run("composite secret without key", func(t *testing.T) {
+		_, err = s.gostore().Exec(api.ExecRequest{
+			Env:     []string{"DB=db/prod"},
+			Command: []string{"true"},
+		})
+		require.Error(t, err)
+	})
+}
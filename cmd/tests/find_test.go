@@ -0,0 +1,111 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/stretchr/testify/require"
+
+	"github.com/UsingCoding/gostore/cmd/tests/api"
+	"github.com/UsingCoding/gostore/internal/common/maybe"
+)
+
+func TestFind(t *testing.T) {
+	s, err := newSuite()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		s.cleanup()
+	})
+
+	err = s.gostore().Init(api.InitRequest{
+		ID: "main",
+	})
+	require.NoError(t, err)
+
+	for _, req := range []api.AddRequest{
+		{Path: "prod/db", Key: maybe.NewJust("password"), Data: strings.NewReader("secret-value")},
+		{Path: "prod/api", Key: maybe.NewJust("token"), Data: strings.NewReader("abc")},
+		{Path: "dev/db", Key: maybe.NewJust("user"), Data: strings.NewReader("admin")},
+	} {
+		err = s.gostore().Add(req)
+		require.NoError(t, err)
+	}
+
+	err = s.gostore().SetMetadata(api.SetMetadataRequest{
+		Path: "dev/db",
+		Tags: []string{"staging"},
+	})
+	require.NoError(t, err)
+
+	paths := func(res api.FindResponse) []string {
+		var p []string
+		for _, r := range res.Results {
+			p = append(p, r.Path)
+		}
+		return p
+	}
+
+	t.Run("path glob", func(t *testing.T) {
+		res, err2 := s.gostore().Find(api.FindRequest{Query: "prod/*"})
+		require.NoError(t, err2)
+		require.ElementsMatch(t, []string{"prod/db", "prod/api"}, paths(res))
+	})
+
+	t.Run("substring", func(t *testing.T) {
+		res, err2 := s.gostore().Find(api.FindRequest{Query: "db"})
+		require.NoError(t, err2)
+		require.ElementsMatch(t, []string{"prod/db", "dev/db"}, paths(res))
+	})
+
+	t.Run("key name", func(t *testing.T) {
+		res, err2 := s.gostore().Find(api.FindRequest{Query: "^tok", Regex: true})
+		require.NoError(t, err2)
+		require.Len(t, res.Results, 1)
+		require.Equal(t, "prod/api", res.Results[0].Path)
+		require.Equal(t, []string{"token"}, res.Results[0].Keys)
+	})
+
+	t.Run("tag", func(t *testing.T) {
+		res, err2 := s.gostore().Find(api.FindRequest{Query: "staging"})
+		require.NoError(t, err2)
+		require.Len(t, res.Results, 1)
+		require.Equal(t, "dev/db", res.Results[0].Path)
+		require.Equal(t, []string{"staging"}, res.Results[0].Tags)
+	})
+
+	t.Run("values", func(t *testing.T) {
+		res, err2 := s.gostore().Find(api.FindRequest{Query: "secret-value"})
+		require.NoError(t, err2)
+		require.Empty(t, res.Results)
+
+		res, err2 = s.gostore().Find(api.FindRequest{Query: "secret-value", Grep: true})
+		require.NoError(t, err2)
+		require.Len(t, res.Results, 1)
+		require.Equal(t, "prod/db", res.Results[0].Path)
+		require.Equal(t, []string{"password"}, res.Results[0].Values)
+	})
+	t.Run("values of inaccessible secrets not searched", func(t *testing.T) {
+		other, err2 := age.GenerateX25519Identity()
+		require.NoError(t, err2)
+
+		err2 = s.gostore().AddRecipients(api.RecipientsRequest{
+			Recipients: []string{other.Recipient().String()},
+			Path:       maybe.NewJust("prod"),
+		})
+		require.NoError(t, err2)
+
+		res, err2 := s.gostore().Find(api.FindRequest{Query: "admin", Grep: true})
+		require.NoError(t, err2)
+
+		found := map[string]api.FindResult{}
+		for _, r := range res.Results {
+			found[r.Path] = r
+		}
+		require.ElementsMatch(t, []string{"prod/db", "prod/api", "dev/db"}, paths(res))
+		require.True(t, found["prod/db"].NoAccess)
+		require.True(t, found["prod/api"].NoAccess)
+		require.False(t, found["dev/db"].NoAccess)
+		require.Equal(t, []string{"user"}, found["dev/db"].Values)
+	})
+}
@@ -0,0 +1,151 @@
+package tests
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/UsingCoding/gostore/cmd/tests/api"
+	"github.com/UsingCoding/gostore/internal/common/maybe"
+)
+
+func TestFSStorage(t *testing.T) {
+	s, err := newSuite()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		s.cleanup()
+	})
+
+	err = s.gostore().Init(api.InitRequest{
+		ID:          "main",
+		StorageType: maybe.NewJust("fs"),
+	})
+	require.NoError(t, err)
+
+	const (
+		path = "secret"
+	)
+
+	for _, v := range []string{"v1", "v2"} {
+		err = s.gostore().Add(api.AddRequest{
+			Path: path,
+			Data: bytes.NewBufferString(v),
+		})
+		require.NoError(t, err)
+	}
+
+	resp, err := s.gostore().Get(api.ReadRequest{
+		Path: path,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "v2", string(resp.Data))
+
+	history, err := s.gostore().History(api.HistoryRequest{
+		Path: path,
+	})
+	require.NoError(t, err)
+	require.Len(t, history.Revisions, 2)
+
+	resp, err = s.gostore().Get(api.ReadRequest{
+		Path: path,
+		At:   maybe.NewJust(history.Revisions[1].ID),
+	})
+	require.NoError(t, err)
+	require.Equal(t, "v1", string(resp.Data))
+
+	err = s.gostore().Copy(api.CopyRequest{
+		Src: path,
+		Dst: "copy",
+	})
+	require.NoError(t, err)
+
+	err = s.gostore().Remove(api.RemoveRequest{
+		Path: path,
+	})
+	require.NoError(t, err)
+
+	list, err := s.gostore().List(api.ListRequest{})
+	require.NoError(t, err)
+	require.Len(t, list.Nodes, 1)
+	require.Equal(t, "copy", list.Nodes[0].Name)
+
+	err = s.gostore().Restore(api.RestoreRequest{
+		Path: path,
+		To:   history.Revisions[0].ID,
+	})
+	require.NoError(t, err)
+
+	resp, err = s.gostore().Get(api.ReadRequest{
+		Path: path,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "v2", string(resp.Data))
+
+	t.Run("path not resolved as revision", func(t *testing.T) {
+		_, err2 := s.gostore().Diff(api.DiffRequest{
+			Revisions: []string{history.Revisions[0].ID},
+			Path:      maybe.NewJust("db/prod"),
+		})
+		require.NoError(t, err2)
+
+		// path outside of journal is not looked up as revision
+		_, err2 = s.gostore().Diff(api.DiffRequest{
+			Revisions: []string{history.Revisions[0].ID},
+			Path:      maybe.NewJust("../secret"),
+		})
+		require.Error(t, err2)
+		require.Contains(t, err2.Error(), "not local")
+	})
+
+	t.Run("pack reads last committed secrets", func(t *testing.T) {
+		copyHistory, err2 := s.gostore().History(api.HistoryRequest{Path: "copy"})
+		require.NoError(t, err2)
+		secretHistory, err2 := s.gostore().History(api.HistoryRequest{Path: path})
+		require.NoError(t, err2)
+
+		err2 = s.gostore().Unpack()
+		require.NoError(t, err2)
+
+		unpacked, err2 := os.ReadFile(filepath.Join(s.basePath, "main", "copy"))
+		require.NoError(t, err2)
+		require.Equal(t, "v2", string(unpacked))
+
+		err2 = os.WriteFile(filepath.Join(s.basePath, "main", "copy"), []byte("v3"), 0o600)
+		require.NoError(t, err2)
+
+		err2 = s.gostore().Pack()
+		require.NoError(t, err2)
+
+		resp2, err2 := s.gostore().Get(api.ReadRequest{Path: "copy"})
+		require.NoError(t, err2)
+		require.Equal(t, "v3", string(resp2.Data))
+
+		h, err2 := s.gostore().History(api.HistoryRequest{Path: "copy"})
+		require.NoError(t, err2)
+		require.Len(t, h.Revisions, len(copyHistory.Revisions)+1)
+
+		// unchanged secret keeps committed value
+		h, err2 = s.gostore().History(api.HistoryRequest{Path: path})
+		require.NoError(t, err2)
+		require.Equal(t, secretHistory.Revisions, h.Revisions)
+	})
+
+	t.Run("rollback discards uncommitted changes", func(t *testing.T) {
+		err2 := s.gostore().Unpack()
+		require.NoError(t, err2)
+
+		err2 = s.gostore().Rollback()
+		require.NoError(t, err2)
+
+		packed, err2 := os.ReadFile(filepath.Join(s.basePath, "main", "copy"))
+		require.NoError(t, err2)
+		require.NotEqual(t, "v3", string(packed))
+
+		resp2, err2 := s.gostore().Get(api.ReadRequest{Path: "copy"})
+		require.NoError(t, err2)
+		require.Equal(t, "v3", string(resp2.Data))
+	})
+}
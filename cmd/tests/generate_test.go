@@ -0,0 +1,116 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/UsingCoding/gostore/cmd/tests/api"
+	"github.com/UsingCoding/gostore/internal/common/maybe"
+)
+
+func TestGenerate(t *testing.T) {
+	s, err := newSuite()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		s.cleanup()
+	})
+
+	err = s.gostore().Init(api.InitRequest{
+		ID: "main",
+	})
+	require.NoError(t, err)
+
+	t.Run("password", func(t *testing.T) {
+		res, err2 := s.gostore().Generate(api.GenerateRequest{
+			Path:   "site",
+			Length: maybe.NewJust(32),
+		})
+		require.NoError(t, err2)
+		require.Len(t, res.Password, 32)
+
+		stored, err2 := s.gostore().Get(api.ReadRequest{
+			Path: "site",
+		})
+		require.NoError(t, err2)
+		require.Equal(t, res.Password, string(stored.Data))
+	})
+
+	t.Run("charset", func(t *testing.T) {
+		res, err2 := s.gostore().Generate(api.GenerateRequest{
+			Path:    "pin",
+			Key:     maybe.NewJust("code"),
+			Length:  maybe.NewJust(6),
+			Charset: maybe.NewJust("0123456789"),
+		})
+		require.NoError(t, err2)
+		require.Len(t, res.Password, 6)
+		require.Empty(t, strings.Trim(res.Password, "0123456789"))
+	})
+
+	t.Run("passphrase", func(t *testing.T) {
+		res, err2 := s.gostore().Generate(api.GenerateRequest{
+			Path:  "disk",
+			Words: maybe.NewJust(5),
+		})
+		require.NoError(t, err2)
+		require.Len(t, strings.Split(res.Password, "-"), 5)
+
+		err2 = s.gostore().SetPasswordPolicy(api.PasswordPolicyRequest{
+			Words: maybe.NewJust(4),
+		})
+		require.NoError(t, err2)
+
+		res, err2 = s.gostore().Generate(api.GenerateRequest{
+			Path:       "disk",
+			Passphrase: true,
+		})
+		require.NoError(t, err2)
+		require.Len(t, strings.Split(res.Password, "-"), 4)
+	})
+
+	t.Run("edit with generated password", func(t *testing.T) {
+		// editor closed without changes keeps generated password
+		err2 := s.gostore().
+			WithEnv("EDITOR=true").
+			EditGenerated(api.EditGeneratedRequest{
+				Path:   "site",
+				Key:    maybe.NewJust("admin"),
+				Length: maybe.NewJust(20),
+			})
+		require.NoError(t, err2)
+
+		stored, err2 := s.gostore().Get(api.ReadRequest{
+			Path: "site",
+			Key:  maybe.NewJust("admin"),
+		})
+		require.NoError(t, err2)
+		require.Len(t, stored.Data, 20)
+	})
+
+	t.Run("policy", func(t *testing.T) {
+		err2 := s.gostore().SetPasswordPolicy(api.PasswordPolicyRequest{
+			Length:           maybe.NewJust(12),
+			Required:         []string{"digits", "symbols"},
+			ExcludeAmbiguous: true,
+		})
+		require.NoError(t, err2)
+
+		for range 20 {
+			res, err3 := s.gostore().Generate(api.GenerateRequest{
+				Path: "policy",
+			})
+			require.NoError(t, err3)
+			require.Len(t, res.Password, 12)
+			require.True(t, strings.ContainsAny(res.Password, "0123456789"))
+			require.True(t, strings.ContainsAny(res.Password, "!#$%&()*+,-./:;<=>?@[\\]^_{}~"))
+			require.False(t, strings.ContainsAny(res.Password, "0Oo1lI"))
+		}
+
+		err2 = s.gostore().SetPasswordPolicy(api.PasswordPolicyRequest{
+			Required: []string{"unknown"},
+		})
+		require.Error(t, err2)
+	})
+}
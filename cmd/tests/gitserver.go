@@ -0,0 +1,177 @@
+package tests
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"io"
+	"net"
+	"net/http"
+	"net/http/cgi"
+	"net/http/httptest"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+// serveGitHTTP serves bare repositories from root over smart HTTP with basic auth checked by authorized
+func serveGitHTTP(t *testing.T, root string, authorized func(user, password string) bool) string {
+	t.Helper()
+
+	gitPath, err := exec.LookPath("git")
+	require.NoError(t, err)
+
+	backend := &cgi.Handler{
+		Path: gitPath,
+		Args: []string{"http-backend"},
+		Env: []string{
+			"GIT_PROJECT_ROOT=" + root,
+			"GIT_HTTP_EXPORT_ALL=1",
+			// enables receive-pack for authenticated user
+			"REMOTE_USER=gostore",
+		},
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		if !ok || !authorized(user, password) {
+			w.Header().Set("WWW-Authenticate", `Basic realm="git"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		backend.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv.URL
+}
+
+// serveGitSSH serves bare repositories by absolute path over SSH for client with authorized key
+func serveGitSSH(t *testing.T, authorized ssh.PublicKey) (addr string, hostKey ssh.PublicKey) {
+	t.Helper()
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	signer, err := ssh.NewSignerFromKey(key)
+	require.NoError(t, err)
+
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(_ ssh.ConnMetadata, k ssh.PublicKey) (*ssh.Permissions, error) {
+			if !bytes.Equal(k.Marshal(), authorized.Marshal()) {
+				return nil, errors.New("unknown public key")
+			}
+			return nil, nil
+		},
+	}
+	config.AddHostKey(signer)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = l.Close()
+	})
+
+	go func() {
+		for {
+			conn, err2 := l.Accept()
+			if err2 != nil {
+				return
+			}
+			go handleSSHConn(conn, config)
+		}
+	}()
+
+	return l.Addr().String(), signer.PublicKey()
+}
+
+func handleSSHConn(conn net.Conn, config *ssh.ServerConfig) {
+	defer conn.Close()
+
+	_, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+
+	for newChan := range chans {
+		if newChan.ChannelType() != "session" {
+			_ = newChan.Reject(ssh.UnknownChannelType, "unknown channel type")
+			continue
+		}
+
+		ch, chReqs, err2 := newChan.Accept()
+		if err2 != nil {
+			return
+		}
+		go handleGitSession(ch, chReqs)
+	}
+}
+
+// handleGitSession runs git-upload-pack or git-receive-pack requested by client
+func handleGitSession(ch ssh.Channel, reqs <-chan *ssh.Request) {
+	defer ch.Close()
+
+	for req := range reqs {
+		if req.Type != "exec" {
+			_ = req.Reply(false, nil)
+			continue
+		}
+
+		var payload struct {
+			Command string
+		}
+		err := ssh.Unmarshal(req.Payload, &payload)
+		if err != nil {
+			_ = req.Reply(false, nil)
+			continue
+		}
+
+		// command in form: git-upload-pack '/path/to/repo.git'
+		service, repo, ok := strings.Cut(payload.Command, " ")
+		if !ok || (service != "git-upload-pack" && service != "git-receive-pack") {
+			_ = req.Reply(false, nil)
+			continue
+		}
+		_ = req.Reply(true, nil)
+
+		var status uint32
+		if runGitService(ch, strings.TrimPrefix(service, "git-"), strings.Trim(repo, "'")) != nil {
+			status = 1
+		}
+
+		_, _ = ch.SendRequest("exit-status", false, ssh.Marshal(struct {
+			Status uint32
+		}{Status: status}))
+		return
+	}
+}
+
+func runGitService(ch ssh.Channel, service, repo string) error {
+	cmd := exec.Command("git", service, repo)
+	cmd.Stdout = ch
+	cmd.Stderr = ch.Stderr()
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+
+	err = cmd.Start()
+	if err != nil {
+		return err
+	}
+
+	// client keeps channel open until exit status received, so do not wait for stdin copy
+	go func() {
+		_, _ = io.Copy(stdin, ch)
+		_ = stdin.Close()
+	}()
+
+	return cmd.Wait()
+}
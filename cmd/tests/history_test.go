@@ -0,0 +1,113 @@
+package tests
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/UsingCoding/gostore/cmd/tests/api"
+	"github.com/UsingCoding/gostore/internal/common/maybe"
+)
+
+func TestSecretHistory(t *testing.T) {
+	s, err := newSuite()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		s.cleanup()
+	})
+
+	err = s.gostore().Init(api.InitRequest{
+		ID: "main",
+	})
+	require.NoError(t, err)
+
+	const (
+		path = "secret"
+		v1   = "v1"
+		v2   = "v2"
+	)
+
+	for _, v := range []string{v1, v2} {
+		err = s.gostore().Add(api.AddRequest{
+			Path: path,
+			Data: bytes.NewBufferString(v),
+		})
+		require.NoError(t, err)
+	}
+
+	err = s.gostore().Remove(api.RemoveRequest{
+		Path: path,
+	})
+	require.NoError(t, err)
+
+	history, err := s.gostore().History(api.HistoryRequest{
+		Path: path,
+	})
+	require.NoError(t, err)
+	require.Len(t, history.Revisions, 3)
+	require.Equal(t, "Remove secret", history.Revisions[0].Message)
+
+	t.Run("get at revision", func(t *testing.T) {
+		for i, expected := range map[int]string{1: v2, 2: v1} {
+			resp, err2 := s.gostore().Get(api.ReadRequest{
+				Path: path,
+				At:   maybe.NewJust(history.Revisions[i].ID),
+			})
+			require.NoError(t, err2)
+			require.Equal(t, expected, string(resp.Data))
+		}
+	})
+
+	t.Run("get at revision where secret removed", func(t *testing.T) {
+		_, err2 := s.gostore().Get(api.ReadRequest{
+			Path: path,
+			At:   maybe.NewJust(history.Revisions[0].ID),
+		})
+		require.Error(t, err2)
+	})
+
+	t.Run("restore removed secret", func(t *testing.T) {
+		err2 := s.gostore().Restore(api.RestoreRequest{
+			Path: path,
+			To:   history.Revisions[2].ID,
+		})
+		require.NoError(t, err2)
+
+		resp, err2 := s.gostore().Get(api.ReadRequest{
+			Path: path,
+		})
+		require.NoError(t, err2)
+		require.Equal(t, v1, string(resp.Data))
+
+		h, err2 := s.gostore().History(api.HistoryRequest{
+			Path: path,
+		})
+		require.NoError(t, err2)
+		require.Len(t, h.Revisions, 4)
+	})
+
+	t.Run("restore to revision where secret removed", func(t *testing.T) {
+		err2 := s.gostore().Restore(api.RestoreRequest{
+			Path: path,
+			To:   history.Revisions[0].ID,
+		})
+		require.Error(t, err2)
+	})
+
+	t.Run("path with format verbs", func(t *testing.T) {
+		const verbPath = "p%x"
+		err2 := s.gostore().Add(api.AddRequest{
+			Path: verbPath,
+			Data: bytes.NewBufferString(v1),
+		})
+		require.NoError(t, err2)
+
+		h, err2 := s.gostore().History(api.HistoryRequest{
+			Path: verbPath,
+		})
+		require.NoError(t, err2)
+		require.Len(t, h.Revisions, 1)
+		require.Equal(t, "Add p%x", h.Revisions[0].Message)
+	})
+}
@@ -0,0 +1,73 @@
+package tests
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/UsingCoding/gostore/cmd/tests/api"
+)
+
+func TestProtectIdentities(t *testing.T) {
+	s, err := newSuite()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		s.cleanup()
+	})
+
+	err = s.gostore().Init(api.InitRequest{
+		ID: "main",
+	})
+	require.NoError(t, err)
+
+	const (
+		path       = "secret"
+		data       = "data"
+		passphrase = "correct horse battery staple"
+	)
+
+	err = s.gostore().Add(api.AddRequest{
+		Path: path,
+		Data: bytes.NewBufferString(data),
+	})
+	require.NoError(t, err)
+
+	err = s.gostore().ProtectIdentities(api.ProtectIdentitiesRequest{
+		Passphrase: passphrase,
+	})
+	require.NoError(t, err)
+
+	t.Run("no passphrase", func(t *testing.T) {
+		_, err2 := s.gostore().Get(api.ReadRequest{
+			Path: path,
+		})
+		require.Error(t, err2)
+	})
+
+	t.Run("invalid passphrase", func(t *testing.T) {
+		_, err2 := s.gostore().
+			WithEnv("GOSTORE_PASSPHRASE=invalid").
+			Get(api.ReadRequest{
+				Path: path,
+			})
+		require.Error(t, err2)
+	})
+
+	t.Run("valid passphrase", func(t *testing.T) {
+		resp, err2 := s.gostore().
+			WithEnv("GOSTORE_PASSPHRASE="+passphrase).
+			Get(api.ReadRequest{
+				Path: path,
+			})
+		require.NoError(t, err2)
+		require.Equal(t, data, string(resp.Data))
+	})
+
+	t.Run("protect already protected", func(t *testing.T) {
+		err2 := s.gostore().ProtectIdentities(api.ProtectIdentitiesRequest{
+			Passphrase: passphrase,
+		})
+		require.Error(t, err2)
+	})
+}
@@ -0,0 +1,155 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/UsingCoding/gostore/cmd/tests/api"
+	"github.com/UsingCoding/gostore/internal/common/maybe"
+)
+
+func TestImport(t *testing.T) {
+	s, err := newSuite()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		s.cleanup()
+	})
+
+	err = s.gostore().Init(api.InitRequest{
+		ID: "main",
+	})
+	require.NoError(t, err)
+
+	const dotenv = `# app config
+export DB_HOST=localhost
+DB_PASS='p@ss #1'
+GREETING="hello\nworld"
+EMPTY=
+PORT=5432 # inline comment
+`
+
+	err = s.gostore().Import(api.ImportRequest{
+		Path:   "app/prod",
+		Format: "dotenv",
+		Data:   strings.NewReader(dotenv),
+	})
+	require.NoError(t, err)
+
+	history, err := s.gostore().History(api.HistoryRequest{Path: "app/prod"})
+	require.NoError(t, err)
+	require.Len(t, history.Revisions, 1)
+
+	for key, expected := range map[string]string{
+		"DB_HOST":  "localhost",
+		"DB_PASS":  "p@ss #1",
+		"GREETING": "hello\nworld",
+		"EMPTY":    "",
+		"PORT":     "5432",
+	} {
+		res, err2 := s.gostore().Get(api.ReadRequest{
+			Path: "app/prod",
+			Key:  maybe.NewJust(key),
+		})
+		require.NoError(t, err2)
+		require.Equal(t, expected, string(res.Data), key)
+	}
+
+	t.Run("export dotenv", func(t *testing.T) {
+		res, err2 := s.gostore().Get(api.ReadRequest{
+			Path:   "app/prod",
+			Format: maybe.NewJust("dotenv"),
+		})
+		require.NoError(t, err2)
+		require.Equal(t, `DB_HOST=localhost
+DB_PASS='p@ss #1'
+EMPTY=
+GREETING="hello\nworld"
+PORT=5432
+`, string(res.Data))
+
+		// exported document imports back to same secret
+		err2 = s.gostore().Import(api.ImportRequest{
+			Path:   "app/copy",
+			Format: "dotenv",
+			Data:   strings.NewReader(string(res.Data)),
+		})
+		require.NoError(t, err2)
+
+		copied, err2 := s.gostore().Get(api.ReadRequest{
+			Path:   "app/copy",
+			Format: maybe.NewJust("dotenv"),
+		})
+		require.NoError(t, err2)
+		require.Equal(t, string(res.Data), string(copied.Data))
+	})
+
+	t.Run("json and yaml", func(t *testing.T) {
+		err2 := s.gostore().Import(api.ImportRequest{
+			Path:   "svc",
+			Format: "json",
+			Data:   strings.NewReader(`{"token": "abc", "port": 8080, "debug": true}`),
+		})
+		require.NoError(t, err2)
+
+		err2 = s.gostore().Import(api.ImportRequest{
+			Path:    "svc",
+			Format:  "yaml",
+			Replace: true,
+			Data:    strings.NewReader("token: xyz\nuser: admin\n"),
+		})
+		require.NoError(t, err2)
+
+		res, err2 := s.gostore().Get(api.ReadRequest{
+			Path:   "svc",
+			Format: maybe.NewJust("json"),
+		})
+		require.NoError(t, err2)
+		require.JSONEq(t, `{"token": "xyz", "user": "admin"}`, string(res.Data))
+	})
+	t.Run("values kept as written", func(t *testing.T) {
+		expected := map[string]string{
+			"big":     "12345678901234567890123",
+			"code":    "0123",
+			"version": "1.10",
+			"created": "2024-01-02T03:04:05Z",
+			"nested":  `{"port":8080,"ratio":1.10}`,
+		}
+
+		for format, doc := range map[string]string{
+			"json": `{
+  "big": 12345678901234567890123,
+  "code": "0123",
+  "version": 1.10,
+  "created": "2024-01-02T03:04:05Z",
+  "nested": {"port": 8080, "ratio": 1.10}
+}`,
+			"yaml": `big: 12345678901234567890123
+code: 0123
+version: 1.10
+created: 2024-01-02T03:04:05Z
+nested:
+  port: 8080
+  ratio: 1.10
+`,
+		} {
+			p := "numbers/" + format
+			err2 := s.gostore().Import(api.ImportRequest{
+				Path:   p,
+				Format: format,
+				Data:   strings.NewReader(doc),
+			})
+			require.NoError(t, err2)
+
+			for key, value := range expected {
+				res, err3 := s.gostore().Get(api.ReadRequest{
+					Path: p,
+					Key:  maybe.NewJust(key),
+				})
+				require.NoError(t, err3)
+				require.Equal(t, value, string(res.Data), "%s: %s", format, key)
+			}
+		}
+	})
+}
@@ -0,0 +1,79 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/UsingCoding/gostore/cmd/tests/api"
+	"github.com/UsingCoding/gostore/internal/common/maybe"
+)
+
+func TestMetadata(t *testing.T) {
+	s, err := newSuite()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		s.cleanup()
+	})
+
+	err = s.gostore().Init(api.InitRequest{
+		ID: "main",
+	})
+	require.NoError(t, err)
+
+	err = s.gostore().Add(api.AddRequest{
+		Path: "db",
+		Data: strings.NewReader("password"),
+	})
+	require.NoError(t, err)
+
+	m, err := s.gostore().Metadata(api.MetadataRequest{
+		Path: "db",
+	})
+	require.NoError(t, err)
+	require.NotNil(t, m.Created)
+	require.NotNil(t, m.Updated)
+	require.NotEmpty(t, m.Author)
+	created := *m.Created
+
+	err = s.gostore().SetMetadata(api.SetMetadataRequest{
+		Path:        "db",
+		Tags:        []string{"prod", "postgres"},
+		Description: maybe.NewJust("Production database"),
+	})
+	require.NoError(t, err)
+
+	// ensure updated time changes
+	time.Sleep(10 * time.Millisecond)
+
+	err = s.gostore().Add(api.AddRequest{
+		Path: "db",
+		Key:  maybe.NewJust("user"),
+		Data: strings.NewReader("admin"),
+	})
+	require.NoError(t, err)
+
+	m, err = s.gostore().Metadata(api.MetadataRequest{
+		Path: "db",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"prod", "postgres"}, m.Tags)
+	require.Equal(t, "Production database", m.Description)
+	require.True(t, created.Equal(*m.Created))
+	require.True(t, m.Updated.After(created))
+
+	// metadata follows secret on move
+	err = s.gostore().Move(api.MoveRequest{
+		Src: "db",
+		Dst: "postgres",
+	})
+	require.NoError(t, err)
+
+	m, err = s.gostore().Metadata(api.MetadataRequest{
+		Path: "postgres",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"prod", "postgres"}, m.Tags)
+}
@@ -0,0 +1,213 @@
+package tests
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/stretchr/testify/require"
+
+	"github.com/UsingCoding/gostore/cmd/tests/api"
+	"github.com/UsingCoding/gostore/internal/common/maybe"
+)
+
+func TestMigrateEncryption(t *testing.T) {
+	s, err := newSuite()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		s.cleanup()
+	})
+
+	err = s.gostore().Init(api.InitRequest{
+		ID: "main",
+	})
+	require.NoError(t, err)
+
+	secrets := map[string]string{
+		"db/user": "admin",
+		"db/pass": "1234",
+		"token":   "abc",
+	}
+	for p, v := range secrets {
+		err = s.gostore().Add(api.AddRequest{
+			Path: p,
+			Data: bytes.NewBufferString(v),
+		})
+		require.NoError(t, err)
+	}
+
+	publicKey := importPGPIdentity(t, s)
+
+	initial, err := s.gostore().ListRecipients()
+	require.NoError(t, err)
+
+	t.Run("failed migration changes nothing", func(t *testing.T) {
+		err2 := s.gostore().MigrateEncryption(api.MigrateEncryptionRequest{
+			Encryption: "pgp",
+			Recipients: []string{"not a key"},
+		})
+		require.Error(t, err2)
+
+		err2 = s.gostore().MigrateEncryption(api.MigrateEncryptionRequest{
+			Encryption: "age",
+			Recipients: initial.Recipients,
+		})
+		require.Error(t, err2)
+
+		recipients, err2 := s.gostore().ListRecipients()
+		require.NoError(t, err2)
+		require.Equal(t, initial.Recipients, recipients.Recipients)
+
+		history, err2 := s.gostore().History(api.HistoryRequest{Path: "token"})
+		require.NoError(t, err2)
+		require.Len(t, history.Revisions, 1)
+	})
+
+	t.Run("migrate to pgp", func(t *testing.T) {
+		err2 := s.gostore().MigrateEncryption(api.MigrateEncryptionRequest{
+			Encryption: "pgp",
+			Recipients: []string{publicKey},
+		})
+		require.NoError(t, err2)
+
+		recipients, err2 := s.gostore().ListRecipients()
+		require.NoError(t, err2)
+		require.Len(t, recipients.Recipients, 1)
+		require.NotEqual(t, initial.Recipients, recipients.Recipients)
+
+		for p, v := range secrets {
+			resp, err3 := s.gostore().Get(api.ReadRequest{Path: p})
+			require.NoError(t, err3)
+			require.Equal(t, v, string(resp.Data))
+		}
+	})
+
+	t.Run("migrate back to age", func(t *testing.T) {
+		err2 := s.gostore().MigrateEncryption(api.MigrateEncryptionRequest{
+			Encryption: "age",
+			Recipients: initial.Recipients,
+		})
+		require.NoError(t, err2)
+
+		for p, v := range secrets {
+			resp, err3 := s.gostore().Get(api.ReadRequest{Path: p})
+			require.NoError(t, err3)
+			require.Equal(t, v, string(resp.Data))
+		}
+	})
+}
+
+func TestMigrateEncryptionScoped(t *testing.T) {
+	s, err := newSuite()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		s.cleanup()
+	})
+
+	err = s.gostore().Init(api.InitRequest{
+		ID: "main",
+	})
+	require.NoError(t, err)
+
+	secrets := map[string]string{
+		"prod/db": "1234",
+		"token":   "abc",
+	}
+	for p, v := range secrets {
+		err = s.gostore().Add(api.AddRequest{
+			Path: p,
+			Data: bytes.NewBufferString(v),
+		})
+		require.NoError(t, err)
+	}
+
+	initial, err := s.gostore().ListRecipients()
+	require.NoError(t, err)
+
+	err = s.gostore().AddRecipients(api.RecipientsRequest{
+		Recipients: initial.Recipients,
+		Path:       maybe.NewJust("prod"),
+	})
+	require.NoError(t, err)
+
+	publicKey := importPGPIdentity(t, s)
+
+	t.Run("scope recipients required", func(t *testing.T) {
+		err2 := s.gostore().MigrateEncryption(api.MigrateEncryptionRequest{
+			Encryption: "pgp",
+			Recipients: []string{publicKey},
+		})
+		require.Error(t, err2)
+
+		err2 = s.gostore().MigrateEncryption(api.MigrateEncryptionRequest{
+			Encryption: "pgp",
+			Recipients: []string{publicKey},
+			ScopeRecipients: map[string][]string{
+				"prod":  {publicKey},
+				"stage": {publicKey},
+			},
+		})
+		require.Error(t, err2)
+
+		scopes, err2 := s.gostore().ListScopes()
+		require.NoError(t, err2)
+		require.Len(t, scopes, 1)
+		require.Equal(t, initial.Recipients, scopes[0].Recipients)
+	})
+
+	t.Run("migrate scopes", func(t *testing.T) {
+		err2 := s.gostore().MigrateEncryption(api.MigrateEncryptionRequest{
+			Encryption: "pgp",
+			Recipients: []string{publicKey},
+			ScopeRecipients: map[string][]string{
+				"prod": {publicKey},
+			},
+		})
+		require.NoError(t, err2)
+
+		recipients, err2 := s.gostore().ListRecipients()
+		require.NoError(t, err2)
+
+		scopes, err2 := s.gostore().ListScopes()
+		require.NoError(t, err2)
+		require.Len(t, scopes, 1)
+		require.Equal(t, "prod", scopes[0].Path)
+		require.Equal(t, recipients.Recipients, scopes[0].Recipients)
+		require.True(t, scopes[0].Accessible)
+
+		for p, v := range secrets {
+			resp, err3 := s.gostore().Get(api.ReadRequest{Path: p})
+			require.NoError(t, err3)
+			require.Equal(t, v, string(resp.Data))
+		}
+	})
+}
+
+// importPGPIdentity generates pgp key, imports it as identity and returns armored public key
+func importPGPIdentity(t *testing.T, s suite) string {
+	t.Helper()
+
+	entity, err := openpgp.NewEntity("me", "", "me@example.com", nil)
+	require.NoError(t, err)
+
+	var privateKey bytes.Buffer
+	w, err := armor.Encode(&privateKey, openpgp.PrivateKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.SerializePrivateWithoutSigning(w, nil))
+	require.NoError(t, w.Close())
+
+	var publicKey bytes.Buffer
+	w, err = armor.Encode(&publicKey, openpgp.PublicKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.Serialize(w))
+	require.NoError(t, w.Close())
+
+	err = s.gostore().ImportIdentity(api.ImportIdentityRequest{
+		Provider: "pgp",
+		Data:     bytes.NewReader(privateKey.Bytes()),
+	})
+	require.NoError(t, err)
+
+	return publicKey.String()
+}
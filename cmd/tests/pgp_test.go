@@ -0,0 +1,114 @@
+package tests
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/stretchr/testify/require"
+
+	"github.com/UsingCoding/gostore/cmd/tests/api"
+	"github.com/UsingCoding/gostore/internal/common/maybe"
+)
+
+func TestPGPEncryption(t *testing.T) {
+	s, err := newSuite()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		s.cleanup()
+	})
+
+	err = s.gostore().Init(api.InitRequest{
+		ID:         "main",
+		Encryption: maybe.NewJust("pgp"),
+	})
+	require.NoError(t, err)
+
+	const (
+		path       = "team/secret"
+		data       = "data"
+		passphrase = "pgp-passphrase"
+	)
+
+	t.Run("generated identity", func(t *testing.T) {
+		err2 := s.gostore().Add(api.AddRequest{
+			Path: path,
+			Data: bytes.NewBufferString(data),
+		})
+		require.NoError(t, err2)
+
+		resp, err2 := s.gostore().Get(api.ReadRequest{Path: path})
+		require.NoError(t, err2)
+		require.Equal(t, data, string(resp.Data))
+	})
+
+	initial, err := s.gostore().ListRecipients()
+	require.NoError(t, err)
+	require.Len(t, initial.Recipients, 1)
+
+	entity, err := openpgp.NewEntity("teammate", "", "teammate@example.com", nil)
+	require.NoError(t, err)
+
+	// public key armored with headers as exported by other tools
+	var publicKey bytes.Buffer
+	w, err := armor.Encode(&publicKey, openpgp.PublicKeyType, map[string]string{"Comment": "teammate"})
+	require.NoError(t, err)
+	require.NoError(t, entity.Serialize(w))
+	require.NoError(t, w.Close())
+
+	require.NoError(t, entity.EncryptPrivateKeys([]byte(passphrase), nil))
+
+	var privateKey bytes.Buffer
+	w, err = armor.Encode(&privateKey, openpgp.PrivateKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.SerializePrivateWithoutSigning(w, nil))
+	require.NoError(t, w.Close())
+
+	t.Run("add pgp recipient", func(t *testing.T) {
+		err2 := s.gostore().AddRecipients(api.RecipientsRequest{
+			Recipients: []string{publicKey.String()},
+		})
+		require.NoError(t, err2)
+
+		list, err2 := s.gostore().ListRecipients()
+		require.NoError(t, err2)
+		require.Len(t, list.Recipients, 2)
+
+		err2 = s.gostore().AddRecipients(api.RecipientsRequest{
+			Recipients: []string{"not a key"},
+		})
+		require.Error(t, err2)
+	})
+
+	t.Run("import protected pgp key", func(t *testing.T) {
+		err2 := s.gostore().
+			WithEnv("GOSTORE_PASSPHRASE=invalid").
+			ImportIdentity(api.ImportIdentityRequest{
+				Provider: "pgp",
+				Data:     bytes.NewReader(privateKey.Bytes()),
+			})
+		require.Error(t, err2)
+
+		err2 = s.gostore().
+			WithEnv("GOSTORE_PASSPHRASE=" + passphrase).
+			ImportIdentity(api.ImportIdentityRequest{
+				Provider: "pgp",
+				Data:     bytes.NewReader(privateKey.Bytes()),
+			})
+		require.NoError(t, err2)
+	})
+
+	t.Run("decrypt with imported identity", func(t *testing.T) {
+		gostore := s.gostore().WithEnv("GOSTORE_PASSPHRASE=" + passphrase)
+
+		err2 := gostore.RemoveRecipients(api.RecipientsRequest{
+			Recipients: initial.Recipients,
+		})
+		require.NoError(t, err2)
+
+		resp, err2 := gostore.Get(api.ReadRequest{Path: path})
+		require.NoError(t, err2)
+		require.Equal(t, data, string(resp.Data))
+	})
+}
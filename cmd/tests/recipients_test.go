@@ -0,0 +1,90 @@
+package tests
+
+import (
+	"bytes"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/stretchr/testify/require"
+
+	"github.com/UsingCoding/gostore/cmd/tests/api"
+)
+
+func TestRecipients(t *testing.T) {
+	s, err := newSuite()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		s.cleanup()
+	})
+
+	err = s.gostore().Init(api.InitRequest{
+		ID: "main",
+	})
+	require.NoError(t, err)
+
+	const (
+		path = "team/secret"
+		data = "data"
+	)
+
+	err = s.gostore().Add(api.AddRequest{
+		Path: path,
+		Data: bytes.NewBufferString(data),
+	})
+	require.NoError(t, err)
+
+	initial, err := s.gostore().ListRecipients()
+	require.NoError(t, err)
+	require.Len(t, initial.Recipients, 1)
+
+	identity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	recipient := identity.Recipient().String()
+
+	t.Run("add recipient", func(t *testing.T) {
+		err2 := s.gostore().AddRecipients(api.RecipientsRequest{
+			Recipients: []string{recipient},
+		})
+		require.NoError(t, err2)
+
+		list, err2 := s.gostore().ListRecipients()
+		require.NoError(t, err2)
+		require.Equal(t, append(initial.Recipients, recipient), list.Recipients)
+
+		err2 = s.gostore().AddRecipients(api.RecipientsRequest{
+			Recipients: []string{recipient},
+		})
+		require.Error(t, err2)
+	})
+
+	t.Run("remove initial recipient", func(t *testing.T) {
+		err2 := s.gostore().ImportIdentity(api.ImportIdentityRequest{
+			Provider: "age",
+			Data:     bytes.NewBufferString(identity.String()),
+		})
+		require.NoError(t, err2)
+
+		err2 = s.gostore().RemoveRecipients(api.RecipientsRequest{
+			Recipients: initial.Recipients,
+		})
+		require.NoError(t, err2)
+
+		list, err2 := s.gostore().ListRecipients()
+		require.NoError(t, err2)
+		require.Equal(t, []string{recipient}, list.Recipients)
+
+		// secret re-encrypted for new recipient
+		resp, err2 := s.gostore().Get(api.ReadRequest{
+			Path: path,
+		})
+		require.NoError(t, err2)
+		require.Equal(t, data, string(resp.Data))
+	})
+
+	t.Run("remove last recipient", func(t *testing.T) {
+		err2 := s.gostore().RemoveRecipients(api.RecipientsRequest{
+			Recipients: []string{recipient},
+		})
+		require.Error(t, err2)
+	})
+}
@@ -0,0 +1,59 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/UsingCoding/gostore/cmd/tests/api"
+	"github.com/UsingCoding/gostore/internal/common/maybe"
+)
+
+func TestRender(t *testing.T) {
+	s, err := newSuite()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		s.cleanup()
+	})
+
+	err = s.gostore().Init(api.InitRequest{
+		ID: "main",
+	})
+	require.NoError(t, err)
+
+	for _, req := range []api.AddRequest{
+		{Path: "db/prod", Key: maybe.NewJust("user"), Data: strings.NewReader("admin")},
+		{Path: "db/prod", Key: maybe.NewJust("pass"), Data: strings.NewReader("p@ss")},
+		{Path: "github", Data: strings.NewReader("gh-token")},
+	} {
+		err = s.gostore().Add(req)
+		require.NoError(t, err)
+	}
+
+	err = s.gostore().AddTOTP(api.AddTOTPRequest{
+		Name: maybe.NewJust("github"),
+		URI:  "otpauth://totp/github?secret=JBSWY3DPEHPK3PXP&digits=8",
+	})
+	require.NoError(t, err)
+
+	res, err := s.gostore().Render(api.RenderRequest{
+		Template: `dsn: postgres://{{ secret "db/prod" "user" }}:{{ secret "db/prod" "pass" }}@db
+token: {{ secret "github" }}
+otp: {{ totp "github" | len }}
+`,
+	})
+	require.NoError(t, err)
+	require.Equal(t, `dsn: postgres://admin:p@ss@db
+token: gh-token
+otp: 8
+`, res.Output)
+
+	t.Run("missing secret", func(t *testing.T) {
+		res, err = s.gostore().Render(api.RenderRequest{
+			Template: `before {{ secret "unknown" }}`,
+		})
+		require.Error(t, err)
+		require.Empty(t, res.Output)
+	})
+}
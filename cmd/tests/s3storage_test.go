@@ -0,0 +1,213 @@
+package tests
+
+import (
+	"bytes"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/johannesboyne/gofakes3"
+	"github.com/johannesboyne/gofakes3/backend/s3mem"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/UsingCoding/gostore/cmd/tests/api"
+	"github.com/UsingCoding/gostore/internal/common/maybe"
+)
+
+func TestS3Storage(t *testing.T) {
+	s, err := newSuite()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		s.cleanup()
+	})
+
+	const bucket = "stores"
+
+	backend := s3mem.New()
+	err = backend.CreateBucket(bucket)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(gofakes3.New(backend).Server())
+	t.Cleanup(server.Close)
+
+	remote := "s3+http://" + strings.TrimPrefix(server.URL, "http://") + "/" + bucket + "/team"
+
+	gostore := s.gostore().WithEnv(
+		"AWS_ACCESS_KEY_ID=access",
+		"AWS_SECRET_ACCESS_KEY=secret",
+	)
+
+	err = gostore.Init(api.InitRequest{
+		ID:          "main",
+		StorageType: maybe.NewJust("s3"),
+		Remote:      maybe.NewJust(remote),
+	})
+	require.NoError(t, err)
+
+	const (
+		path = "db/secret"
+	)
+
+	for _, v := range []string{"v1", "v2"} {
+		err = gostore.Add(api.AddRequest{
+			Path: path,
+			Data: bytes.NewBufferString(v),
+		})
+		require.NoError(t, err)
+	}
+
+	history, err := gostore.History(api.HistoryRequest{
+		Path: path,
+	})
+	require.NoError(t, err)
+	require.Len(t, history.Revisions, 2)
+
+	resp, err := gostore.Get(api.ReadRequest{
+		Path: path,
+		At:   maybe.NewJust(history.Revisions[1].ID),
+	})
+	require.NoError(t, err)
+	require.Equal(t, "v1", string(resp.Data))
+
+	err = gostore.Copy(api.CopyRequest{
+		Src: "db",
+		Dst: "backup",
+	})
+	require.NoError(t, err)
+
+	err = gostore.Move(api.MoveRequest{
+		Src: path,
+		Dst: "moved",
+	})
+	require.NoError(t, err)
+
+	list, err := gostore.List(api.ListRequest{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"backup", "moved"}, nodeNames(list.Nodes))
+
+	t.Run("init fails for existing store", func(t *testing.T) {
+		err = gostore.Init(api.InitRequest{
+			ID:          "other",
+			StorageType: maybe.NewJust("s3"),
+			Remote:      maybe.NewJust(remote),
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("clone shares store through bucket", func(t *testing.T) {
+		err = gostore.Clone(api.CloneRequest{
+			ID:          "clone",
+			Remote:      remote,
+			StorageType: maybe.NewJust("s3"),
+		})
+		require.NoError(t, err)
+
+		clone := gostore.WithEnv("GOSTORE_STORE_ID=clone")
+
+		err = clone.Add(api.AddRequest{
+			Path: "shared",
+			Data: bytes.NewBufferString("shared"),
+		})
+		require.NoError(t, err)
+
+		resp, err = gostore.WithEnv("GOSTORE_STORE_ID=main").Get(api.ReadRequest{
+			Path: "shared",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "shared", string(resp.Data))
+	})
+
+	main := gostore.WithEnv("GOSTORE_STORE_ID=main")
+	clone := gostore.WithEnv("GOSTORE_STORE_ID=clone")
+
+	t.Run("uncommitted changes isolated per client", func(t *testing.T) {
+		err = main.Unpack()
+		require.NoError(t, err)
+
+		// unpacked state of main is not visible to clone
+		resp, err = clone.Get(api.ReadRequest{Path: "shared"})
+		require.NoError(t, err)
+		require.Equal(t, "shared", string(resp.Data))
+
+		err = clone.Add(api.AddRequest{
+			Path: "from-clone",
+			Data: bytes.NewBufferString("from-clone"),
+		})
+		require.NoError(t, err)
+
+		// rollback discards only changes of main
+		err = main.Rollback()
+		require.NoError(t, err)
+
+		for _, p := range []string{"shared", "from-clone"} {
+			resp, err = main.Get(api.ReadRequest{Path: p})
+			require.NoError(t, err)
+			require.Equal(t, p, string(resp.Data))
+		}
+	})
+
+	t.Run("concurrent commits of clients", func(t *testing.T) {
+		const n = 5
+
+		var eg errgroup.Group
+		for _, c := range []struct {
+			name string
+			api  api.API
+		}{
+			{name: "main", api: main},
+			{name: "clone", api: clone},
+		} {
+			eg.Go(func() error {
+				for i := range n {
+					err2 := c.api.Add(api.AddRequest{
+						Path: fmt.Sprintf("concurrent/%s-%d", c.name, i),
+						Data: bytes.NewBufferString(c.name),
+					})
+					if err2 != nil {
+						return err2
+					}
+				}
+				return nil
+			})
+		}
+		require.NoError(t, eg.Wait())
+
+		for _, a := range []api.API{main, clone} {
+			list, err2 := a.List(api.ListRequest{Path: maybe.NewJust("concurrent")})
+			require.NoError(t, err2)
+			require.Len(t, list.Nodes, 2*n)
+		}
+	})
+
+	t.Run("conflicting change fails commit", func(t *testing.T) {
+		err = main.Unpack()
+		require.NoError(t, err)
+
+		err = clone.Add(api.AddRequest{
+			Path: "shared",
+			Data: bytes.NewBufferString("changed"),
+		})
+		require.NoError(t, err)
+
+		err = main.Pack()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "shared")
+
+		err = main.Rollback()
+		require.NoError(t, err)
+
+		resp, err = main.Get(api.ReadRequest{Path: "shared"})
+		require.NoError(t, err)
+		require.Equal(t, "changed", string(resp.Data))
+	})
+}
+
+func nodeNames(nodes []api.ListNode) []string {
+	names := make([]string, 0, len(nodes))
+	for _, n := range nodes {
+		names = append(names, n.Name)
+	}
+	return names
+}
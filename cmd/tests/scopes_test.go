@@ -0,0 +1,280 @@
+package tests
+
+import (
+	"bytes"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/UsingCoding/fpgo/pkg/slices"
+	"github.com/stretchr/testify/require"
+
+	"github.com/UsingCoding/gostore/cmd/tests/api"
+	"github.com/UsingCoding/gostore/internal/common/maybe"
+)
+
+func TestRecipientsScopes(t *testing.T) {
+	s, err := newSuite()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		s.cleanup()
+	})
+
+	err = s.gostore().Init(api.InitRequest{
+		ID: "main",
+	})
+	require.NoError(t, err)
+
+	for _, p := range []string{"prod/db", "dev/db"} {
+		err = s.gostore().Add(api.AddRequest{
+			Path: p,
+			Data: bytes.NewBufferString(p),
+		})
+		require.NoError(t, err)
+	}
+
+	ops, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	opsRecipient := ops.Recipient().String()
+
+	t.Run("add scope", func(t *testing.T) {
+		err2 := s.gostore().AddRecipients(api.RecipientsRequest{
+			Recipients: []string{opsRecipient},
+			Path:       maybe.NewJust("prod"),
+		})
+		require.NoError(t, err2)
+
+		scopes, err2 := s.gostore().ListScopes()
+		require.NoError(t, err2)
+		require.Equal(t, []api.RecipientsScope{
+			{Path: "prod", Recipients: []string{opsRecipient}, Accessible: false},
+		}, scopes)
+
+		// store recipients untouched
+		recipients, err2 := s.gostore().ListRecipients()
+		require.NoError(t, err2)
+		require.Len(t, recipients.Recipients, 1)
+
+		_, err2 = s.gostore().Get(api.ReadRequest{Path: "prod/db"})
+		require.Error(t, err2)
+
+		resp, err2 := s.gostore().Get(api.ReadRequest{Path: "dev/db"})
+		require.NoError(t, err2)
+		require.Equal(t, "dev/db", string(resp.Data))
+	})
+
+	t.Run("list marks inaccessible subtree", func(t *testing.T) {
+		list, err2 := s.gostore().List(api.ListRequest{})
+		require.NoError(t, err2)
+
+		noAccess := map[string]bool{}
+		for _, n := range list.Nodes {
+			noAccess[n.Name] = n.NoAccess
+		}
+		require.Equal(t, map[string]bool{"dev": false, "prod": true}, noAccess)
+
+		list, err2 = s.gostore().List(api.ListRequest{Path: maybe.NewJust("prod")})
+		require.NoError(t, err2)
+		require.True(t, list.NoAccess)
+	})
+
+	t.Run("copy across scope boundary", func(t *testing.T) {
+		err2 := s.gostore().Copy(api.CopyRequest{
+			Src: "dev",
+			Dst: "prod/dev",
+		})
+		require.NoError(t, err2)
+
+		_, err2 = s.gostore().Get(api.ReadRequest{Path: "prod/dev/db"})
+		require.Error(t, err2)
+
+		// secrets of scope can not be re-encrypted without scope identity
+		err2 = s.gostore().Copy(api.CopyRequest{
+			Src: "prod/db",
+			Dst: "staging/db",
+		})
+		require.Error(t, err2)
+
+		list, err2 := s.gostore().List(api.ListRequest{})
+		require.NoError(t, err2)
+		require.NotContains(t, slices.Map(list.Nodes, func(n api.ListNode) string {
+			return n.Name
+		}), "staging")
+	})
+
+	t.Run("move across scope boundary", func(t *testing.T) {
+		err2 := s.gostore().Move(api.MoveRequest{
+			Src: "dev/db",
+			Dst: "prod/replica",
+		})
+		require.NoError(t, err2)
+
+		_, err2 = s.gostore().Get(api.ReadRequest{Path: "prod/replica"})
+		require.Error(t, err2)
+
+		err2 = s.gostore().ImportIdentity(api.ImportIdentityRequest{
+			Provider: "age",
+			Data:     bytes.NewBufferString(ops.String()),
+		})
+		require.NoError(t, err2)
+
+		resp, err2 := s.gostore().Get(api.ReadRequest{Path: "prod/replica"})
+		require.NoError(t, err2)
+		require.Equal(t, "dev/db", string(resp.Data))
+
+		list, err2 := s.gostore().List(api.ListRequest{Path: maybe.NewJust("prod")})
+		require.NoError(t, err2)
+		require.False(t, list.NoAccess)
+	})
+
+	t.Run("remove scope", func(t *testing.T) {
+		err2 := s.gostore().RemoveRecipients(api.RecipientsRequest{
+			Recipients: []string{opsRecipient},
+			Path:       maybe.NewJust("prod"),
+		})
+		require.NoError(t, err2)
+
+		scopes, err2 := s.gostore().ListScopes()
+		require.NoError(t, err2)
+		require.Empty(t, scopes)
+
+		resp, err2 := s.gostore().Get(api.ReadRequest{Path: "prod/db"})
+		require.NoError(t, err2)
+		require.Equal(t, "prod/db", string(resp.Data))
+	})
+}
+
+func TestRecipientsScopesAccess(t *testing.T) {
+	s, err := newSuite()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		s.cleanup()
+	})
+
+	err = s.gostore().Init(api.InitRequest{
+		ID: "main",
+	})
+	require.NoError(t, err)
+
+	for _, p := range []string{"prod/db", "prod/team/db"} {
+		err = s.gostore().Add(api.AddRequest{
+			Path: p,
+			Data: bytes.NewBufferString(p),
+		})
+		require.NoError(t, err)
+	}
+
+	local, err := s.gostore().ListRecipients()
+	require.NoError(t, err)
+
+	ops, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+
+	// nested scope accessible by local identity inside scope of other team
+	err = s.gostore().AddRecipients(api.RecipientsRequest{
+		Recipients: local.Recipients,
+		Path:       maybe.NewJust("prod/team"),
+	})
+	require.NoError(t, err)
+	err = s.gostore().AddRecipients(api.RecipientsRequest{
+		Recipients: []string{ops.Recipient().String()},
+		Path:       maybe.NewJust("prod"),
+	})
+	require.NoError(t, err)
+
+	// access checked without unlocking identities
+	err = s.gostore().ProtectIdentities(api.ProtectIdentitiesRequest{
+		Passphrase: "passphrase",
+	})
+	require.NoError(t, err)
+
+	scopes, err := s.gostore().ListScopes()
+	require.NoError(t, err)
+	require.ElementsMatch(t, []api.RecipientsScope{
+		{Path: "prod/team", Recipients: local.Recipients, Accessible: true},
+		{Path: "prod", Recipients: []string{ops.Recipient().String()}, Accessible: false},
+	}, scopes)
+
+	list, err := s.gostore().List(api.ListRequest{Path: maybe.NewJust("prod")})
+	require.NoError(t, err)
+	require.True(t, list.NoAccess)
+
+	noAccess := map[string]bool{}
+	for _, n := range list.Nodes {
+		noAccess[n.Name] = n.NoAccess
+	}
+	require.Equal(t, map[string]bool{"db": true, "team": false}, noAccess)
+
+	list, err = s.gostore().List(api.ListRequest{Path: maybe.NewJust("prod/team")})
+	require.NoError(t, err)
+	require.False(t, list.NoAccess)
+}
+
+func TestRecipientsScopesDiff(t *testing.T) {
+	s, err := newSuite()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		s.cleanup()
+	})
+
+	err = s.gostore().Init(api.InitRequest{
+		ID: "main",
+	})
+	require.NoError(t, err)
+
+	add := func(path, key, data string) {
+		err2 := s.gostore().Add(api.AddRequest{
+			Path: path,
+			Key:  maybe.NewJust(key),
+			Data: bytes.NewBufferString(data),
+		})
+		require.NoError(t, err2)
+	}
+
+	add("team/x", "password", "p1")
+	add("api", "token", "t1")
+
+	history, err := s.gostore().History(api.HistoryRequest{Path: "api"})
+	require.NoError(t, err)
+	require.Len(t, history.Revisions, 1)
+
+	ops, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+
+	// secret of scope re-encrypted for recipient local identities can not decrypt
+	err = s.gostore().AddRecipients(api.RecipientsRequest{
+		Recipients: []string{ops.Recipient().String()},
+		Path:       maybe.NewJust("team"),
+	})
+	require.NoError(t, err)
+
+	add("api", "token", "t2")
+
+	t1, t2 := "t1", "t2"
+	for _, showValues := range []bool{false, true} {
+		resp, err2 := s.gostore().Diff(api.DiffRequest{
+			Revisions:  []string{history.Revisions[0].ID, "HEAD"},
+			ShowValues: showValues,
+		})
+		require.NoError(t, err2)
+
+		apiKey := api.KeyDiff{Name: "token", Status: "changed"}
+		if showValues {
+			apiKey.Old = &t1
+			apiKey.New = &t2
+		}
+
+		require.Equal(t, []api.SecretDiff{
+			{
+				Path:   "api",
+				Status: "changed",
+				Keys:   []api.KeyDiff{apiKey},
+			},
+			{
+				Path:     "team/x",
+				Status:   "changed",
+				Keys:     []api.KeyDiff{{Name: "password", Status: "changed"}},
+				NoAccess: true,
+			},
+		}, resp.Secrets)
+	}
+}
@@ -50,6 +50,27 @@ func TestSecretCRUD(t *testing.T) {
 		require.NoError(t, err2)
 	})
 
+	t.Run("value with format verbs", func(t *testing.T) {
+		const verbData = "p%sss%d%x"
+		err2 := s.gostore().Add(api.AddRequest{
+			Path: path,
+			Data: bytes.NewBufferString(verbData),
+		})
+		require.NoError(t, err2)
+
+		resp, err2 := s.gostore().Get(api.ReadRequest{
+			Path: path,
+		})
+		require.NoError(t, err2)
+
+		require.Equal(t, verbData, string(resp.Data))
+
+		err2 = s.gostore().Remove(api.RemoveRequest{
+			Path: path,
+		})
+		require.NoError(t, err2)
+	})
+
 	t.Run("copy secret", func(t *testing.T) {
 		dstP := "dst"
 		t.Cleanup(func() {
@@ -0,0 +1,144 @@
+package tests
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/pem"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/UsingCoding/gostore/cmd/tests/api"
+)
+
+func TestSSHIdentity(t *testing.T) {
+	s, err := newSuite()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		s.cleanup()
+	})
+
+	err = s.gostore().Init(api.InitRequest{
+		ID: "main",
+	})
+	require.NoError(t, err)
+
+	const (
+		path       = "team/secret"
+		data       = "data"
+		passphrase = "ssh-passphrase"
+	)
+
+	err = s.gostore().Add(api.AddRequest{
+		Path: path,
+		Data: bytes.NewBufferString(data),
+	})
+	require.NoError(t, err)
+
+	initial, err := s.gostore().ListRecipients()
+	require.NoError(t, err)
+
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	sshPub, err := ssh.NewPublicKey(pub)
+	require.NoError(t, err)
+	recipient := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshPub)))
+
+	block, err := ssh.MarshalPrivateKeyWithPassphrase(key, "user@host", []byte(passphrase))
+	require.NoError(t, err)
+	privateKey := pem.EncodeToMemory(block)
+
+	t.Run("add ssh recipient", func(t *testing.T) {
+		// comment of public key dropped
+		err2 := s.gostore().AddRecipients(api.RecipientsRequest{
+			Recipients: []string{recipient + " user@host"},
+		})
+		require.NoError(t, err2)
+
+		list, err2 := s.gostore().ListRecipients()
+		require.NoError(t, err2)
+		require.Equal(t, append(initial.Recipients, recipient), list.Recipients)
+	})
+
+	t.Run("import protected ssh key", func(t *testing.T) {
+		err2 := s.gostore().
+			WithEnv("GOSTORE_PASSPHRASE=invalid").
+			ImportIdentity(api.ImportIdentityRequest{
+				Provider: "ssh",
+				Data:     bytes.NewBuffer(privateKey),
+			})
+		require.Error(t, err2)
+
+		err2 = s.gostore().
+			WithEnv("GOSTORE_PASSPHRASE=" + passphrase).
+			ImportIdentity(api.ImportIdentityRequest{
+				Provider: "ssh",
+				Data:     bytes.NewBuffer(privateKey),
+			})
+		require.NoError(t, err2)
+	})
+
+	t.Run("decrypt with ssh identity", func(t *testing.T) {
+		err2 := s.gostore().
+			WithEnv("GOSTORE_PASSPHRASE=" + passphrase).
+			RemoveRecipients(api.RecipientsRequest{
+				Recipients: initial.Recipients,
+			})
+		require.NoError(t, err2)
+
+		// imported key kept protected with its passphrase
+		_, err2 = s.gostore().
+			WithEnv("GOSTORE_PASSPHRASE=invalid").
+			Get(api.ReadRequest{Path: path})
+		require.Error(t, err2)
+
+		resp, err2 := s.gostore().
+			WithEnv("GOSTORE_PASSPHRASE=" + passphrase).
+			Get(api.ReadRequest{Path: path})
+		require.NoError(t, err2)
+		require.Equal(t, data, string(resp.Data))
+	})
+
+	t.Run("rsa key", func(t *testing.T) {
+		rsaKey, err2 := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err2)
+
+		rsaPub, err2 := ssh.NewPublicKey(&rsaKey.PublicKey)
+		require.NoError(t, err2)
+
+		rsaBlock, err2 := ssh.MarshalPrivateKey(rsaKey, "")
+		require.NoError(t, err2)
+
+		err2 = s.gostore().
+			WithEnv("GOSTORE_PASSPHRASE=" + passphrase).
+			AddRecipients(api.RecipientsRequest{
+				Recipients: []string{strings.TrimSpace(string(ssh.MarshalAuthorizedKey(rsaPub)))},
+			})
+		require.NoError(t, err2)
+
+		err2 = s.gostore().ImportIdentity(api.ImportIdentityRequest{
+			Provider: "ssh",
+			Data:     bytes.NewBuffer(pem.EncodeToMemory(rsaBlock)),
+		})
+		require.NoError(t, err2)
+
+		err2 = s.gostore().
+			WithEnv("GOSTORE_PASSPHRASE=" + passphrase).
+			RemoveRecipients(api.RecipientsRequest{
+				Recipients: []string{recipient},
+			})
+		require.NoError(t, err2)
+
+		// secret readable with unprotected rsa identity only
+		resp, err2 := s.gostore().
+			WithEnv("GOSTORE_PASSPHRASE=invalid").
+			Get(api.ReadRequest{Path: path})
+		require.NoError(t, err2)
+		require.Equal(t, data, string(resp.Data))
+	})
+}
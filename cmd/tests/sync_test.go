@@ -0,0 +1,346 @@
+package tests
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"os"
+	"path"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/go-git/go-git/v5"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"github.com/UsingCoding/gostore/cmd/tests/api"
+	"github.com/UsingCoding/gostore/internal/common/maybe"
+)
+
+func TestSyncMergesKeys(t *testing.T) {
+	s, err := newSuite()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		s.cleanup()
+	})
+
+	remote := path.Join(s.basePath, "remote.git")
+	_, err = git.PlainInit(remote, true)
+	require.NoError(t, err)
+
+	first := s.gostore().WithEnv("GOSTORE_STORE_ID=first")
+	second := s.gostore().WithEnv("GOSTORE_STORE_ID=second")
+
+	err = s.gostore().Init(api.InitRequest{
+		ID:     "first",
+		Remote: maybe.NewJust(remote),
+	})
+	require.NoError(t, err)
+
+	add := func(a api.API, key, data string) {
+		err2 := a.Add(api.AddRequest{
+			Path: "db",
+			Key:  maybe.NewJust(key),
+			Data: bytes.NewBufferString(data),
+		})
+		require.NoError(t, err2)
+	}
+
+	get := func(a api.API, key string) string {
+		resp, err2 := a.Get(api.ReadRequest{
+			Path: "db",
+			Key:  maybe.NewJust(key),
+		})
+		require.NoError(t, err2)
+		return string(resp.Data)
+	}
+
+	add(first, "user", "admin")
+	add(first, "password", "p1")
+	require.NoError(t, first.Sync())
+
+	err = s.gostore().Clone(api.CloneRequest{
+		ID:     "second",
+		Remote: remote,
+	})
+	require.NoError(t, err)
+
+	t.Run("different keys merged", func(t *testing.T) {
+		add(first, "password", "p2")
+		add(second, "user", "root")
+
+		require.NoError(t, first.Sync())
+		require.NoError(t, second.Sync())
+		require.NoError(t, first.Sync())
+
+		for _, a := range []api.API{first, second} {
+			require.Equal(t, "p2", get(a, "password"))
+			require.Equal(t, "root", get(a, "user"))
+		}
+	})
+
+	t.Run("same value is not conflict", func(t *testing.T) {
+		add(first, "password", "p3")
+		add(second, "password", "p3")
+
+		require.NoError(t, first.Sync())
+		require.NoError(t, second.Sync())
+		require.Equal(t, "p3", get(second, "password"))
+	})
+
+	t.Run("uncommitted changes not discarded", func(t *testing.T) {
+		require.NoError(t, first.Sync())
+		add(first, "user", "guest")
+		require.NoError(t, first.Sync())
+
+		secretPath := path.Join(s.basePath, "second", "db")
+		data, err2 := os.ReadFile(secretPath)
+		require.NoError(t, err2)
+
+		changed := append(bytes.Clone(data), '\n')
+		require.NoError(t, os.WriteFile(secretPath, changed, 0o600))
+
+		err2 = second.Sync()
+		require.Error(t, err2)
+		require.Contains(t, err2.Error(), "uncommitted changes")
+
+		kept, err2 := os.ReadFile(secretPath)
+		require.NoError(t, err2)
+		require.Equal(t, changed, kept)
+
+		require.NoError(t, os.WriteFile(secretPath, data, 0o600))
+		require.NoError(t, second.Sync())
+		require.Equal(t, "guest", get(second, "user"))
+	})
+
+	t.Run("conflicting key reported", func(t *testing.T) {
+		add(first, "password", "p4")
+		add(second, "password", "p5")
+
+		require.NoError(t, first.Sync())
+
+		err2 := second.Sync()
+		require.Error(t, err2)
+		require.Contains(t, err2.Error(), "db:password")
+
+		// local changes kept after failed sync
+		require.Equal(t, "p5", get(second, "password"))
+	})
+}
+
+func TestSyncMergesRecipients(t *testing.T) {
+	s, err := newSuite()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		s.cleanup()
+	})
+
+	remote := path.Join(s.basePath, "remote.git")
+	_, err = git.PlainInit(remote, true)
+	require.NoError(t, err)
+
+	first := s.gostore().WithEnv("GOSTORE_STORE_ID=first")
+	second := s.gostore().WithEnv("GOSTORE_STORE_ID=second")
+
+	err = s.gostore().Init(api.InitRequest{
+		ID:     "first",
+		Remote: maybe.NewJust(remote),
+	})
+	require.NoError(t, err)
+
+	err = first.Add(api.AddRequest{
+		Path: "db",
+		Data: bytes.NewBufferString("data"),
+	})
+	require.NoError(t, err)
+	require.NoError(t, first.Sync())
+
+	err = s.gostore().Clone(api.CloneRequest{
+		ID:     "second",
+		Remote: remote,
+	})
+	require.NoError(t, err)
+
+	initial, err := first.ListRecipients()
+	require.NoError(t, err)
+
+	newRecipient := func() string {
+		identity, err2 := age.GenerateX25519Identity()
+		require.NoError(t, err2)
+		return identity.Recipient().String()
+	}
+	firstRecipient, secondRecipient, scopeRecipient := newRecipient(), newRecipient(), newRecipient()
+
+	err = first.AddRecipients(api.RecipientsRequest{
+		Recipients: []string{firstRecipient},
+	})
+	require.NoError(t, err)
+
+	err = second.AddRecipients(api.RecipientsRequest{
+		Recipients: []string{secondRecipient},
+	})
+	require.NoError(t, err)
+	err = second.AddRecipients(api.RecipientsRequest{
+		Recipients: append([]string{scopeRecipient}, initial.Recipients...),
+		Path:       maybe.NewJust("team"),
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, first.Sync())
+	require.NoError(t, second.Sync())
+	require.NoError(t, first.Sync())
+
+	for _, a := range []api.API{first, second} {
+		recipients, err2 := a.ListRecipients()
+		require.NoError(t, err2)
+		require.ElementsMatch(t, append([]string{firstRecipient, secondRecipient}, initial.Recipients...), recipients.Recipients)
+
+		scopes, err2 := a.ListScopes()
+		require.NoError(t, err2)
+		require.Len(t, scopes, 1)
+		require.Equal(t, "team", scopes[0].Path)
+		require.ElementsMatch(t, append([]string{scopeRecipient}, initial.Recipients...), scopes[0].Recipients)
+
+		resp, err2 := a.Get(api.ReadRequest{Path: "db"})
+		require.NoError(t, err2)
+		require.Equal(t, "data", string(resp.Data))
+	}
+}
+
+func TestSyncAuth(t *testing.T) {
+	s, err := newSuite()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		s.cleanup()
+	})
+
+	const (
+		tokenEnv    = "GOSTORE_TEST_GIT_TOKEN"
+		passwordEnv = "GOSTORE_TEST_GIT_PASSWORD"
+		token       = "token"
+		password    = "password"
+	)
+
+	newRemote := func(name string) string {
+		remote := path.Join(s.basePath, name)
+		_, err2 := git.PlainInit(remote, true)
+		require.NoError(t, err2)
+		return remote
+	}
+
+	pushed := func(remote string) {
+		repo, err2 := git.PlainOpen(remote)
+		require.NoError(t, err2)
+		_, err2 = repo.Head()
+		require.NoError(t, err2)
+	}
+
+	initStore := func(id, remote string) api.API {
+		err2 := s.gostore().Init(api.InitRequest{
+			ID:     id,
+			Remote: maybe.NewJust(remote),
+		})
+		require.NoError(t, err2)
+
+		a := s.gostore().WithEnv("GOSTORE_STORE_ID=" + id)
+		err2 = a.Add(api.AddRequest{
+			Path: "db",
+			Data: bytes.NewBufferString("data"),
+		})
+		require.NoError(t, err2)
+
+		return a
+	}
+
+	t.Run("https token", func(t *testing.T) {
+		remote := newRemote("token.git")
+		url := serveGitHTTP(t, s.basePath, func(user, p string) bool {
+			// token sent as basic auth password
+			return user != "" && p == token
+		})
+
+		a := initStore("token", url+"/token.git")
+
+		err2 := s.gostore().SetStoreAuth(api.StoreAuthRequest{
+			StoreID:   "token",
+			Type:      "token",
+			SecretEnv: maybe.NewJust(tokenEnv),
+		})
+		require.NoError(t, err2)
+
+		err2 = a.Sync()
+		require.Error(t, err2)
+		require.Contains(t, err2.Error(), tokenEnv)
+
+		err2 = a.WithEnv(tokenEnv + "=invalid").Sync()
+		require.Error(t, err2)
+
+		err2 = a.WithEnv(tokenEnv + "=" + token).Sync()
+		require.NoError(t, err2)
+		pushed(remote)
+	})
+
+	t.Run("https basic auth", func(t *testing.T) {
+		remote := newRemote("basic.git")
+		url := serveGitHTTP(t, s.basePath, func(user, p string) bool {
+			return user == "bot" && p == password
+		})
+
+		a := initStore("basic", url+"/basic.git")
+
+		err2 := s.gostore().SetStoreAuth(api.StoreAuthRequest{
+			StoreID:   "basic",
+			Type:      "basic",
+			User:      maybe.NewJust("bot"),
+			SecretEnv: maybe.NewJust(passwordEnv),
+		})
+		require.NoError(t, err2)
+
+		err2 = a.WithEnv(passwordEnv + "=invalid").Sync()
+		require.Error(t, err2)
+
+		err2 = a.WithEnv(passwordEnv + "=" + password).Sync()
+		require.NoError(t, err2)
+		pushed(remote)
+	})
+
+	t.Run("ssh key file", func(t *testing.T) {
+		remote := newRemote("ssh.git")
+
+		pub, key, err2 := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err2)
+		sshPub, err2 := ssh.NewPublicKey(pub)
+		require.NoError(t, err2)
+
+		block, err2 := ssh.MarshalPrivateKeyWithPassphrase(key, "", []byte(password))
+		require.NoError(t, err2)
+		keyPath := path.Join(s.basePath, "id_ed25519")
+		err2 = os.WriteFile(keyPath, pem.EncodeToMemory(block), 0o600)
+		require.NoError(t, err2)
+
+		addr, hostKey := serveGitSSH(t, sshPub)
+		knownHosts := path.Join(s.basePath, "known_hosts")
+		err2 = os.WriteFile(knownHosts, []byte(knownhosts.Line([]string{knownhosts.Normalize(addr)}, hostKey)+"\n"), 0o600)
+		require.NoError(t, err2)
+
+		a := initStore("ssh", "ssh://git@"+addr+remote).
+			WithEnv("SSH_KNOWN_HOSTS=" + knownHosts)
+
+		err2 = s.gostore().SetStoreAuth(api.StoreAuthRequest{
+			StoreID:   "ssh",
+			Type:      "ssh-key",
+			SSHKey:    maybe.NewJust(keyPath),
+			SecretEnv: maybe.NewJust(passwordEnv),
+		})
+		require.NoError(t, err2)
+
+		err2 = a.WithEnv(passwordEnv + "=invalid").Sync()
+		require.Error(t, err2)
+
+		err2 = a.WithEnv(passwordEnv + "=" + password).Sync()
+		require.NoError(t, err2)
+		pushed(remote)
+	})
+}
@@ -0,0 +1,127 @@
+package tests
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/hotp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/UsingCoding/gostore/cmd/tests/api"
+	"github.com/UsingCoding/gostore/internal/common/maybe"
+)
+
+func TestTOTPURI(t *testing.T) {
+	s, err := newSuite()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		s.cleanup()
+	})
+
+	err = s.gostore().Init(api.InitRequest{
+		ID: "main",
+	})
+	require.NoError(t, err)
+
+	const (
+		uri = "otpauth://totp/Example:alice@example.com?secret=JBSWY3DPEHPK3PXP&issuer=Example&algorithm=SHA256&digits=8&period=60"
+	)
+
+	err = s.gostore().AddTOTP(api.AddTOTPRequest{
+		URI: uri,
+	})
+	require.NoError(t, err)
+
+	exported, err := s.gostore().ExportTOTP(api.ExportTOTPRequest{
+		Name: "Example",
+	})
+	require.NoError(t, err)
+
+	key, err := otp.NewKeyFromURL(exported.URI)
+	require.NoError(t, err)
+	require.Equal(t, "Example", key.Issuer())
+	require.Equal(t, "alice@example.com", key.AccountName())
+	require.Equal(t, "JBSWY3DPEHPK3PXP", key.Secret())
+	require.Equal(t, otp.AlgorithmSHA256, key.Algorithm())
+	require.Equal(t, otp.DigitsEight, key.Digits())
+	require.Equal(t, uint64(60), key.Period())
+
+	passcode, err := s.gostore().TOTPPasscode(api.TOTPPasscodeRequest{
+		Name: "Example",
+	})
+	require.NoError(t, err)
+	require.Len(t, passcode.Code, 8)
+
+	t.Run("explicit name", func(t *testing.T) {
+		err = s.gostore().AddTOTP(api.AddTOTPRequest{
+			Name: maybe.NewJust("work"),
+			URI:  "otpauth://totp/alice?secret=JBSWY3DPEHPK3PXP",
+		})
+		require.NoError(t, err)
+
+		passcode, err = s.gostore().TOTPPasscode(api.TOTPPasscodeRequest{
+			Name: "work",
+		})
+		require.NoError(t, err)
+		require.Len(t, passcode.Code, 6)
+	})
+
+	t.Run("non positive period rejected", func(t *testing.T) {
+		err = s.gostore().AddTOTP(api.AddTOTPRequest{
+			Name: maybe.NewJust("zero"),
+			URI:  "otpauth://totp/alice?secret=JBSWY3DPEHPK3PXP&period=0",
+		})
+		require.NoError(t, err)
+
+		_, err = s.gostore().TOTPPasscode(api.TOTPPasscodeRequest{
+			Name: "zero",
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid period")
+	})
+}
+
+func TestHOTP(t *testing.T) {
+	s, err := newSuite()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		s.cleanup()
+	})
+
+	err = s.gostore().Init(api.InitRequest{
+		ID: "main",
+	})
+	require.NoError(t, err)
+
+	const (
+		secret = "JBSWY3DPEHPK3PXP"
+	)
+
+	err = s.gostore().AddTOTP(api.AddTOTPRequest{
+		Name: maybe.NewJust("bank"),
+		URI:  "otpauth://hotp/Bank:alice?secret=" + secret + "&issuer=Bank&counter=5",
+	})
+	require.NoError(t, err)
+
+	for _, counter := range []uint64{5, 6} {
+		passcode, err2 := s.gostore().TOTPPasscode(api.TOTPPasscodeRequest{
+			Name: "bank",
+		})
+		require.NoError(t, err2)
+
+		expected, err2 := hotp.GenerateCode(secret, counter)
+		require.NoError(t, err2)
+		require.Equal(t, expected, passcode.Code)
+	}
+
+	exported, err := s.gostore().ExportTOTP(api.ExportTOTPRequest{
+		Name: "bank",
+	})
+	require.NoError(t, err)
+
+	u, err := url.Parse(exported.URI)
+	require.NoError(t, err)
+	require.Equal(t, "hotp", u.Host)
+	require.Equal(t, "7", u.Query().Get("counter"))
+}
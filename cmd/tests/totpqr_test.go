@@ -0,0 +1,162 @@
+package tests
+
+import (
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/png"
+	"net/url"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/qrcode"
+	"github.com/pquerna/otp"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/UsingCoding/gostore/cmd/tests/api"
+)
+
+func TestTOTPImportQR(t *testing.T) {
+	s, err := newSuite()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		s.cleanup()
+	})
+
+	err = s.gostore().Init(api.InitRequest{
+		ID: "main",
+	})
+	require.NoError(t, err)
+
+	t.Run("otpauth uri", func(t *testing.T) {
+		img := path.Join(s.basePath, "uri.png")
+		writeQR(t, img, "otpauth://totp/Example:alice?secret=JBSWY3DPEHPK3PXP&issuer=Example&digits=8")
+
+		err = s.gostore().ImportTOTPQR(api.ImportTOTPQRRequest{ImagePath: img})
+		require.NoError(t, err)
+
+		exported, err2 := s.gostore().ExportTOTP(api.ExportTOTPRequest{Name: "Example"})
+		require.NoError(t, err2)
+
+		key, err2 := otp.NewKeyFromURL(exported.URI)
+		require.NoError(t, err2)
+		require.Equal(t, "JBSWY3DPEHPK3PXP", key.Secret())
+		require.Equal(t, otp.DigitsEight, key.Digits())
+	})
+
+	t.Run("migration batch", func(t *testing.T) {
+		img := path.Join(s.basePath, "migration.png")
+		writeMigrationQR(t, img, []migrationAccount{
+			{secret: "first", name: "Work:bob", issuer: "Work", otpType: 2},
+			{secret: "second", name: "carol", issuer: "Bank", otpType: 1},
+		})
+
+		err = s.gostore().ImportTOTPQR(api.ImportTOTPQRRequest{ImagePath: img})
+		require.NoError(t, err)
+
+		exported, err2 := s.gostore().ExportTOTP(api.ExportTOTPRequest{Name: "Work"})
+		require.NoError(t, err2)
+
+		key, err2 := otp.NewKeyFromURL(exported.URI)
+		require.NoError(t, err2)
+		require.Equal(t, "bob", key.AccountName())
+		// base32 of "first"
+		require.Equal(t, "MZUXE43U", key.Secret())
+		require.Equal(t, "totp", key.Type())
+
+		exported, err2 = s.gostore().ExportTOTP(api.ExportTOTPRequest{Name: "Bank"})
+		require.NoError(t, err2)
+
+		key, err2 = otp.NewKeyFromURL(exported.URI)
+		require.NoError(t, err2)
+		require.Equal(t, "carol", key.AccountName())
+		require.Equal(t, "hotp", key.Type())
+	})
+
+	t.Run("existing issuer", func(t *testing.T) {
+		img := path.Join(s.basePath, "existing.png")
+		writeMigrationQR(t, img, []migrationAccount{
+			{secret: "third", name: "dave", issuer: "Shop", otpType: 2},
+			{secret: "fourth", name: "Work:erin", issuer: "Work", otpType: 1},
+		})
+
+		err = s.gostore().ImportTOTPQR(api.ImportTOTPQRRequest{ImagePath: img})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "already exists")
+
+		// nothing imported from failed batch
+		_, err2 := s.gostore().ExportTOTP(api.ExportTOTPRequest{Name: "Shop"})
+		require.Error(t, err2)
+
+		err = s.gostore().ImportTOTPQR(api.ImportTOTPQRRequest{ImagePath: img, Overwrite: true})
+		require.NoError(t, err)
+
+		exported, err2 := s.gostore().ExportTOTP(api.ExportTOTPRequest{Name: "Work"})
+		require.NoError(t, err2)
+
+		key, err2 := otp.NewKeyFromURL(exported.URI)
+		require.NoError(t, err2)
+		require.Equal(t, "erin", key.AccountName())
+		require.Equal(t, "hotp", key.Type())
+
+		_, err2 = s.gostore().ExportTOTP(api.ExportTOTPRequest{Name: "Shop"})
+		require.NoError(t, err2)
+	})
+}
+
+type migrationAccount struct {
+	secret, name, issuer string
+	otpType              uint64
+}
+
+// writeMigrationQR writes QR code with Google Authenticator export of accounts
+func writeMigrationQR(t *testing.T, p string, accounts []migrationAccount) {
+	t.Helper()
+
+	var payload []byte
+	for _, account := range accounts {
+		var a []byte
+		a = protowire.AppendTag(a, 1, protowire.BytesType)
+		a = protowire.AppendBytes(a, []byte(account.secret))
+		a = protowire.AppendTag(a, 2, protowire.BytesType)
+		a = protowire.AppendString(a, account.name)
+		a = protowire.AppendTag(a, 3, protowire.BytesType)
+		a = protowire.AppendString(a, account.issuer)
+		a = protowire.AppendTag(a, 6, protowire.VarintType)
+		a = protowire.AppendVarint(a, account.otpType)
+
+		payload = protowire.AppendTag(payload, 1, protowire.BytesType)
+		payload = protowire.AppendBytes(payload, a)
+	}
+
+	writeQR(t, p, "otpauth-migration://offline?data="+url.QueryEscape(base64.StdEncoding.EncodeToString(payload)))
+}
+
+func writeQR(t *testing.T, p, text string) {
+	t.Helper()
+
+	const size = 400
+
+	m, err := qrcode.NewQRCodeWriter().Encode(text, gozxing.BarcodeFormat_QR_CODE, size, size, nil)
+	require.NoError(t, err)
+
+	img := image.NewGray(image.Rect(0, 0, m.GetWidth(), m.GetHeight()))
+	for x := 0; x < m.GetWidth(); x++ {
+		for y := 0; y < m.GetHeight(); y++ {
+			c := color.White
+			if m.Get(x, y) {
+				c = color.Black
+			}
+			img.Set(x, y, c)
+		}
+	}
+
+	f, err := os.Create(p)
+	require.NoError(t, err)
+	defer f.Close()
+
+	require.NoError(t, png.Encode(f, img))
+}
@@ -0,0 +1,22 @@
+package agent
+
+import (
+	"github.com/urfave/cli/v2"
+
+	"github.com/UsingCoding/gostore/internal/cli/cmd"
+)
+
+func Agent() []*cli.Command {
+	return []*cli.Command{
+		{
+			Name:     "agent",
+			Usage:    "Manage agent caching unlocked identities",
+			Category: cmd.ModuleCategory,
+			Subcommands: []*cli.Command{
+				start(),
+				stop(),
+				clearCmd(),
+			},
+		},
+	}
+}
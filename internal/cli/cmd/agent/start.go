@@ -0,0 +1,41 @@
+package agent
+
+import (
+	"os"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	clipkg "github.com/UsingCoding/gostore/internal/cli"
+	appagent "github.com/UsingCoding/gostore/internal/gostore/app/agent"
+	infraagent "github.com/UsingCoding/gostore/internal/gostore/infrastructure/agent"
+	"github.com/UsingCoding/gostore/internal/gostore/infrastructure/consoleoutput"
+)
+
+func start() *cli.Command {
+	return &cli.Command{
+		Name:  "start",
+		Usage: "Start agent in foreground",
+		Flags: []cli.Flag{
+			&cli.DurationFlag{
+				Name:  "ttl",
+				Usage: "How long agent holds unlocked identity",
+				Value: 15 * time.Minute,
+			},
+		},
+		Action: func(ctx *cli.Context) error {
+			container := clipkg.ContainerScope.MustGet(ctx.Context)
+			socket := container.AgentSocket
+
+			o := consoleoutput.New(os.Stderr, consoleoutput.WithNewline(true))
+			o.Printf("Agent listening on %s", socket)
+
+			return infraagent.Serve(
+				ctx.Context,
+				socket,
+				appagent.NewKeyring(ctx.Duration("ttl")),
+				container.EncryptionManager,
+			)
+		},
+	}
+}
@@ -0,0 +1,19 @@
+package agent
+
+import (
+	"github.com/urfave/cli/v2"
+
+	clipkg "github.com/UsingCoding/gostore/internal/cli"
+)
+
+func stop() *cli.Command {
+	return &cli.Command{
+		Name:  "stop",
+		Usage: "Stop running agent",
+		Action: func(ctx *cli.Context) error {
+			client := clipkg.ContainerScope.MustGet(ctx.Context).Agent
+
+			return client.Stop(ctx.Context)
+		},
+	}
+}
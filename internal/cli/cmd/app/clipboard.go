@@ -0,0 +1,26 @@
+package app
+
+import (
+	"os"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/UsingCoding/gostore/internal/gostore/infrastructure/clipboard"
+)
+
+// clipboardClear is helper started by commands copying to clipboard
+func clipboardClear() *cli.Command {
+	return &cli.Command{
+		Name:   clipboard.ClearCommand,
+		Hidden: true,
+		Flags: []cli.Flag{
+			&cli.DurationFlag{
+				Name:  "after",
+				Value: clipboard.DefaultClearTimeout,
+			},
+		},
+		Action: func(ctx *cli.Context) error {
+			return clipboard.ClearAfter(os.Stdin, ctx.Duration("after"))
+		},
+	}
+}
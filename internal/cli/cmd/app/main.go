@@ -17,5 +17,6 @@ func Main() []*cli.Command {
 	return []*cli.Command{
 		version(),
 		completion(),
+		clipboardClear(),
 	}
 }
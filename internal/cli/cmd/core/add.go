@@ -23,6 +23,13 @@ func add() *cli.Command {
 		Category:     cmd.CoreCategory,
 		Action:       executeAdd,
 		BashComplete: completion.ListCompletion(""),
+		Flags: append([]cli.Flag{
+			&cli.BoolFlag{
+				Name:    "generate",
+				Aliases: []string{"g"},
+				Usage:   "Generate password instead of reading it, generate flags applied",
+			},
+		}, generateFlags()...),
 	}
 }
 
@@ -30,6 +37,10 @@ func executeAdd(ctx *cli.Context) error {
 	if ctx.Args().Len() < 1 {
 		return errors.New("not enough arguments")
 	}
+
+	if ctx.Bool("generate") {
+		return executeGenerate(ctx)
+	}
 	path := ctx.Args().Get(0)
 
 	var key maybe.Maybe[string]
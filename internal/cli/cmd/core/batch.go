@@ -0,0 +1,160 @@
+package core
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+
+	clipkg "github.com/UsingCoding/gostore/internal/cli"
+	"github.com/UsingCoding/gostore/internal/cli/cmd"
+	"github.com/UsingCoding/gostore/internal/common/maybe"
+	"github.com/UsingCoding/gostore/internal/gostore/app/store"
+)
+
+func batch() *cli.Command {
+	return &cli.Command{
+		Name:      "batch",
+		Usage:     "Apply operations from stdin in one change",
+		UsageText: "batch < OPS.jsonl",
+		Description: "Each line of stdin is JSON operation:\n" +
+			`  {"op": "add", "path": "PATH", "key": "KEY", "value": "VALUE"}` + "\n" +
+			`  {"op": "import", "path": "PATH", "payload": {"KEY": "VALUE"}, "replace": false}` + "\n" +
+			`  {"op": "copy", "src": "PATH", "dst": "PATH"}` + "\n" +
+			`  {"op": "move", "src": "PATH", "dst": "PATH"}` + "\n" +
+			`  {"op": "remove", "path": "PATH", "key": "KEY"}` + "\n" +
+			"Key is optional. Nothing applied if any operation fails",
+		Category: cmd.CoreCategory,
+		Action:   executeBatch,
+	}
+}
+
+type batchOp struct {
+	Op      string            `json:"op"`
+	Path    string            `json:"path"`
+	Key     *string           `json:"key"`
+	Value   *string           `json:"value"`
+	Payload map[string]string `json:"payload"`
+	Replace bool              `json:"replace"`
+	Src     string            `json:"src"`
+	Dst     string            `json:"dst"`
+}
+
+func executeBatch(ctx *cli.Context) error {
+	ops, err := readBatchOps()
+	if err != nil {
+		return err
+	}
+
+	if len(ops) == 0 {
+		return errors.New("no operations passed")
+	}
+
+	service := clipkg.ContainerScope.MustGet(ctx.Context).StoreService
+
+	return service.Batch(ctx.Context, func(tx store.Tx) error {
+		for i, op := range ops {
+			err2 := applyBatchOp(ctx.Context, tx, op)
+			if err2 != nil {
+				return errors.Wrapf(err2, "operation %d %s", i+1, op.Op)
+			}
+		}
+		return nil
+	})
+}
+
+// readBatchOps reads and validates all operations before applying them
+func readBatchOps() ([]batchOp, error) {
+	scanner := bufio.NewScanner(os.Stdin)
+	// allow big values in operations
+	scanner.Buffer(nil, 16*1024*1024)
+
+	var (
+		ops  []batchOp
+		line int
+	)
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var op batchOp
+		err := json.Unmarshal([]byte(text), &op)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid operation at line %d", line)
+		}
+
+		err = validateBatchOp(op)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid operation at line %d", line)
+		}
+
+		ops = append(ops, op)
+	}
+
+	return ops, errors.Wrap(scanner.Err(), "failed to read operations")
+}
+
+func validateBatchOp(op batchOp) error {
+	switch op.Op {
+	case "add":
+		if op.Path == "" || op.Value == nil {
+			return errors.New("add requires path and value")
+		}
+	case "import":
+		if op.Path == "" || len(op.Payload) == 0 {
+			return errors.New("import requires path and payload")
+		}
+	case "copy", "move":
+		if op.Src == "" || op.Dst == "" {
+			return errors.Errorf("%s requires src and dst", op.Op)
+		}
+	case "remove":
+		if op.Path == "" {
+			return errors.New("remove requires path")
+		}
+	default:
+		return errors.Errorf("unknown operation %q", op.Op)
+	}
+	return nil
+}
+
+func applyBatchOp(ctx context.Context, tx store.Tx, op batchOp) error {
+	switch op.Op {
+	case "add":
+		return tx.Add(ctx, store.AddParams{
+			SecretIndex: store.SecretIndex{
+				Path: op.Path,
+				Key:  maybe.FromPtr(op.Key),
+			},
+			Data: []byte(*op.Value),
+		})
+	case "import":
+		payload := make(map[string][]byte, len(op.Payload))
+		for k, v := range op.Payload {
+			payload[k] = []byte(v)
+		}
+		return tx.Import(ctx, store.ImportParams{
+			Path:    op.Path,
+			Payload: payload,
+			Replace: op.Replace,
+		})
+	case "copy":
+		return tx.Copy(ctx, store.CopyParams{Src: op.Src, Dst: op.Dst})
+	case "move":
+		return tx.Move(ctx, store.MoveParams{Src: op.Src, Dst: op.Dst})
+	case "remove":
+		return tx.Remove(ctx, store.RemoveParams{
+			Path: op.Path,
+			Key:  maybe.FromPtr(op.Key),
+		})
+	default:
+		return errors.Errorf("unknown operation %q", op.Op)
+	}
+}
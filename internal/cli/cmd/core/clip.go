@@ -0,0 +1,27 @@
+package core
+
+import (
+	"github.com/urfave/cli/v2"
+
+	"github.com/UsingCoding/gostore/internal/gostore/infrastructure/clipboard"
+)
+
+func clipFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:    "clip",
+			Aliases: []string{"c"},
+			Usage:   "Copy value to clipboard instead of printing",
+		},
+		&cli.DurationFlag{
+			Name:    "clip-timeout",
+			Usage:   "Clear clipboard after timeout if it still holds copied value, 0 disables clearing",
+			Value:   clipboard.DefaultClearTimeout,
+			EnvVars: []string{"GOSTORE_CLIP_TIMEOUT"},
+		},
+	}
+}
+
+func copyToClipboard(ctx *cli.Context, data []byte) error {
+	return clipboard.Copy(data, ctx.Duration("clip-timeout"))
+}
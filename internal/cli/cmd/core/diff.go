@@ -0,0 +1,191 @@
+package core
+
+import (
+	"encoding/json"
+	"os"
+	stdslices "slices"
+	"strings"
+
+	"github.com/UsingCoding/fpgo/pkg/slices"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+
+	clipkg "github.com/UsingCoding/gostore/internal/cli"
+	"github.com/UsingCoding/gostore/internal/cli/cmd"
+	"github.com/UsingCoding/gostore/internal/cli/completion"
+	"github.com/UsingCoding/gostore/internal/common/maybe"
+	"github.com/UsingCoding/gostore/internal/gostore/app/output"
+	"github.com/UsingCoding/gostore/internal/gostore/app/store"
+	"github.com/UsingCoding/gostore/internal/gostore/infrastructure/consoleoutput"
+)
+
+const (
+	maxDiffRevisions = 2
+)
+
+func diff() *cli.Command {
+	return &cli.Command{
+		Name:  "diff",
+		Usage: "Show changed secrets and keys between revisions",
+		UsageText: `diff [<rev1> [<rev2>]] [path]
+
+Without revisions shows uncommitted changes. With single revision compares it with current state.
+Values are hidden unless --show-values passed`,
+		Category: cmd.CoreCategory,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "show-values",
+				Usage: "Print decrypted values of changed keys",
+			},
+		},
+		Action:       executeDiff,
+		BashComplete: completion.ListCompletion(""),
+	}
+}
+
+func executeDiff(ctx *cli.Context) error {
+	service := clipkg.ContainerScope.MustGet(ctx.Context).StoreService
+
+	args := ctx.Args().Slice()
+	if len(args) > maxDiffRevisions+1 {
+		return errors.New("too many arguments")
+	}
+
+	paths, err := storePaths(ctx, service)
+	if err != nil {
+		return err
+	}
+
+	var revisions []string
+	for len(args) > 0 && len(revisions) < maxDiffRevisions {
+		if len(args) == 1 && containsPath(paths, args[0]) {
+			// existing path wins over short revision with same prefix
+			break
+		}
+
+		r, err := service.ResolveRevision(ctx.Context, args[0])
+		if err != nil {
+			return err
+		}
+		if !maybe.Valid(r) {
+			break
+		}
+
+		revisions = append(revisions, maybe.Just(r))
+		args = args[1:]
+	}
+
+	if len(args) > 1 {
+		return errors.Errorf("unknown revision %s", args[0])
+	}
+
+	params := store.DiffParams{
+		ShowValues: ctx.Bool("show-values"),
+	}
+	if len(args) == 1 {
+		params.Path = args[0]
+	}
+	if len(revisions) > 0 {
+		params.From = maybe.NewJust(revisions[0])
+	}
+	if len(revisions) > 1 {
+		params.To = maybe.NewJust(revisions[1])
+	}
+
+	diffs, err := service.Diff(ctx.Context, params)
+	if err != nil {
+		return err
+	}
+
+	o := consoleoutput.New(os.Stdout, consoleoutput.WithNewline(true))
+
+	switch output.FromCtx(ctx.Context) {
+	case output.JSON:
+		data, err2 := json.Marshal(slices.Map(diffs, func(d store.SecretDiff) jsonSecretDiff {
+			return jsonSecretDiff{
+				Path:     d.Path,
+				Status:   string(d.Status),
+				NoAccess: d.NoAccess,
+				Keys: slices.Map(d.Keys, func(k store.KeyDiff) jsonKeyDiff {
+					return jsonKeyDiff{
+						Name:   k.Name,
+						Status: string(k.Status),
+						Old:    maybe.ToPtr(maybe.Map(k.Old, bytesToString)),
+						New:    maybe.ToPtr(maybe.Map(k.New, bytesToString)),
+					}
+				}),
+			}
+		}))
+		if err2 != nil {
+			return errors.Wrap(err2, "failed to marshal diff")
+		}
+
+		o.Printf("%s", data)
+	default:
+		for _, d := range diffs {
+			if d.NoAccess {
+				o.Printf("%s %s (no access, values not compared)", diffStatusSign(d.Status), d.Path)
+			} else {
+				o.Printf("%s %s", diffStatusSign(d.Status), d.Path)
+			}
+
+			for _, k := range d.Keys {
+				o.Printf("  %s %s", diffStatusSign(k.Status), k.Name)
+
+				if v, ok := maybe.JustValid(k.Old); ok {
+					o.Printf("    - %s", v)
+				}
+				if v, ok := maybe.JustValid(k.New); ok {
+					o.Printf("    + %s", v)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func storePaths(ctx *cli.Context, service store.Service) ([]string, error) {
+	tree, err := service.List(ctx.Context, store.ListParams{})
+	if err != nil {
+		return nil, err
+	}
+
+	return tree.Inline().Keys(), nil
+}
+
+func containsPath(paths []string, p string) bool {
+	p = strings.Trim(p, "/")
+	return stdslices.ContainsFunc(paths, func(s string) bool {
+		return s == p || strings.HasPrefix(s, p+"/")
+	})
+}
+
+func diffStatusSign(status store.DiffStatus) string {
+	switch status {
+	case store.DiffAdded:
+		return "+"
+	case store.DiffRemoved:
+		return "-"
+	default:
+		return "~"
+	}
+}
+
+type jsonSecretDiff struct {
+	Path     string        `json:"path"`
+	Status   string        `json:"status"`
+	Keys     []jsonKeyDiff `json:"keys"`
+	NoAccess bool          `json:"noAccess,omitempty"`
+}
+
+type jsonKeyDiff struct {
+	Name   string  `json:"name"`
+	Status string  `json:"status"`
+	Old    *string `json:"old,omitempty"`
+	New    *string `json:"new,omitempty"`
+}
+
+func bytesToString(b []byte) string {
+	return string(b)
+}
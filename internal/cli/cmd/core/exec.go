@@ -0,0 +1,89 @@
+package core
+
+import (
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+
+	clipkg "github.com/UsingCoding/gostore/internal/cli"
+	"github.com/UsingCoding/gostore/internal/cli/cmd"
+	"github.com/UsingCoding/gostore/internal/gostore/app/usecase/env"
+	"github.com/UsingCoding/gostore/internal/gostore/infrastructure/process"
+)
+
+func execCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "exec",
+		Usage:     "Run command with secrets as environment variables",
+		UsageText: "exec [--env NAME=PATH[:KEY]]... [--env-from PATH]... -- <COMMAND> [ARGS]...",
+		Description: "Keys of secrets from --env-from exported in upper case, default value named by secret. " +
+			"Values passed to command environment and never written to disk",
+		Category: cmd.CoreCategory,
+		Action:   executeExec,
+		Flags: []cli.Flag{
+			&cli.StringSliceFlag{
+				Name:    "env",
+				Aliases: []string{"e"},
+				Usage:   "Export secret value as variable: NAME=PATH[:KEY]",
+			},
+			&cli.StringSliceFlag{
+				Name:  "env-from",
+				Usage: "Export all keys of secret as variables",
+			},
+		},
+	}
+}
+
+func executeExec(ctx *cli.Context) error {
+	if ctx.Args().Len() < 1 {
+		return errors.New("no command to run")
+	}
+
+	service := clipkg.ContainerScope.MustGet(ctx.Context).Env
+
+	vars, err := service.Resolve(ctx.Context, env.ResolveParams{
+		Vars: ctx.StringSlice("env"),
+		From: ctx.StringSlice("env-from"),
+	})
+	if err != nil {
+		return err
+	}
+
+	return process.Exec(
+		ctx.Args().First(),
+		ctx.Args().Tail(),
+		mergeEnv(os.Environ(), vars),
+	)
+}
+
+// mergeEnv overrides variables of environ since duplicated names resolved differently by programs
+func mergeEnv(environ []string, vars []env.Var) []string {
+	names := map[string]struct{}{}
+	for _, v := range vars {
+		names[v.Name] = struct{}{}
+	}
+
+	res := make([]string, 0, len(environ)+len(vars))
+	for _, e := range environ {
+		name, _, _ := strings.Cut(e, "=")
+		if _, ok := names[name]; ok {
+			continue
+		}
+		res = append(res, e)
+	}
+
+	// later vars override earlier ones
+	seen := map[string]int{}
+	for _, v := range vars {
+		if i, ok := seen[v.Name]; ok {
+			res[i] = v.String()
+			continue
+		}
+		seen[v.Name] = len(res)
+		res = append(res, v.String())
+	}
+
+	return res
+}
@@ -0,0 +1,199 @@
+package core
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/term"
+
+	clipkg "github.com/UsingCoding/gostore/internal/cli"
+	"github.com/UsingCoding/gostore/internal/cli/cmd"
+	"github.com/UsingCoding/gostore/internal/gostore/app/output"
+	"github.com/UsingCoding/gostore/internal/gostore/app/store"
+	"github.com/UsingCoding/gostore/internal/gostore/infrastructure/consoleoutput"
+)
+
+func find() *cli.Command {
+	return &cli.Command{
+		Name:      "find",
+		Aliases:   []string{"search"},
+		Usage:     "Find secrets by path, key names and tags",
+		UsageText: "find [--regex] [--grep [--yes]] [--path PATH] <QUERY>",
+		Description: "Query is glob pattern, query without glob symbols matches as substring. " +
+			"With --grep decrypted values are searched too, so all secrets are decrypted",
+		Category: cmd.CoreCategory,
+		Action:   executeFind,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:    "regex",
+				Aliases: []string{"e"},
+				Usage:   "Interpret query as regular expression",
+			},
+			&cli.StringFlag{
+				Name:  "path",
+				Usage: "Search only in subtree",
+			},
+			&cli.BoolFlag{
+				Name:  "grep",
+				Usage: "Search in decrypted values too",
+			},
+			&cli.BoolFlag{
+				Name:    "yes",
+				Aliases: []string{"y"},
+				Usage:   "Do not ask confirmation to decrypt secrets for --grep",
+			},
+		},
+	}
+}
+
+func executeFind(ctx *cli.Context) error {
+	if ctx.Args().Len() < 1 {
+		return errors.New("not enough arguments")
+	}
+
+	matcher, err := newMatcher(ctx.Args().Get(0), ctx.Bool("regex"))
+	if err != nil {
+		return err
+	}
+
+	grep := ctx.Bool("grep")
+	if grep && !ctx.Bool("yes") {
+		err = confirmDecrypt()
+		if err != nil {
+			return err
+		}
+	}
+
+	service := clipkg.ContainerScope.MustGet(ctx.Context).StoreService
+
+	results, err := service.Find(ctx.Context, store.FindParams{
+		Path:    ctx.String("path"),
+		Matcher: matcher,
+		Grep:    grep,
+	})
+	if err != nil {
+		return err
+	}
+
+	o := consoleoutput.New(os.Stdout, consoleoutput.WithNewline(true))
+
+	if output.FromCtx(ctx.Context) == output.JSON {
+		res := make([]jsonFindResult, 0, len(results))
+		for _, r := range results {
+			res = append(res, jsonFindResult{
+				Path:        r.Path,
+				PathMatched: r.PathMatched,
+				Keys:        r.Keys,
+				Tags:        r.Tags,
+				Values:      r.Values,
+				NoAccess:    r.NoAccess,
+			})
+		}
+
+		data, err2 := json.Marshal(res)
+		if err2 != nil {
+			return errors.Wrap(err2, "failed to marshal results")
+		}
+
+		o.Printf("%s", data)
+		return nil
+	}
+
+	for _, r := range results {
+		line := r.Path
+		if len(r.Keys) > 0 {
+			line += fmt.Sprintf(" keys: %s", strings.Join(r.Keys, ", "))
+		}
+		if len(r.Tags) > 0 {
+			line += fmt.Sprintf(" tags: %s", strings.Join(r.Tags, ", "))
+		}
+		if len(r.Values) > 0 {
+			line += fmt.Sprintf(" values: %s", strings.Join(r.Values, ", "))
+		}
+		if r.NoAccess {
+			line += " (no access, values not searched)"
+		}
+		o.Printf("%s", line)
+	}
+
+	return nil
+}
+
+func confirmDecrypt() error {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return errors.New("searching values decrypts all secrets, pass --yes to confirm")
+	}
+
+	_, _ = fmt.Fprint(os.Stderr, "Searching values decrypts all secrets, continue? [y/N]: ")
+
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return errors.Wrap(err, "failed to read confirmation")
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return nil
+	default:
+		return errors.New("search cancelled")
+	}
+}
+
+func newMatcher(query string, regex bool) (store.Matcher, error) {
+	if regex {
+		r, err := regexp.Compile(query)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid regular expression")
+		}
+		return regexMatcher{r}, nil
+	}
+
+	if !strings.ContainsAny(query, "*?[") {
+		return substringMatcher(query), nil
+	}
+
+	// validate pattern once, path.Match reports bad pattern only on matching
+	_, err := path.Match(query, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid glob pattern")
+	}
+
+	return globMatcher(query), nil
+}
+
+type regexMatcher struct {
+	*regexp.Regexp
+}
+
+func (m regexMatcher) Match(s string) bool {
+	return m.MatchString(s)
+}
+
+type substringMatcher string
+
+func (m substringMatcher) Match(s string) bool {
+	return strings.Contains(s, string(m))
+}
+
+type globMatcher string
+
+func (m globMatcher) Match(s string) bool {
+	matched, _ := path.Match(string(m), s)
+	return matched
+}
+
+type jsonFindResult struct {
+	Path        string   `json:"path"`
+	PathMatched bool     `json:"pathMatched"`
+	Keys        []string `json:"keys,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Values      []string `json:"values,omitempty"`
+	NoAccess    bool     `json:"noAccess,omitempty"`
+}
@@ -0,0 +1,70 @@
+package core
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+
+	clipkg "github.com/UsingCoding/gostore/internal/cli"
+	"github.com/UsingCoding/gostore/internal/cli/cmd"
+	"github.com/UsingCoding/gostore/internal/cli/completion"
+	"github.com/UsingCoding/gostore/internal/common/maybe"
+	"github.com/UsingCoding/gostore/internal/gostore/app/store"
+	"github.com/UsingCoding/gostore/internal/gostore/app/usecase/generate"
+	"github.com/UsingCoding/gostore/internal/gostore/infrastructure/consoleoutput"
+)
+
+func generateCmd() *cli.Command {
+	return &cli.Command{
+		Name:         "generate",
+		Aliases:      []string{"gen"},
+		Usage:        "Generate password or passphrase and add it to store",
+		UsageText:    "generate [--length N] [--charset CHARS] [--words N | --passphrase] [--clip] <PATH> ?<KEY>",
+		Description:  "Password policy of store used for options not passed, see `store policy`",
+		Category:     cmd.CoreCategory,
+		Action:       executeGenerate,
+		BashComplete: completion.ListCompletion(""),
+		Flags:        generateFlags(),
+	}
+}
+
+func generateFlags() []cli.Flag {
+	return append(cmd.PasswordFlags(), clipFlags()...)
+}
+
+func executeGenerate(ctx *cli.Context) error {
+	if ctx.Args().Len() < 1 {
+		return errors.New("not enough arguments")
+	}
+	path := ctx.Args().Get(0)
+
+	var key maybe.Maybe[string]
+	if ctx.Args().Len() > 1 {
+		key = maybe.NewJust(ctx.Args().Get(1))
+	}
+
+	service := clipkg.ContainerScope.MustGet(ctx.Context).Generate
+
+	data, err := service.Generate(ctx.Context, generate.GenerateParams{
+		SecretIndex: store.SecretIndex{
+			Path: path,
+			Key:  key,
+		},
+		PasswordParams: cmd.PasswordParams(ctx),
+	})
+	if err != nil {
+		return err
+	}
+
+	return outputGenerated(ctx, data)
+}
+
+func outputGenerated(ctx *cli.Context, data []byte) error {
+	if ctx.Bool("clip") {
+		return copyToClipboard(ctx, data)
+	}
+
+	consoleoutput.New(os.Stdout, consoleoutput.WithNewline(true)).Printf("%s", data)
+	return nil
+}
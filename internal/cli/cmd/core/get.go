@@ -1,8 +1,9 @@
 package core
 
 import (
-	"fmt"
+	"encoding/json"
 	"os"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/urfave/cli/v2"
@@ -11,7 +12,9 @@ import (
 	clipkg "github.com/UsingCoding/gostore/internal/cli"
 	"github.com/UsingCoding/gostore/internal/cli/cmd"
 	"github.com/UsingCoding/gostore/internal/cli/completion"
+	"github.com/UsingCoding/gostore/internal/gostore/app/output"
 	"github.com/UsingCoding/gostore/internal/gostore/infrastructure/consoleoutput"
+	"github.com/UsingCoding/gostore/internal/gostore/infrastructure/secretformat"
 
 	"github.com/UsingCoding/gostore/internal/common/maybe"
 	"github.com/UsingCoding/gostore/internal/gostore/app/store"
@@ -25,6 +28,13 @@ func get() *cli.Command {
 		Category:     cmd.CoreCategory,
 		Action:       executeGet,
 		BashComplete: completion.ListCompletion(""),
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{
+				Name:  "at",
+				Usage: "Get secret at revision or date (2006-01-02, 2006-01-02 15:04:05, RFC3339)",
+			},
+			formatFlag(false),
+		}, clipFlags()...),
 	}
 }
 
@@ -48,11 +58,14 @@ func executeGet(ctx *cli.Context) error {
 
 	service := clipkg.ContainerScope.MustGet(ctx.Context).StoreService
 
+	at := maybe.Map(maybe.MapZero(ctx.String("at")), parseHistoryPoint)
+
 	secretsData, err := service.Get(ctx.Context, store.GetParams{
 		SecretIndex: store.SecretIndex{
 			Path: path,
 			Key:  key,
 		},
+		At: at,
 	})
 	if err != nil {
 		return err
@@ -62,24 +75,119 @@ func executeGet(ctx *cli.Context) error {
 		return errors.New("no secret payload found")
 	}
 
+	if f := ctx.String("format"); f != "" {
+		format, err2 := secretformat.Parse(f)
+		if err2 != nil {
+			return err2
+		}
+
+		payload := make(map[string][]byte, len(secretsData))
+		for _, data := range secretsData {
+			payload[data.Name] = data.Payload
+		}
+
+		data, err2 := secretformat.Encode(format, payload)
+		if err2 != nil {
+			return err2
+		}
+
+		_, err = os.Stdout.Write(data)
+		return errors.Wrap(err, "failed to write secret")
+	}
+
+	if ctx.Bool("clip") {
+		// specific key requested or secret holds only one value
+		if !maybe.Valid(key) && len(secretsData) > 1 {
+			return errors.New("secret has several keys, pass key to copy")
+		}
+
+		err = copyToClipboard(ctx, secretsData[0].Payload)
+		if err != nil {
+			return err
+		}
+
+		msg := consoleoutput.New(os.Stderr, consoleoutput.WithNewline(true))
+		if timeout := ctx.Duration("clip-timeout"); timeout > 0 {
+			msg.Printf("Copied %s to clipboard, clears in %s", path, timeout)
+		} else {
+			msg.Printf("Copied %s to clipboard", path)
+		}
+		return nil
+	}
+
+	if output.FromCtx(ctx.Context) == output.JSON {
+		m, err2 := service.Metadata(ctx.Context, store.MetadataParams{
+			Path: path,
+			At:   at,
+		})
+		if err2 != nil {
+			return err2
+		}
+
+		s := jsonSecret{
+			Path:     path,
+			Payload:  map[string]string{},
+			Metadata: toJSONMetadata(maybe.Just(m)),
+		}
+		for _, data := range secretsData {
+			s.Payload[data.Name] = string(data.Payload)
+		}
+
+		data, err2 := json.Marshal(s)
+		if err2 != nil {
+			return errors.Wrap(err2, "failed to marshal secret")
+		}
+
+		consoleoutput.New(os.Stdout, consoleoutput.WithNewline(true)).Printf("%s", data)
+		return nil
+	}
+
 	// if there is only one data in secret print it without kv formatting
 	if len(secretsData) == 1 && secretsData[0].Default {
 		s := secretsData[0]
-		o.Printf(string(s.Payload))
+		o.Printf("%s", s.Payload)
 		return nil
 	}
 
 	// there is request for specific key in secret, print it without kv formatting
 	if maybe.Valid(key) {
 		s := secretsData[0]
-		o.Printf(string(s.Payload))
+		o.Printf("%s", s.Payload)
 		return nil
 	}
 
 	for _, data := range secretsData {
-		msg := fmt.Sprintf("%s: %s", data.Name, data.Payload)
-		o.Printf(msg)
+		o.Printf("%s: %s", data.Name, data.Payload)
 	}
 
 	return nil
 }
+
+type jsonSecret struct {
+	Path     string            `json:"path"`
+	Payload  map[string]string `json:"payload"`
+	Metadata jsonMetadata      `json:"metadata"`
+}
+
+var historyTimeLayouts = []string{
+	time.RFC3339,
+	time.DateTime,
+	"2006-01-02 15:04",
+	time.DateOnly,
+}
+
+// parseHistoryPoint interprets s as date in local timezone or as revision
+func parseHistoryPoint(s string) store.HistoryPoint {
+	for _, layout := range historyTimeLayouts {
+		t, err := time.ParseInLocation(layout, s, time.Local)
+		if err == nil {
+			if layout == time.DateOnly {
+				// date means state at the end of the day
+				t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+			}
+			return store.HistoryPoint{Time: maybe.NewJust(t)}
+		}
+	}
+
+	return store.HistoryPoint{Revision: maybe.NewJust(s)}
+}
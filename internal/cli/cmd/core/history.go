@@ -0,0 +1,94 @@
+package core
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/UsingCoding/fpgo/pkg/slices"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+
+	clipkg "github.com/UsingCoding/gostore/internal/cli"
+	"github.com/UsingCoding/gostore/internal/cli/cmd"
+	"github.com/UsingCoding/gostore/internal/cli/completion"
+	"github.com/UsingCoding/gostore/internal/gostore/app/output"
+	"github.com/UsingCoding/gostore/internal/gostore/app/storage"
+	"github.com/UsingCoding/gostore/internal/gostore/app/store"
+	"github.com/UsingCoding/gostore/internal/gostore/infrastructure/consoleoutput"
+)
+
+const (
+	shortRevisionLen = 8
+)
+
+func history() *cli.Command {
+	return &cli.Command{
+		Name:         "history",
+		Aliases:      []string{"log"},
+		Usage:        "List revisions changed secret",
+		UsageText:    "history <path>",
+		Category:     cmd.CoreCategory,
+		Action:       executeHistory,
+		BashComplete: completion.ListCompletion(""),
+	}
+}
+
+func executeHistory(ctx *cli.Context) error {
+	if ctx.Args().Len() < 1 {
+		return errors.New("not enough arguments")
+	}
+	path := ctx.Args().Get(0)
+
+	service := clipkg.ContainerScope.MustGet(ctx.Context).StoreService
+
+	revisions, err := service.History(ctx.Context, store.HistoryParams{
+		Path: path,
+	})
+	if err != nil {
+		return err
+	}
+
+	o := consoleoutput.New(os.Stdout, consoleoutput.WithNewline(true))
+
+	switch output.FromCtx(ctx.Context) {
+	case output.JSON:
+		data, err2 := json.Marshal(slices.Map(revisions, func(r storage.Revision) jsonRevision {
+			return jsonRevision{
+				ID:      r.ID,
+				Author:  r.Author,
+				Time:    r.Time,
+				Message: r.Message,
+			}
+		}))
+		if err2 != nil {
+			return errors.Wrap(err2, "failed to marshal history")
+		}
+
+		o.Printf("%s", data)
+	default:
+		for _, r := range revisions {
+			id := r.ID
+			if len(id) > shortRevisionLen {
+				id = id[:shortRevisionLen]
+			}
+
+			o.Printf(
+				"%s %s %s %s",
+				id,
+				r.Time.Local().Format(time.DateTime),
+				r.Author,
+				r.Message,
+			)
+		}
+	}
+
+	return nil
+}
+
+type jsonRevision struct {
+	ID      string    `json:"id"`
+	Author  string    `json:"author"`
+	Time    time.Time `json:"time"`
+	Message string    `json:"message"`
+}
@@ -0,0 +1,76 @@
+package core
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"github.com/UsingCoding/fpgo/pkg/slices"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+
+	clipkg "github.com/UsingCoding/gostore/internal/cli"
+	"github.com/UsingCoding/gostore/internal/cli/cmd"
+	"github.com/UsingCoding/gostore/internal/cli/completion"
+	"github.com/UsingCoding/gostore/internal/gostore/app/store"
+	"github.com/UsingCoding/gostore/internal/gostore/infrastructure/secretformat"
+)
+
+func importCmd() *cli.Command {
+	return &cli.Command{
+		Name:         "import",
+		Usage:        "Import keys of composite secret from stdin in one change",
+		UsageText:    "import --format dotenv|json|yaml [--replace] <PATH> < FILE",
+		Category:     cmd.CoreCategory,
+		Action:       executeImport,
+		BashComplete: completion.ListCompletion(""),
+		Flags: []cli.Flag{
+			formatFlag(true),
+			&cli.BoolFlag{
+				Name:  "replace",
+				Usage: "Remove keys of secret missing in imported document",
+			},
+		},
+	}
+}
+
+func formatFlag(required bool) cli.Flag {
+	return &cli.StringFlag{
+		Name:     "format",
+		Aliases:  []string{"f"},
+		Required: required,
+		Usage: "Document format: " + strings.Join(slices.Map(secretformat.Formats, func(f secretformat.Format) string {
+			return string(f)
+		}), "|"),
+	}
+}
+
+func executeImport(ctx *cli.Context) error {
+	if ctx.Args().Len() < 1 {
+		return errors.New("not enough arguments")
+	}
+	path := ctx.Args().Get(0)
+
+	format, err := secretformat.Parse(ctx.String("format"))
+	if err != nil {
+		return err
+	}
+
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return errors.Wrap(err, "failed to read from stdin")
+	}
+
+	payload, err := secretformat.Decode(format, data)
+	if err != nil {
+		return err
+	}
+
+	service := clipkg.ContainerScope.MustGet(ctx.Context).StoreService
+
+	return service.Import(ctx.Context, store.ImportParams{
+		Path:    path,
+		Payload: payload,
+		Replace: ctx.Bool("replace"),
+	})
+}
@@ -3,6 +3,8 @@ package core
 import (
 	"encoding/json"
 	"os"
+	"path"
+	"strings"
 
 	"github.com/UsingCoding/fpgo/pkg/slices"
 	"github.com/pkg/errors"
@@ -25,25 +27,57 @@ func list() *cli.Command {
 		Category: cmd.CoreCategory,
 		Usage:    "List secrets in current store",
 		Action:   executeList,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:    "long",
+				Aliases: []string{"l"},
+				Usage:   "Show secrets metadata",
+			},
+		},
 	}
 }
 
 func executeList(ctx *cli.Context) error {
-	var path string
+	var p string
 	if ctx.Args().Len() > 0 {
-		path = ctx.Args().Get(0)
+		p = ctx.Args().Get(0)
 	}
 
 	service := clipkg.ContainerScope.MustGet(ctx.Context).StoreService
 	configService := clipkg.ContainerScope.MustGet(ctx.Context).C
 
 	tree, err := service.List(ctx.Context, store.ListParams{
-		Path: path,
+		Path: p,
 	})
 	if err != nil {
 		return err
 	}
 
+	var metadata map[string]store.Metadata
+	if ctx.Bool("long") {
+		metadata, err = service.ListMetadata(ctx.Context, store.ListParams{
+			Path: p,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// paths relative to listed path which secrets current identities can not decrypt
+	base := strings.Trim(p, "/")
+	paths := append(treePaths(tree, ""), "")
+	access, err := service.Accessible(ctx.Context, slices.Map(paths, func(rel string) string {
+		return path.Join(base, rel)
+	}))
+	if err != nil {
+		return err
+	}
+
+	denied := map[string]bool{}
+	for _, rel := range paths {
+		denied[rel] = !access[path.Join(base, rel)]
+	}
+
 	currentStoreID, err := configService.CurrentStoreID(ctx.Context)
 	if err != nil {
 		return err
@@ -53,15 +87,16 @@ func executeList(ctx *cli.Context) error {
 
 	// just value without check since to use service.List we already has store in context
 	root := string(maybe.Just(currentStoreID))
-	if path != "" {
-		root = path
+	if p != "" {
+		root = p
 	}
 
 	switch output.FromCtx(ctx.Context) {
 	case output.JSON:
 		rootNode := jsonTreeNode{
-			Name:  root,
-			Elems: recursiveJSONList(tree),
+			Name:     root,
+			Elems:    recursiveJSONList(tree, "", metadata, denied),
+			NoAccess: denied[""],
 		}
 
 		data, err2 := json.Marshal(rootNode)
@@ -72,35 +107,100 @@ func executeList(ctx *cli.Context) error {
 		o.Printf(string(data))
 	default:
 		treePrinter := treeprint.NewWithRoot(root)
+		if denied[""] {
+			treePrinter.SetMetaValue(noAccessMeta)
+		}
 
-		recursiveList(treePrinter, tree)
+		recursiveList(treePrinter, tree, "", metadata, denied)
 		_, _ = os.Stdout.WriteString(treePrinter.String())
 	}
 
 	return nil
 }
 
-func recursiveList(treePrinter treeprint.Tree, tree storage.Tree) {
+const noAccessMeta = "no access"
+
+// recursiveList prints tree, metadata is printed for secrets when it passed.
+// Entries which secrets can not be decrypted are marked
+func recursiveList(
+	treePrinter treeprint.Tree,
+	tree storage.Tree,
+	parent string,
+	metadata map[string]store.Metadata,
+	denied map[string]bool,
+) {
 	for _, entry := range tree {
+		p := path.Join(parent, entry.Name)
+
 		if len(entry.Children) == 0 {
+			var meta []string
+			if denied[p] {
+				meta = append(meta, noAccessMeta)
+			}
+			if m, ok := metadata[p]; ok {
+				if short := shortMetadata(m); short != "" {
+					meta = append(meta, short)
+				}
+			}
+
+			if len(meta) != 0 {
+				treePrinter.AddMetaNode(strings.Join(meta, " "), entry.Name)
+				continue
+			}
+
 			treePrinter.AddNode(entry.Name)
 			continue
 		}
 
-		recursiveList(treePrinter.AddBranch(entry.Name), entry.Children)
+		branch := treePrinter.AddBranch(entry.Name)
+		if denied[p] {
+			branch.SetMetaValue(noAccessMeta)
+		}
+
+		recursiveList(branch, entry.Children, p, metadata, denied)
+	}
+}
+
+// treePaths returns paths of all entries of tree
+func treePaths(tree storage.Tree, parent string) []string {
+	var res []string
+	for _, entry := range tree {
+		p := path.Join(parent, entry.Name)
+		res = append(res, p)
+		res = append(res, treePaths(entry.Children, p)...)
 	}
+	return res
 }
 
-func recursiveJSONList(tree storage.Tree) []jsonTreeNode {
+func recursiveJSONList(
+	tree storage.Tree,
+	parent string,
+	metadata map[string]store.Metadata,
+	denied map[string]bool,
+) []jsonTreeNode {
 	return slices.Map(tree, func(e storage.Entry) jsonTreeNode {
+		p := path.Join(parent, e.Name)
+
+		var m *jsonMetadata
+		if len(e.Children) == 0 {
+			if secretMetadata, ok := metadata[p]; ok {
+				jm := toJSONMetadata(secretMetadata)
+				m = &jm
+			}
+		}
+
 		return jsonTreeNode{
-			Name:  e.Name,
-			Elems: recursiveJSONList(e.Children),
+			Name:     e.Name,
+			Elems:    recursiveJSONList(e.Children, p, metadata, denied),
+			Metadata: m,
+			NoAccess: denied[p],
 		}
 	})
 }
 
 type jsonTreeNode struct {
-	Name  string         `json:"name"`
-	Elems []jsonTreeNode `json:"children,omitempty"`
+	Name     string         `json:"name"`
+	Elems    []jsonTreeNode `json:"children,omitempty"`
+	Metadata *jsonMetadata  `json:"metadata,omitempty"`
+	NoAccess bool           `json:"noAccess,omitempty"`
 }
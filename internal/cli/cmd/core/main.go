@@ -7,11 +7,21 @@ import (
 func Main() []*cli.Command {
 	return []*cli.Command{
 		add(),
+		batch(),
 		copyCmd(),
+		diff(),
+		execCmd(),
 		get(),
+		history(),
+		importCmd(),
 		qrget(),
 		list(),
+		meta(),
+		find(),
+		generateCmd(),
 		move(),
 		remove(),
+		renderCmd(),
+		restore(),
 	}
 }
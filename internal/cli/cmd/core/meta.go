@@ -0,0 +1,147 @@
+package core
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+
+	clipkg "github.com/UsingCoding/gostore/internal/cli"
+	"github.com/UsingCoding/gostore/internal/cli/cmd"
+	"github.com/UsingCoding/gostore/internal/cli/completion"
+	"github.com/UsingCoding/gostore/internal/common/maybe"
+	"github.com/UsingCoding/gostore/internal/gostore/app/output"
+	"github.com/UsingCoding/gostore/internal/gostore/app/store"
+	"github.com/UsingCoding/gostore/internal/gostore/infrastructure/consoleoutput"
+)
+
+func meta() *cli.Command {
+	return &cli.Command{
+		Name:         "meta",
+		Usage:        "Show or update secret metadata: timestamps, author, tags and description",
+		UsageText:    "meta [--tag TAG]... [--clear-tags] [--description TEXT] <PATH>",
+		Category:     cmd.CoreCategory,
+		BashComplete: completion.ListCompletion(""),
+		Action:       executeMeta,
+		Flags: []cli.Flag{
+			&cli.StringSliceFlag{
+				Name:    "tag",
+				Aliases: []string{"t"},
+				Usage:   "Set tags of secret, replaces existing ones",
+			},
+			&cli.BoolFlag{
+				Name:  "clear-tags",
+				Usage: "Remove all tags of secret",
+			},
+			&cli.StringFlag{
+				Name:    "description",
+				Aliases: []string{"d"},
+				Usage:   "Set description of secret, empty removes it",
+			},
+		},
+	}
+}
+
+func executeMeta(ctx *cli.Context) error {
+	if ctx.Args().Len() < 1 {
+		return errors.New("not enough arguments")
+	}
+	path := ctx.Args().Get(0)
+
+	service := clipkg.ContainerScope.MustGet(ctx.Context).StoreService
+
+	params := store.SetMetadataParams{
+		Path: path,
+	}
+	if ctx.IsSet("tag") {
+		params.Tags = maybe.NewJust(ctx.StringSlice("tag"))
+	}
+	if ctx.Bool("clear-tags") {
+		params.Tags = maybe.NewJust[[]string](nil)
+	}
+	if ctx.IsSet("description") {
+		params.Description = maybe.NewJust(ctx.String("description"))
+	}
+
+	if maybe.Valid(params.Tags) || maybe.Valid(params.Description) {
+		return service.SetMetadata(ctx.Context, params)
+	}
+
+	m, err := service.Metadata(ctx.Context, store.MetadataParams{
+		Path: path,
+	})
+	if err != nil {
+		return err
+	}
+
+	metadata, ok := maybe.JustValid(m)
+	if !ok {
+		return errors.Errorf("secret %s not found", path)
+	}
+
+	o := consoleoutput.New(os.Stdout, consoleoutput.WithNewline(true))
+
+	if output.FromCtx(ctx.Context) == output.JSON {
+		data, err2 := json.Marshal(toJSONMetadata(metadata))
+		if err2 != nil {
+			return errors.Wrap(err2, "failed to marshal metadata")
+		}
+
+		o.Printf("%s", data)
+		return nil
+	}
+
+	if t, ok2 := maybe.JustValid(metadata.Created); ok2 {
+		o.Printf("Created: %s", t.Local().Format(time.DateTime))
+	}
+	if t, ok2 := maybe.JustValid(metadata.Updated); ok2 {
+		o.Printf("Updated: %s", t.Local().Format(time.DateTime))
+	}
+	if a, ok2 := maybe.JustValid(metadata.Author); ok2 {
+		o.Printf("Author: %s", a)
+	}
+	if len(metadata.Tags) > 0 {
+		o.Printf("Tags: %s", strings.Join(metadata.Tags, ", "))
+	}
+	if d, ok2 := maybe.JustValid(metadata.Description); ok2 {
+		o.Printf("Description: %s", d)
+	}
+
+	return nil
+}
+
+// shortMetadata formats metadata in one line
+func shortMetadata(m store.Metadata) string {
+	var parts []string
+	if t, ok := maybe.JustValid(m.Updated); ok {
+		parts = append(parts, t.Local().Format(time.DateTime))
+	}
+	if a, ok := maybe.JustValid(m.Author); ok {
+		parts = append(parts, a)
+	}
+	if len(m.Tags) > 0 {
+		parts = append(parts, "#"+strings.Join(m.Tags, " #"))
+	}
+	return strings.Join(parts, " ")
+}
+
+func toJSONMetadata(m store.Metadata) jsonMetadata {
+	return jsonMetadata{
+		Created:     maybe.ToPtr(m.Created),
+		Updated:     maybe.ToPtr(m.Updated),
+		Author:      maybe.ToPtr(m.Author),
+		Tags:        m.Tags,
+		Description: maybe.ToPtr(m.Description),
+	}
+}
+
+type jsonMetadata struct {
+	Created     *time.Time `json:"created,omitempty"`
+	Updated     *time.Time `json:"updated,omitempty"`
+	Author      *string    `json:"author,omitempty"`
+	Tags        []string   `json:"tags,omitempty"`
+	Description *string    `json:"description,omitempty"`
+}
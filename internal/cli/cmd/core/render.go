@@ -0,0 +1,55 @@
+package core
+
+import (
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+
+	clipkg "github.com/UsingCoding/gostore/internal/cli"
+	"github.com/UsingCoding/gostore/internal/cli/cmd"
+	"github.com/UsingCoding/gostore/internal/gostore/app/usecase/render"
+)
+
+func renderCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "render",
+		Usage:     "Render Go text/template with secrets to stdout",
+		UsageText: "render <TEMPLATE|->",
+		Description: "Template functions:\n" +
+			"  {{ secret \"PATH\" }} - default value of secret\n" +
+			"  {{ secret \"PATH\" \"KEY\" }} - value of secret key\n" +
+			"  {{ totp \"NAME\" }} - current TOTP passcode",
+		Category: cmd.CoreCategory,
+		Action:   executeRender,
+	}
+}
+
+func executeRender(ctx *cli.Context) error {
+	if ctx.Args().Len() < 1 {
+		return errors.New("not enough arguments")
+	}
+	name := ctx.Args().First()
+
+	var (
+		tmpl []byte
+		err  error
+	)
+	if name == "-" {
+		tmpl, err = io.ReadAll(os.Stdin)
+	} else {
+		tmpl, err = os.ReadFile(name)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to read template %s", name)
+	}
+
+	service := clipkg.ContainerScope.MustGet(ctx.Context).Render
+
+	return service.Render(ctx.Context, render.RenderParams{
+		Name:     name,
+		Template: tmpl,
+		Output:   os.Stdout,
+	})
+}
@@ -0,0 +1,43 @@
+package core
+
+import (
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+
+	clipkg "github.com/UsingCoding/gostore/internal/cli"
+	"github.com/UsingCoding/gostore/internal/cli/cmd"
+	"github.com/UsingCoding/gostore/internal/cli/completion"
+	"github.com/UsingCoding/gostore/internal/gostore/app/store"
+)
+
+func restore() *cli.Command {
+	return &cli.Command{
+		Name:         "restore",
+		Usage:        "Restore secret to previous revision",
+		UsageText:    "restore --to <revision|date> <path>",
+		Category:     cmd.CoreCategory,
+		Action:       executeRestore,
+		BashComplete: completion.ListCompletion(""),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "to",
+				Usage:    "Revision or date (2006-01-02, 2006-01-02 15:04:05, RFC3339) to restore secret to",
+				Required: true,
+			},
+		},
+	}
+}
+
+func executeRestore(ctx *cli.Context) error {
+	if ctx.Args().Len() < 1 {
+		return errors.New("not enough arguments")
+	}
+	path := ctx.Args().Get(0)
+
+	service := clipkg.ContainerScope.MustGet(ctx.Context).StoreService
+
+	return service.Restore(ctx.Context, store.RestoreParams{
+		Path: path,
+		To:   parseHistoryPoint(ctx.String("to")),
+	})
+}
@@ -36,7 +36,7 @@ func executeExport(ctx *cli.Context) error {
 	}
 
 	identities, err := service.ExportRawIdentity(ctx.Context, slices.Map(recipients, func(r string) encryption.Recipient {
-		return encryption.Recipient(r)
+		return encryption.ParseRecipient(r)
 	})...)
 	if err != nil {
 		return err
@@ -15,6 +15,7 @@ func Identity() []*cli.Command {
 			Subcommands: []*cli.Command{
 				export(),
 				importCmd(),
+				protect(),
 			},
 		},
 	}
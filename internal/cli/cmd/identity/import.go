@@ -16,12 +16,12 @@ func importCmd() *cli.Command {
 	return &cli.Command{
 		Name:      "import",
 		Usage:     "Import identity",
-		UsageText: "import < identity.plain",
+		UsageText: "import [FILE] < identity.plain",
 		Action:    executeImport,
 		Flags: []cli.Flag{
 			&cli.StringFlag{
 				Name:     "provider",
-				Usage:    "Identity provider: age",
+				Usage:    "Identity provider: age, ssh or pgp",
 				Required: true,
 				Aliases:  []string{"p"},
 			},
@@ -32,13 +32,21 @@ func importCmd() *cli.Command {
 func executeImport(ctx *cli.Context) error {
 	provider := ctx.String("provider")
 
-	data, err := io.ReadAll(stdos.Stdin)
+	var (
+		data []byte
+		err  error
+	)
+	if ctx.Args().Len() > 0 {
+		data, err = stdos.ReadFile(ctx.Args().First())
+	} else {
+		data, err = io.ReadAll(stdos.Stdin)
+	}
 	if err != nil {
 		return err
 	}
 
 	if len(data) == 0 {
-		return errors.New("empty stdin")
+		return errors.New("empty identity")
 	}
 
 	service := clipkg.ContainerScope.MustGet(ctx.Context).C
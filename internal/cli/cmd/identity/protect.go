@@ -0,0 +1,86 @@
+package identity
+
+import (
+	"bufio"
+	"bytes"
+	stdos "os"
+
+	"github.com/UsingCoding/fpgo/pkg/slices"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/term"
+
+	clipkg "github.com/UsingCoding/gostore/internal/cli"
+	"github.com/UsingCoding/gostore/internal/gostore/app/encryption"
+	"github.com/UsingCoding/gostore/internal/gostore/infrastructure/consoleoutput"
+	"github.com/UsingCoding/gostore/internal/gostore/infrastructure/passphrase"
+)
+
+func protect() *cli.Command {
+	return &cli.Command{
+		Name:      "protect",
+		Usage:     "Encrypt private keys of identities with passphrase",
+		UsageText: "protect [-r <RECIPIENT>]...",
+		Action:    executeProtect,
+		Flags: []cli.Flag{
+			&cli.StringSliceFlag{
+				Name:    "recipients",
+				Usage:   "Protect only identities of passed recipients, by default all unprotected identities are protected",
+				Aliases: []string{"r"},
+			},
+		},
+	}
+}
+
+func executeProtect(ctx *cli.Context) error {
+	p, err := readNewPassphrase()
+	if err != nil {
+		return err
+	}
+
+	service := clipkg.ContainerScope.MustGet(ctx.Context).C
+
+	err = service.ProtectIdentities(
+		ctx.Context,
+		p,
+		slices.Map(ctx.StringSlice("recipients"), func(r string) encryption.Recipient {
+			return encryption.ParseRecipient(r)
+		})...,
+	)
+	if err != nil {
+		return err
+	}
+
+	o := consoleoutput.New(stdos.Stdout, consoleoutput.WithNewline(true))
+	o.OKf("Identities protected")
+
+	return nil
+}
+
+func readNewPassphrase() ([]byte, error) {
+	if !term.IsTerminal(int(stdos.Stdin.Fd())) {
+		// read passphrase from first line of stdin
+		line, err := bufio.NewReader(stdos.Stdin).ReadBytes('\n')
+		if err != nil && len(line) == 0 {
+			return nil, errors.Wrap(err, "failed to read passphrase from stdin")
+		}
+
+		return bytes.TrimRight(line, "\r\n"), nil
+	}
+
+	p, err := passphrase.Prompt("Enter new passphrase: ")
+	if err != nil {
+		return nil, err
+	}
+
+	confirm, err := passphrase.Prompt("Confirm passphrase: ")
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(p, confirm) {
+		return nil, errors.New("passphrases do not match")
+	}
+
+	return p, nil
+}
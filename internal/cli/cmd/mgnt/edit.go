@@ -21,10 +21,17 @@ func edit() *cli.Command {
 	return &cli.Command{
 		Name:         "edit",
 		Usage:        "Edit secrets",
-		UsageText:    "edit <SECRET_ID> ?<KEY>",
+		UsageText:    "edit [--generate] <SECRET_ID> ?<KEY>",
 		Category:     cmd.MgmtCategory,
 		Action:       executeEdit,
 		BashComplete: completionpkg.ListCompletion(""),
+		Flags: append([]cli.Flag{
+			&cli.BoolFlag{
+				Name:    "generate",
+				Aliases: []string{"g"},
+				Usage:   "Open editor with generated password instead of current value, generate flags applied",
+			},
+		}, cmd.PasswordFlags()...),
 	}
 }
 
@@ -39,7 +46,7 @@ func executeEdit(ctx *cli.Context) error {
 		key = maybe.NewJust(ctx.Args().Get(1))
 	}
 
-	s := clipkg.ContainerScope.MustGet(ctx.Context).StoreService
+	container := clipkg.ContainerScope.MustGet(ctx.Context)
 
 	e, err := editor.NewEditor()
 	if err != nil {
@@ -48,10 +55,22 @@ func executeEdit(ctx *cli.Context) error {
 
 	o := consoleoutput.New(os.Stdout, consoleoutput.WithNewline(true))
 
-	err = appedit.NewService(s, e).Edit(ctx.Context, store.SecretIndex{
+	service := appedit.NewService(container.StoreService, e)
+	index := store.SecretIndex{
 		Path: path,
 		Key:  key,
-	})
+	}
+
+	if ctx.Bool("generate") {
+		data, err2 := container.Generate.Password(ctx.Context, cmd.PasswordParams(ctx))
+		if err2 != nil {
+			return err2
+		}
+
+		err = service.EditWith(ctx.Context, index, data)
+	} else {
+		err = service.Edit(ctx.Context, index)
+	}
 	if errors.Is(err, appedit.ErrNoChangesMade) {
 		o.Printf("No changes made")
 		return nil
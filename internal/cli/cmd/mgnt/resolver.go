@@ -0,0 +1,45 @@
+package mgnt
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/UsingCoding/gostore/internal/gostore/app/store"
+)
+
+const (
+	ttyPath = "/dev/tty"
+)
+
+// promptResolver asks user in terminal which side of conflict to keep
+type promptResolver struct{}
+
+func (promptResolver) Resolve(_ context.Context, conflict store.Conflict) (store.Resolution, error) {
+	tty, err := os.OpenFile(ttyPath, os.O_RDWR, 0)
+	if err != nil {
+		return "", errors.Wrap(err, "no terminal to resolve conflict")
+	}
+	defer tty.Close()
+
+	r := bufio.NewReader(tty)
+	for {
+		_, _ = fmt.Fprintf(tty, "Conflicting changes in %s, keep [o]urs or [t]heirs: ", conflict)
+
+		line, err2 := r.ReadString('\n')
+		if err2 != nil {
+			return "", errors.Wrap(err2, "failed to read answer")
+		}
+
+		switch strings.ToLower(strings.TrimSpace(line)) {
+		case "o", string(store.ResolutionOurs):
+			return store.ResolutionOurs, nil
+		case "t", string(store.ResolutionTheirs):
+			return store.ResolutionTheirs, nil
+		}
+	}
+}
@@ -4,9 +4,12 @@ import (
 	"os"
 
 	"github.com/urfave/cli/v2"
+	"golang.org/x/term"
 
 	clipkg "github.com/UsingCoding/gostore/internal/cli"
 	"github.com/UsingCoding/gostore/internal/cli/cmd"
+	"github.com/UsingCoding/gostore/internal/common/maybe"
+	"github.com/UsingCoding/gostore/internal/gostore/app/store"
 	"github.com/UsingCoding/gostore/internal/gostore/infrastructure/consoleoutput"
 )
 
@@ -15,10 +18,23 @@ func sync() *cli.Command {
 		Name:     "sync",
 		Usage:    "Sync store with remote",
 		Category: cmd.MgmtCategory,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:    "interactive",
+				Aliases: []string{"i"},
+				Usage:   "Prompt to resolve conflicting changes, otherwise sync fails listing conflicts",
+				Value:   term.IsTerminal(int(os.Stdin.Fd())),
+			},
+		},
 		Action: func(ctx *cli.Context) error {
 			service := clipkg.ContainerScope.MustGet(ctx.Context).StoreService
 
-			err := service.Sync(ctx.Context)
+			var params store.SyncParams
+			if ctx.Bool("interactive") {
+				params.ConflictResolver = maybe.NewJust[store.ConflictResolver](promptResolver{})
+			}
+
+			err := service.Sync(ctx.Context, params)
 			if err != nil {
 				return err
 			}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"github.com/urfave/cli/v2"
+
+	"github.com/UsingCoding/gostore/internal/common/maybe"
+	"github.com/UsingCoding/gostore/internal/gostore/app/usecase/generate"
+)
+
+// PasswordFlags configure generated password, store password policy used for flags not passed
+func PasswordFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.IntFlag{
+			Name:    "length",
+			Aliases: []string{"n"},
+			Usage:   "Password length",
+		},
+		&cli.StringFlag{
+			Name:  "charset",
+			Usage: "Characters to build password from instead of policy character classes",
+		},
+		&cli.IntFlag{
+			Name:  "words",
+			Usage: "Generate diceware passphrase with words count",
+		},
+		&cli.BoolFlag{
+			Name:  "passphrase",
+			Usage: "Generate diceware passphrase with words count of store policy",
+		},
+	}
+}
+
+func PasswordParams(ctx *cli.Context) generate.PasswordParams {
+	return generate.PasswordParams{
+		Length:     maybe.MapZero(ctx.Int("length")),
+		Charset:    maybe.MapZero(ctx.String("charset")),
+		Words:      maybe.MapZero(ctx.Int("words")),
+		Passphrase: ctx.Bool("passphrase"),
+	}
+}
@@ -0,0 +1,107 @@
+package recipients
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/UsingCoding/fpgo/pkg/slices"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+
+	clipkg "github.com/UsingCoding/gostore/internal/cli"
+	"github.com/UsingCoding/gostore/internal/gostore/app/encryption"
+	"github.com/UsingCoding/gostore/internal/gostore/app/output"
+	"github.com/UsingCoding/gostore/internal/gostore/app/store"
+	"github.com/UsingCoding/gostore/internal/gostore/infrastructure/consoleoutput"
+)
+
+func list() *cli.Command {
+	return &cli.Command{
+		Name:    "list",
+		Aliases: []string{"ls"},
+		Usage:   "List store recipients",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "scopes",
+				Usage: "List recipients of subtrees scopes",
+			},
+		},
+		Action: func(ctx *cli.Context) error {
+			if ctx.Bool("scopes") {
+				return listScopes(ctx)
+			}
+
+			service := clipkg.ContainerScope.MustGet(ctx.Context).StoreService
+
+			recipients, err := service.Recipients(ctx.Context)
+			if err != nil {
+				return err
+			}
+
+			o := consoleoutput.New(os.Stdout, consoleoutput.WithNewline(true))
+
+			switch output.FromCtx(ctx.Context) {
+			case output.JSON:
+				data, err2 := json.Marshal(slices.Map(recipients, encryption.Recipient.String))
+				if err2 != nil {
+					return errors.Wrap(err2, "failed to marshal recipients")
+				}
+
+				o.Printf("%s", data)
+			default:
+				for _, r := range recipients {
+					o.Printf("%s", r.String())
+				}
+			}
+
+			return nil
+		},
+	}
+}
+
+func listScopes(ctx *cli.Context) error {
+	service := clipkg.ContainerScope.MustGet(ctx.Context).StoreService
+
+	scopes, err := service.Scopes(ctx.Context)
+	if err != nil {
+		return err
+	}
+
+	o := consoleoutput.New(os.Stdout, consoleoutput.WithNewline(true))
+
+	switch output.FromCtx(ctx.Context) {
+	case output.JSON:
+		data, err2 := json.Marshal(slices.Map(scopes, func(s store.ScopeAccess) jsonScope {
+			return jsonScope{
+				Path:       s.Path,
+				Recipients: slices.Map(s.Recipients, encryption.Recipient.String),
+				Accessible: s.Accessible,
+			}
+		}))
+		if err2 != nil {
+			return errors.Wrap(err2, "failed to marshal scopes")
+		}
+
+		o.Printf("%s", data)
+	default:
+		for _, s := range scopes {
+			if s.Accessible {
+				o.Printf("%s:", s.Path)
+			} else {
+				o.Printf("%s (no access):", s.Path)
+			}
+
+			for _, r := range s.Recipients {
+				o.Printf("  %s", r)
+			}
+		}
+	}
+
+	return nil
+}
+
+type jsonScope struct {
+	Path       string   `json:"path"`
+	Recipients []string `json:"recipients"`
+	Accessible bool     `json:"accessible"`
+}
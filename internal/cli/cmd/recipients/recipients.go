@@ -0,0 +1,30 @@
+package recipients
+
+import (
+	"github.com/urfave/cli/v2"
+
+	"github.com/UsingCoding/gostore/internal/cli/cmd"
+)
+
+func Recipients() []*cli.Command {
+	return []*cli.Command{
+		{
+			Name:     "recipients",
+			Usage:    "Manage store recipients",
+			Category: cmd.ModuleCategory,
+			Subcommands: []*cli.Command{
+				add(),
+				remove(),
+				list(),
+			},
+		},
+	}
+}
+
+func pathFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:    "path",
+		Aliases: []string{"p"},
+		Usage:   "Subtree which recipients override store recipients",
+	}
+}
@@ -0,0 +1,49 @@
+package recipients
+
+import (
+	"os"
+
+	"github.com/UsingCoding/fpgo/pkg/slices"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+
+	clipkg "github.com/UsingCoding/gostore/internal/cli"
+	"github.com/UsingCoding/gostore/internal/common/maybe"
+	"github.com/UsingCoding/gostore/internal/gostore/app/encryption"
+	"github.com/UsingCoding/gostore/internal/gostore/app/store"
+	"github.com/UsingCoding/gostore/internal/gostore/infrastructure/consoleoutput"
+)
+
+func remove() *cli.Command {
+	return &cli.Command{
+		Name:      "remove",
+		Aliases:   []string{"rm"},
+		Usage:     "Remove recipients from store or subtree and re-encrypt secrets",
+		UsageText: "rm [--path <PATH>] <RECIPIENT>...",
+		Flags: []cli.Flag{
+			pathFlag(),
+		},
+		Action: func(ctx *cli.Context) error {
+			if ctx.Args().Len() < 1 {
+				return errors.New("not enough arguments")
+			}
+
+			service := clipkg.ContainerScope.MustGet(ctx.Context).StoreService
+
+			err := service.RemoveRecipients(ctx.Context, store.RemoveRecipientsParams{
+				Recipients: slices.Map(ctx.Args().Slice(), func(r string) encryption.Recipient {
+					return encryption.ParseRecipient(r)
+				}),
+				Path: maybe.MapZero(ctx.String("path")),
+			})
+			if err != nil {
+				return err
+			}
+
+			o := consoleoutput.New(os.Stdout, consoleoutput.WithNewline(true))
+			o.OKf("Recipients removed")
+
+			return nil
+		},
+	}
+}
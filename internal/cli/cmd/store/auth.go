@@ -0,0 +1,233 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+
+	clipkg "github.com/UsingCoding/gostore/internal/cli"
+	"github.com/UsingCoding/gostore/internal/cli/completion"
+	"github.com/UsingCoding/gostore/internal/common/maybe"
+	"github.com/UsingCoding/gostore/internal/gostore/app/config"
+	"github.com/UsingCoding/gostore/internal/gostore/app/storage"
+	"github.com/UsingCoding/gostore/internal/gostore/infrastructure/consoleoutput"
+)
+
+func auth() *cli.Command {
+	return &cli.Command{
+		Name:  "auth",
+		Usage: "Manage credentials to access store remote",
+		Subcommands: []*cli.Command{
+			{
+				Name:         "set",
+				Usage:        "Set credentials for store remote",
+				UsageText:    "set [auth flags] <STORE>",
+				Flags:        authFlags(),
+				BashComplete: completion.ListStoresCompletion,
+				Action:       executeAuthSet,
+			},
+			{
+				Name:         "show",
+				Usage:        "Show credentials config of store remote",
+				UsageText:    "show <STORE>",
+				BashComplete: completion.ListStoresCompletion,
+				Action:       executeAuthShow,
+			},
+			{
+				Name:         "clear",
+				Usage:        "Remove credentials of store remote",
+				UsageText:    "clear <STORE>",
+				BashComplete: completion.ListStoresCompletion,
+				Action:       executeAuthClear,
+			},
+		},
+	}
+}
+
+func executeAuthSet(ctx *cli.Context) error {
+	if ctx.Args().Len() < 1 {
+		return errors.New("not enough arguments")
+	}
+	storeID := ctx.Args().Get(0)
+
+	a, err := authFromFlags(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !maybe.Valid(a) {
+		return errors.New("auth type is required")
+	}
+
+	service := clipkg.ContainerScope.MustGet(ctx.Context).C
+	return service.SetStoreAuth(ctx.Context, config.StoreID(storeID), a)
+}
+
+func executeAuthShow(ctx *cli.Context) error {
+	if ctx.Args().Len() < 1 {
+		return errors.New("not enough arguments")
+	}
+	storeID := ctx.Args().Get(0)
+
+	service := clipkg.ContainerScope.MustGet(ctx.Context).C
+
+	s, err := service.StoreByID(ctx.Context, config.StoreID(storeID))
+	if err != nil {
+		return err
+	}
+
+	storeView, ok := maybe.JustValid(s)
+	if !ok {
+		return errors.Errorf("store with id %s not found", storeID)
+	}
+
+	o := consoleoutput.New(os.Stdout, consoleoutput.WithNewline(true))
+
+	a, ok := maybe.JustValid(storeView.Auth)
+	if !ok {
+		o.Printf("No auth configured")
+		return nil
+	}
+
+	o.Printf("type: %s", a.Type)
+	if a.User != "" {
+		o.Printf("user: %s", a.User)
+	}
+	if a.KeyPath != "" {
+		o.Printf("ssh key: %s", a.KeyPath)
+	}
+	if src, ok2 := maybe.JustValid(a.Secret); ok2 {
+		o.Printf("secret: %s", formatSecretSource(src))
+	}
+
+	return nil
+}
+
+func executeAuthClear(ctx *cli.Context) error {
+	if ctx.Args().Len() < 1 {
+		return errors.New("not enough arguments")
+	}
+	storeID := ctx.Args().Get(0)
+
+	service := clipkg.ContainerScope.MustGet(ctx.Context).C
+	return service.SetStoreAuth(ctx.Context, config.StoreID(storeID), maybe.Maybe[config.StoreAuth]{})
+}
+
+// authFlags used by commands which access store remote
+func authFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name: "auth",
+			Usage: fmt.Sprintf(
+				"Auth type to access remote: %s, %s, %s, %s",
+				storage.SSHKeyAuth,
+				storage.SSHAgentAuth,
+				storage.BasicAuth,
+				storage.TokenAuth,
+			),
+		},
+		&cli.StringFlag{
+			Name:  "auth-user",
+			Usage: "User for SSH or HTTPS basic auth",
+		},
+		&cli.StringFlag{
+			Name:      "auth-ssh-key",
+			Usage:     "Path to SSH private key",
+			TakesFile: true,
+		},
+		&cli.StringFlag{
+			Name:  "auth-secret-env",
+			Usage: "Environment variable with password, token or SSH key passphrase",
+		},
+		&cli.StringFlag{
+			Name:  "auth-secret",
+			Usage: "Secret with password, token or SSH key passphrase in form <store-id>:<path>[:<key>]",
+		},
+	}
+}
+
+func authFromFlags(ctx *cli.Context) (maybe.Maybe[config.StoreAuth], error) {
+	t := storage.AuthType(ctx.String("auth"))
+	if t == "" {
+		return maybe.Maybe[config.StoreAuth]{}, nil
+	}
+
+	a := config.StoreAuth{
+		Type:    t,
+		User:    ctx.String("auth-user"),
+		KeyPath: ctx.String("auth-ssh-key"),
+	}
+
+	env := ctx.String("auth-secret-env")
+	ref := ctx.String("auth-secret")
+	switch {
+	case env != "" && ref != "":
+		return maybe.Maybe[config.StoreAuth]{}, errors.New("only one of auth-secret-env and auth-secret allowed")
+	case env != "":
+		a.Secret = maybe.NewJust(config.SecretSource{
+			Env: maybe.NewJust(env),
+		})
+	case ref != "":
+		secretRef, err := parseSecretRef(ref)
+		if err != nil {
+			return maybe.Maybe[config.StoreAuth]{}, err
+		}
+		a.Secret = maybe.NewJust(config.SecretSource{
+			Secret: maybe.NewJust(secretRef),
+		})
+	}
+
+	switch t {
+	case storage.SSHKeyAuth:
+		if a.KeyPath == "" {
+			return maybe.Maybe[config.StoreAuth]{}, errors.Errorf("auth-ssh-key required for %s auth", t)
+		}
+	case storage.SSHAgentAuth:
+	case storage.BasicAuth:
+		if a.User == "" || !maybe.Valid(a.Secret) {
+			return maybe.Maybe[config.StoreAuth]{}, errors.Errorf("auth-user and secret required for %s auth", t)
+		}
+	case storage.TokenAuth:
+		if !maybe.Valid(a.Secret) {
+			return maybe.Maybe[config.StoreAuth]{}, errors.Errorf("secret required for %s auth", t)
+		}
+	default:
+		return maybe.Maybe[config.StoreAuth]{}, errors.Errorf("unknown auth type %s", t)
+	}
+
+	return maybe.NewJust(a), nil
+}
+
+func parseSecretRef(s string) (config.SecretRef, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		return config.SecretRef{}, errors.Errorf("invalid secret reference %s, expected <store-id>:<path>[:<key>]", s)
+	}
+
+	ref := config.SecretRef{
+		StoreID: config.StoreID(parts[0]),
+		Path:    parts[1],
+	}
+	if len(parts) == 3 {
+		ref.Key = maybe.MapZero(parts[2])
+	}
+
+	return ref, nil
+}
+
+func formatSecretSource(src config.SecretSource) string {
+	if env, ok := maybe.JustValid(src.Env); ok {
+		return fmt.Sprintf("env %s", env)
+	}
+
+	ref := maybe.Just(src.Secret)
+	s := fmt.Sprintf("%s:%s", ref.StoreID, ref.Path)
+	if k, ok := maybe.JustValid(ref.Key); ok {
+		s += ":" + k
+	}
+
+	return s
+}
@@ -17,7 +17,7 @@ func clone() *cli.Command {
 		Usage:     "Clone store locally",
 		UsageText: "clone <ADDRESS>",
 		Action:    executeClone,
-		Flags: []cli.Flag{
+		Flags: append([]cli.Flag{
 			&cli.StringFlag{
 				Name:     "id",
 				Usage:    "store id",
@@ -32,7 +32,7 @@ func clone() *cli.Command {
 				Usage: "Storage type to detect clone strategy",
 				Value: string(storage.GITType),
 			},
-		},
+		}, authFlags()...),
 	}
 }
 
@@ -46,6 +46,11 @@ func executeClone(ctx *cli.Context) error {
 	storePath := maybe.MapZero(ctx.String("store-path"))
 	storageType := ctx.String("storage-type")
 
+	a, err := authFromFlags(ctx)
+	if err != nil {
+		return err
+	}
+
 	service := clipkg.ContainerScope.MustGet(ctx.Context).StoreCRUD
 
 	return service.Clone(
@@ -55,6 +60,7 @@ func executeClone(ctx *cli.Context) error {
 			StorePath:   storePath,
 			StorageType: storage.Type(storageType),
 			Remote:      address,
+			Auth:        a,
 		},
 	)
 }
@@ -1,6 +1,7 @@
 package store
 
 import (
+	"fmt"
 	"os"
 
 	"github.com/UsingCoding/fpgo/pkg/slices"
@@ -9,6 +10,7 @@ import (
 	clipkg "github.com/UsingCoding/gostore/internal/cli"
 	"github.com/UsingCoding/gostore/internal/common/maybe"
 	"github.com/UsingCoding/gostore/internal/gostore/app/encryption"
+	"github.com/UsingCoding/gostore/internal/gostore/app/storage"
 	"github.com/UsingCoding/gostore/internal/gostore/app/storecrud"
 	"github.com/UsingCoding/gostore/internal/gostore/infrastructure/consoleoutput"
 )
@@ -18,7 +20,7 @@ func initCmd() *cli.Command {
 		Name:   "init",
 		Usage:  "Initialize store",
 		Action: executeInit,
-		Flags: []cli.Flag{
+		Flags: append([]cli.Flag{
 			&cli.StringFlag{
 				Name:     "id",
 				Usage:    "Local store id",
@@ -35,9 +37,19 @@ func initCmd() *cli.Command {
 			},
 			&cli.StringFlag{
 				Name:  "remote",
-				Usage: "Remote address for store",
+				Usage: "Remote address for store. For s3 storage: s3://<endpoint>/<bucket>[/<prefix>]",
 			},
-		},
+			&cli.StringFlag{
+				Name:  "storage-type",
+				Usage: fmt.Sprintf("Storage type: %s, %s or %s", storage.GITType, storage.FSType, storage.S3Type),
+				Value: string(storage.GITType),
+			},
+			&cli.StringFlag{
+				Name:  "encryption",
+				Usage: fmt.Sprintf("Encryption: %s or %s", encryption.AgeEncryption, encryption.PGPEncryption),
+				Value: string(encryption.AgeEncryption),
+			},
+		}, authFlags()...),
 	}
 }
 
@@ -47,9 +59,14 @@ func executeInit(ctx *cli.Context) error {
 	storePath := maybe.MapZero(ctx.String("store-path"))
 	remote := maybe.MapZero(ctx.String("remote"))
 	recipients := slices.Map(ctx.StringSlice("recipients"), func(r string) encryption.Recipient {
-		return encryption.Recipient(r)
+		return encryption.ParseRecipient(r)
 	})
 
+	a, err := authFromFlags(ctx)
+	if err != nil {
+		return err
+	}
+
 	service := clipkg.ContainerScope.MustGet(ctx.Context).StoreCRUD
 
 	res, err := service.Init(ctx.Context, storecrud.InitParams{
@@ -57,6 +74,10 @@ func executeInit(ctx *cli.Context) error {
 		StorePath:  storePath,
 		Recipients: recipients,
 		Remote:     remote,
+		Auth:       a,
+
+		StorageType: maybe.NewJust(storage.Type(ctx.String("storage-type"))),
+		Encryption:  maybe.NewJust(encryption.Encryption(ctx.String("encryption"))),
 	})
 	if err != nil {
 		return err
@@ -0,0 +1,82 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/UsingCoding/fpgo/pkg/slices"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+
+	clipkg "github.com/UsingCoding/gostore/internal/cli"
+	"github.com/UsingCoding/gostore/internal/gostore/app/encryption"
+	"github.com/UsingCoding/gostore/internal/gostore/app/store"
+	"github.com/UsingCoding/gostore/internal/gostore/infrastructure/consoleoutput"
+)
+
+func migrateEncryption() *cli.Command {
+	return &cli.Command{
+		Name:      "migrate-encryption",
+		Usage:     "Re-encrypt all secrets of current store with other encryption",
+		UsageText: "migrate-encryption --to <ENCRYPTION> -r <RECIPIENT>... [-s <SCOPE_PATH>=<RECIPIENT>...]",
+		Action:    executeMigrateEncryption,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "to",
+				Usage:    fmt.Sprintf("Encryption to migrate to: %s or %s", encryption.AgeEncryption, encryption.PGPEncryption),
+				Required: true,
+			},
+			&cli.StringSliceFlag{
+				Name:     "recipients",
+				Usage:    "Recipients of store for new encryption",
+				Aliases:  []string{"r"},
+				Required: true,
+			},
+			&cli.StringSliceFlag{
+				Name:    "scope-recipients",
+				Usage:   "Recipients of scope for new encryption as <SCOPE_PATH>=<RECIPIENT>, required for every recipients scope of store",
+				Aliases: []string{"s"},
+			},
+		},
+	}
+}
+
+func executeMigrateEncryption(ctx *cli.Context) error {
+	service := clipkg.ContainerScope.MustGet(ctx.Context).StoreService
+
+	enc := encryption.Encryption(ctx.String("to"))
+
+	scopes, err := slices.MapErr(ctx.StringSlice("scope-recipients"), parseScopeRecipient)
+	if err != nil {
+		return err
+	}
+
+	err = service.MigrateEncryption(ctx.Context, store.MigrateEncryptionParams{
+		Encryption: enc,
+		Recipients: slices.Map(ctx.StringSlice("recipients"), func(r string) encryption.Recipient {
+			return encryption.ParseRecipient(r)
+		}),
+		Scopes: scopes,
+	})
+	if err != nil {
+		return err
+	}
+
+	o := consoleoutput.New(os.Stdout, consoleoutput.WithNewline(true))
+	o.OKf("Store migrated to %s", enc)
+
+	return nil
+}
+
+func parseScopeRecipient(s string) (store.RecipientsScope, error) {
+	p, r, ok := strings.Cut(s, "=")
+	if !ok || p == "" || r == "" {
+		return store.RecipientsScope{}, errors.Errorf("invalid scope recipient %q, expected <SCOPE_PATH>=<RECIPIENT>", s)
+	}
+
+	return store.RecipientsScope{
+		Path:       p,
+		Recipients: []encryption.Recipient{encryption.ParseRecipient(r)},
+	}, nil
+}
@@ -0,0 +1,121 @@
+package store
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+
+	"github.com/UsingCoding/fpgo/pkg/slices"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+
+	clipkg "github.com/UsingCoding/gostore/internal/cli"
+	"github.com/UsingCoding/gostore/internal/common/maybe"
+	"github.com/UsingCoding/gostore/internal/gostore/app/output"
+	"github.com/UsingCoding/gostore/internal/gostore/app/store"
+	"github.com/UsingCoding/gostore/internal/gostore/infrastructure/consoleoutput"
+)
+
+func policy() *cli.Command {
+	return &cli.Command{
+		Name:      "policy",
+		Usage:     "Show or update password policy of current store",
+		UsageText: "policy [--length N] [--words N] [--require CLASS]... [--exclude-ambiguous]",
+		Action:    executePolicy,
+		Flags: []cli.Flag{
+			&cli.IntFlag{
+				Name:  "length",
+				Usage: "Length of generated passwords, 0 resets to default",
+			},
+			&cli.IntFlag{
+				Name:  "words",
+				Usage: "Words count in generated passphrases, 0 resets to default",
+			},
+			&cli.StringSliceFlag{
+				Name: "require",
+				Usage: "Character classes each password contains: " +
+					strings.Join(slices.Map(store.CharClasses, func(c store.CharClass) string {
+						return string(c)
+					}), ", "),
+			},
+			&cli.BoolFlag{
+				Name:  "exclude-ambiguous",
+				Usage: "Exclude characters looking alike such as 0/O and 1/l/I",
+			},
+		},
+	}
+}
+
+func executePolicy(ctx *cli.Context) error {
+	service := clipkg.ContainerScope.MustGet(ctx.Context).StoreService
+
+	p, err := service.PasswordPolicy(ctx.Context)
+	if err != nil {
+		return err
+	}
+
+	changed := false
+	if ctx.IsSet("length") {
+		p.Length = maybe.MapZero(ctx.Int("length"))
+		changed = true
+	}
+	if ctx.IsSet("words") {
+		p.Words = maybe.MapZero(ctx.Int("words"))
+		changed = true
+	}
+	if ctx.IsSet("require") {
+		p.Required = slices.Map(ctx.StringSlice("require"), func(c string) store.CharClass {
+			return store.CharClass(c)
+		})
+		changed = true
+	}
+	if ctx.IsSet("exclude-ambiguous") {
+		p.ExcludeAmbiguous = ctx.Bool("exclude-ambiguous")
+		changed = true
+	}
+
+	if changed {
+		return service.SetPasswordPolicy(ctx.Context, p)
+	}
+
+	o := consoleoutput.New(os.Stdout, consoleoutput.WithNewline(true))
+
+	if output.FromCtx(ctx.Context) == output.JSON {
+		data, err2 := json.Marshal(jsonPolicy{
+			Length: maybe.ToPtr(p.Length),
+			Words:  maybe.ToPtr(p.Words),
+			Required: slices.Map(p.Required, func(c store.CharClass) string {
+				return string(c)
+			}),
+			ExcludeAmbiguous: p.ExcludeAmbiguous,
+		})
+		if err2 != nil {
+			return errors.Wrap(err2, "failed to marshal policy")
+		}
+
+		o.Printf("%s", data)
+		return nil
+	}
+
+	if l, ok := maybe.JustValid(p.Length); ok {
+		o.Printf("Length: %d", l)
+	}
+	if w, ok := maybe.JustValid(p.Words); ok {
+		o.Printf("Words: %d", w)
+	}
+	if len(p.Required) > 0 {
+		o.Printf("Required: %s", strings.Join(slices.Map(p.Required, func(c store.CharClass) string {
+			return string(c)
+		}), ", "))
+	}
+	o.Printf("Exclude ambiguous: %t", p.ExcludeAmbiguous)
+
+	return nil
+}
+
+type jsonPolicy struct {
+	Length           *int     `json:"length,omitempty"`
+	Words            *int     `json:"words,omitempty"`
+	Required         []string `json:"required,omitempty"`
+	ExcludeAmbiguous bool     `json:"excludeAmbiguous"`
+}
@@ -19,6 +19,9 @@ func Store() []*cli.Command {
 				initCmd(),
 				clone(),
 				remove(),
+				auth(),
+				policy(),
+				migrateEncryption(),
 			},
 		},
 	}
@@ -1,40 +1,38 @@
 package totp
 
 import (
+	"fmt"
+
 	"github.com/manifoldco/promptui"
 	"github.com/urfave/cli/v2"
 
 	clipkg "github.com/UsingCoding/gostore/internal/cli"
+	"github.com/UsingCoding/gostore/internal/common/maybe"
 	"github.com/UsingCoding/gostore/internal/gostore/app/usecase/totp"
 )
 
 func add() *cli.Command {
 	return &cli.Command{
-		Name:  "add",
-		Usage: "add a totp issuer",
+		Name:      "add",
+		Usage:     "add a totp issuer",
+		UsageText: "add [--uri <otpauth://totp/...>] [--type hotp [--counter N]] [NAME]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "uri",
+				Usage: "otpauth uri to take secret and parameters from. Name defaults to uri issuer or account",
+			},
+			&cli.StringFlag{
+				Name:  "type",
+				Usage: fmt.Sprintf("Passcode type: %s or %s", totp.TypeTOTP, totp.TypeHOTP),
+				Value: string(totp.TypeTOTP),
+			},
+			&cli.Uint64Flag{
+				Name:  "counter",
+				Usage: "Initial counter for hotp",
+			},
+		},
 		Action: func(c *cli.Context) error {
-			prompt := promptui.Prompt{
-				Label: "Name",
-			}
-			name, err := prompt.Run()
-			if err != nil {
-				return err
-			}
-
-			prompt = promptui.Prompt{
-				Label: "Secret",
-				Mask:  '*',
-			}
-			secret, err := prompt.Run()
-			if err != nil {
-				return err
-			}
-
-			prompt = promptui.Prompt{
-				Label:   "ALG",
-				Default: string(totp.AlgorithmSHA1),
-			}
-			alg, err := prompt.Run()
+			params, err := addParams(c)
 			if err != nil {
 				return err
 			}
@@ -42,12 +40,54 @@ func add() *cli.Command {
 			return clipkg.ContainerScope.MustGet(c.Context).TOTP.
 				AddIssuer(
 					c.Context,
-					totp.AddParams{
-						Name:      name,
-						Secret:    []byte(secret),
-						Algorithm: totp.Algorithm(alg),
-					},
+					params,
 				)
 		},
 	}
 }
+
+func addParams(c *cli.Context) (totp.AddParams, error) {
+	if uri := c.String("uri"); uri != "" {
+		return totp.ParseURI(c.Args().First(), uri)
+	}
+
+	prompt := promptui.Prompt{
+		Label:   "Name",
+		Default: c.Args().First(),
+	}
+	name, err := prompt.Run()
+	if err != nil {
+		return totp.AddParams{}, err
+	}
+
+	prompt = promptui.Prompt{
+		Label: "Secret",
+		Mask:  '*',
+	}
+	secret, err := prompt.Run()
+	if err != nil {
+		return totp.AddParams{}, err
+	}
+
+	prompt = promptui.Prompt{
+		Label:   "ALG",
+		Default: string(totp.AlgorithmSHA1),
+	}
+	alg, err := prompt.Run()
+	if err != nil {
+		return totp.AddParams{}, err
+	}
+
+	params := totp.AddParams{
+		Name:      name,
+		Secret:    []byte(secret),
+		Algorithm: totp.Algorithm(alg),
+		Type:      maybe.NewJust(totp.Type(c.String("type"))),
+	}
+
+	if c.IsSet("counter") {
+		params.Counter = maybe.NewJust(c.Uint64("counter"))
+	}
+
+	return params, nil
+}
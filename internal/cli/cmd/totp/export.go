@@ -0,0 +1,47 @@
+package totp
+
+import (
+	"os"
+
+	"github.com/mdp/qrterminal/v3"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+
+	clipkg "github.com/UsingCoding/gostore/internal/cli"
+	clicompletion "github.com/UsingCoding/gostore/internal/cli/completion"
+	"github.com/UsingCoding/gostore/internal/gostore/infrastructure/consoleoutput"
+)
+
+func export() *cli.Command {
+	return &cli.Command{
+		Name:         "export",
+		Usage:        "Print otpauth uri of totp issuer",
+		UsageText:    "export [--qr] <NAME>",
+		BashComplete: clicompletion.ListCompletion("totp"),
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "qr",
+				Usage: "Print uri as QR code to scan by authenticator app",
+			},
+		},
+		Action: func(ctx *cli.Context) error {
+			if ctx.Args().Len() < 1 {
+				return errors.New("not enough arguments")
+			}
+
+			uri, err := clipkg.ContainerScope.MustGet(ctx.Context).TOTP.
+				ExportURI(ctx.Context, ctx.Args().First())
+			if err != nil {
+				return err
+			}
+
+			if ctx.Bool("qr") {
+				qrterminal.Generate(uri, qrterminal.L, os.Stdout)
+				return nil
+			}
+
+			consoleoutput.New(os.Stdout, consoleoutput.WithNewline(true)).Printf("%s", uri)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,65 @@
+package totp
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+
+	clipkg "github.com/UsingCoding/gostore/internal/cli"
+	"github.com/UsingCoding/gostore/internal/gostore/app/usecase/totp"
+	"github.com/UsingCoding/gostore/internal/gostore/infrastructure/consoleoutput"
+	"github.com/UsingCoding/gostore/internal/gostore/infrastructure/qrcode"
+)
+
+func importQR() *cli.Command {
+	return &cli.Command{
+		Name:      "import-qr",
+		Usage:     "Import issuers from PNG or JPEG image with otpauth or Google Authenticator export QR code",
+		UsageText: "import-qr [--overwrite] <IMAGE>",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "overwrite",
+				Usage: "Replace existing issuers with same names",
+			},
+		},
+		Action: func(ctx *cli.Context) error {
+			if ctx.Args().Len() < 1 {
+				return errors.New("not enough arguments")
+			}
+
+			f, err := os.Open(ctx.Args().First())
+			if err != nil {
+				return errors.Wrap(err, "failed to open image")
+			}
+			defer f.Close()
+
+			payload, err := qrcode.Decode(f)
+			if err != nil {
+				return err
+			}
+
+			issuers, err := totp.ParseQRPayload(payload)
+			if err != nil {
+				return err
+			}
+
+			service := clipkg.ContainerScope.MustGet(ctx.Context).TOTP
+			o := consoleoutput.New(os.Stdout, consoleoutput.WithNewline(true))
+
+			err = service.ImportIssuers(ctx.Context, totp.ImportParams{
+				Issuers:   issuers,
+				Overwrite: ctx.Bool("overwrite"),
+			})
+			if err != nil {
+				return err
+			}
+
+			for _, params := range issuers {
+				o.Printf("Imported %s", params.Name)
+			}
+
+			return nil
+		},
+	}
+}
@@ -11,6 +11,7 @@ import (
 
 	clipkg "github.com/UsingCoding/gostore/internal/cli"
 	clicompletion "github.com/UsingCoding/gostore/internal/cli/completion"
+	"github.com/UsingCoding/gostore/internal/common/maybe"
 	apptotp "github.com/UsingCoding/gostore/internal/gostore/app/usecase/totp"
 	"github.com/UsingCoding/gostore/internal/gostore/infrastructure/consoleoutput"
 )
@@ -37,6 +38,18 @@ func passcode() *cli.Command {
 			}
 
 			o := consoleoutput.New(os.Stdout, consoleoutput.WithNewline(true))
+
+			if counter, ok := maybe.JustValid(pv.Counter); ok {
+				code, err2 := pv.GeneratePasscode()
+				if err2 != nil {
+					return err2
+				}
+
+				o.Printf("HMAC-based One Time Password")
+				o.Printf("Code: %s Counter: %d", code, counter)
+				return nil
+			}
+
 			o.Printf("Time-based One Time Password")
 
 			switch term.IsTerminal(int(os.Stdout.Fd())) {
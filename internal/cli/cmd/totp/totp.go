@@ -15,6 +15,8 @@ func TOTP() []*cli.Command {
 			Subcommands: []*cli.Command{
 				add(),
 				passcode(),
+				export(),
+				importQR(),
 			},
 		},
 	}
@@ -1,17 +1,29 @@
 package cli
 
 import (
+	"os"
+	"path"
+
 	"github.com/urfave/cli/v2"
 
 	"github.com/UsingCoding/gostore/internal/common/maybe"
 	"github.com/UsingCoding/gostore/internal/common/scope"
+	"github.com/UsingCoding/gostore/internal/gostore/app/agent"
+	appencryption "github.com/UsingCoding/gostore/internal/gostore/app/encryption"
+	"github.com/UsingCoding/gostore/internal/gostore/app/remoteauth"
 	"github.com/UsingCoding/gostore/internal/gostore/app/store"
 	"github.com/UsingCoding/gostore/internal/gostore/app/storecrud"
+	"github.com/UsingCoding/gostore/internal/gostore/app/usecase/env"
+	"github.com/UsingCoding/gostore/internal/gostore/app/usecase/generate"
+	"github.com/UsingCoding/gostore/internal/gostore/app/usecase/render"
 	"github.com/UsingCoding/gostore/internal/gostore/app/usecase/totp"
 
 	"github.com/UsingCoding/gostore/internal/gostore/app/config"
+	infraagent "github.com/UsingCoding/gostore/internal/gostore/infrastructure/agent"
 	infraconfig "github.com/UsingCoding/gostore/internal/gostore/infrastructure/config"
+	"github.com/UsingCoding/gostore/internal/gostore/infrastructure/diceware"
 	"github.com/UsingCoding/gostore/internal/gostore/infrastructure/encryption"
+	"github.com/UsingCoding/gostore/internal/gostore/infrastructure/passphrase"
 	"github.com/UsingCoding/gostore/internal/gostore/infrastructure/storage"
 	infrastore "github.com/UsingCoding/gostore/internal/gostore/infrastructure/store"
 )
@@ -30,6 +42,9 @@ var (
 func NewContainer(ctx *cli.Context) Container {
 	gostoreBaseDir := ctx.String("gostore-base-path")
 	storeID := maybe.MapZero(ctx.String("store-id"))
+	agentSocket := maybe.MapNone(maybe.MapZero(ctx.String("agent-socket")), func() string {
+		return path.Join(gostoreBaseDir, "agent.sock")
+	})
 
 	storageManager := storage.NewManager()
 	encryptionManager := encryption.NewManager()
@@ -39,32 +54,59 @@ func NewContainer(ctx *cli.Context) Container {
 		gostoreBaseDir,
 		storageManager,
 		encryptionManager,
+		passphrase.NewProvider(maybe.MapZero(os.Getenv(passphrase.EnvPassphrase))),
 	)
 
+	agentClient := infraagent.NewClient(agentSocket)
+
 	manifestSerializer := infrastore.NewManifestSerializer()
 
-	storeService := store.NewStoreService(
-		storeID,
-		storageManager,
-		encryptionManager,
-		manifestSerializer,
-		infrastore.NewSecretSerializer(),
-		c,
-		c,
-	)
+	secretSerializer := infrastore.NewSecretSerializer()
+	identityProvider := agent.NewIdentityProvider(agentClient, c)
+	authorProvider := infrastore.NewAuthorProvider()
+
+	var authProvider remoteauth.Provider
+	newStoreService := func(storeID maybe.Maybe[string]) store.Service {
+		return store.NewStoreService(
+			storeID,
+			storageManager,
+			agent.NewEncryptionManager(agentClient, encryptionManager),
+			manifestSerializer,
+			secretSerializer,
+			c,
+			identityProvider,
+			authProvider,
+			authorProvider,
+		)
+	}
+	authProvider = remoteauth.NewProvider(c, func(storeID config.StoreID) store.Service {
+		return newStoreService(maybe.NewJust(string(storeID)))
+	})
+
+	storeService := newStoreService(storeID)
 
 	storeCRUD := storecrud.NewService(
 		c,
 		encryptionManager,
 		storageManager,
 		manifestSerializer,
+		authProvider,
 	)
 
+	totpService := totp.NewService(storeService)
+
 	return Container{
 		C:            c,
 		StoreService: storeService,
-		TOTP:         totp.NewService(storeService),
+		TOTP:         totpService,
+		Generate:     generate.NewService(storeService, diceware.NewWordGenerator()),
+		Env:          env.NewService(storeService),
+		Render:       render.NewService(storeService, totpService),
 		StoreCRUD:    storeCRUD,
+		Agent:        agentClient,
+		AgentSocket:  agentSocket,
+
+		EncryptionManager: encryptionManager,
 	}
 }
 
@@ -74,4 +116,12 @@ type Container struct {
 
 	StoreCRUD storecrud.Service
 	TOTP      totp.Service
+	Generate  generate.Service
+	Env       env.Service
+	Render    render.Service
+
+	Agent       agent.Client
+	AgentSocket string
+
+	EncryptionManager appencryption.Manager
 }
@@ -7,6 +7,7 @@ import (
 	"image"
 	"path/filepath"
 	"strings"
+	"time"
 	"unicode"
 	"unicode/utf8"
 
@@ -19,6 +20,7 @@ import (
 	"github.com/UsingCoding/gostore/internal/gostore/app/storage"
 	"github.com/UsingCoding/gostore/internal/gostore/app/store"
 	"github.com/UsingCoding/gostore/internal/gostore/app/usecase/edit"
+	"github.com/UsingCoding/gostore/internal/gostore/app/usecase/generate"
 )
 
 type focusArea int
@@ -48,6 +50,7 @@ type dashboard struct {
 	configService config.Service
 	storeService  store.Service
 	editService   edit.Service
+	generate      generate.Service
 
 	grid         *ui.Grid
 	sidebar      *widgets.Flex
@@ -103,13 +106,20 @@ type textPrompt struct {
 	onCancel func()
 }
 
-func newDashboard(ctx context.Context, configService config.Service, storeService store.Service, editService edit.Service) *dashboard {
+func newDashboard(
+	ctx context.Context,
+	configService config.Service,
+	storeService store.Service,
+	editService edit.Service,
+	generateService generate.Service,
+) *dashboard {
 	d := &dashboard{
 		Block:            *ui.NewBlock(),
 		ctx:              ctx,
 		configService:    configService,
 		storeService:     storeService,
 		editService:      editService,
+		generate:         generateService,
 		normalBorder:     ui.Theme.Block.Border,
 		focusBorder:      ui.NewStyle(ui.ColorGreen),
 		focusedSelection: ui.NewStyle(ui.ColorBlack, ui.ColorGreen),
@@ -388,6 +398,9 @@ func (d *dashboard) handleSecretPaneEvent(e ui.Event) bool {
 	case "a":
 		d.promptAddField()
 		return true
+	case "g":
+		d.confirmGenerateField()
+		return true
 	}
 
 	return false
@@ -745,6 +758,33 @@ func (d *dashboard) loadSecretFields(path string) {
 	}
 
 	d.secretPane.SetFields(fields, placeholder)
+
+	m, err := d.storeService.Metadata(d.ctx, store.MetadataParams{Path: path})
+	if err != nil {
+		d.setStatus(fmt.Sprintf("Failed to load secret metadata: %v", err))
+		return
+	}
+	if metadata, ok := maybe.JustValid(m); ok {
+		d.secretPane.SetMetadata(buildMetadataLines(metadata))
+	}
+}
+
+func buildMetadataLines(m store.Metadata) []string {
+	var lines []string
+	if t, ok := maybe.JustValid(m.Updated); ok {
+		line := "Updated " + t.Local().Format(time.DateTime)
+		if a, ok2 := maybe.JustValid(m.Author); ok2 {
+			line += " by " + a
+		}
+		lines = append(lines, line)
+	}
+	if len(m.Tags) > 0 {
+		lines = append(lines, "Tags: "+strings.Join(m.Tags, ", "))
+	}
+	if d, ok := maybe.JustValid(m.Description); ok {
+		lines = append(lines, d)
+	}
+	return lines
 }
 
 func buildSecretFields(data []store.SecretData) []secretField {
@@ -886,6 +926,38 @@ func (d *dashboard) removeSecret(path string) {
 	d.refreshSecrets(false)
 }
 
+func (d *dashboard) confirmGenerateField() {
+	path, ok := d.ensureSecretSelected()
+	if !ok {
+		return
+	}
+	field, ok := d.secretPane.selectedField()
+	if !ok {
+		d.setStatus("Select a field")
+		return
+	}
+
+	d.openConfirm(fmt.Sprintf("Replace %s with generated password?", field.name), func() {
+		d.generateField(path, field.name)
+	})
+}
+
+func (d *dashboard) generateField(path, key string) {
+	_, err := d.generate.Generate(d.ctx, generate.GenerateParams{
+		SecretIndex: store.SecretIndex{
+			Path: path,
+			Key:  maybe.NewJust(key),
+		},
+	})
+	if err != nil {
+		d.setStatus(fmt.Sprintf("Generate failed: %v", err))
+		return
+	}
+
+	d.setStatus("Password generated")
+	d.loadSecretFields(path)
+}
+
 func (d *dashboard) confirmRemoveField() {
 	path, ok := d.ensureSecretSelected()
 	if !ok {
@@ -1130,7 +1202,7 @@ func (d *dashboard) helpText() string {
 	case focusStoresSearch:
 		return "Enter: apply | Esc: cancel | type to filter"
 	case focusSecretPane:
-		return "j/k: move | Space: copy | v: view | e: edit | d: delete | a: add | Tab: sidebar"
+		return "j/k: move | Space: copy | v: view | e: edit | g: generate | d: delete | a: add | Tab: sidebar"
 	default:
 		return "q: quit"
 	}
@@ -19,6 +19,7 @@ type SecretPane struct {
 	ui.Block
 
 	fields      []secretField
+	metadata    []string
 	selected    int
 	scroll      int
 	placeholder string
@@ -62,6 +63,7 @@ func (p *SecretPane) SetFocused(focused bool) {
 
 func (p *SecretPane) SetFields(fields []secretField, placeholder string) {
 	p.fields = fields
+	p.metadata = nil
 	p.placeholder = placeholder
 	if len(fields) == 0 {
 		p.selected = -1
@@ -72,6 +74,11 @@ func (p *SecretPane) SetFields(fields []secretField, placeholder string) {
 	p.needsScroll = true
 }
 
+// SetMetadata sets lines describing secret drawn above fields, reset by SetFields
+func (p *SecretPane) SetMetadata(lines []string) {
+	p.metadata = lines
+}
+
 func (p *SecretPane) selectedField() (secretField, bool) {
 	if p.selected < 0 || p.selected >= len(p.fields) {
 		return secretField{}, false
@@ -107,12 +114,13 @@ func (p *SecretPane) ToggleVisible() {
 func (p *SecretPane) Draw(buf *ui.Buffer) {
 	p.Block.Draw(buf)
 
+	content := p.drawMetadata(buf)
+
 	if len(p.fields) == 0 {
-		p.drawPlaceholder(buf)
+		p.drawPlaceholder(buf, content)
 		return
 	}
 
-	content := p.Inner
 	if content.Dx() <= 0 || content.Dy() <= 0 {
 		return
 	}
@@ -155,16 +163,40 @@ func (p *SecretPane) Draw(buf *ui.Buffer) {
 	}
 
 	if len(p.fields) > visibleCount && content.Dx() < p.Inner.Dx() {
-		p.drawScrollbar(buf, visibleCount)
+		p.drawScrollbar(buf, content, visibleCount)
+	}
+}
+
+// drawMetadata draws metadata lines and returns area left for fields
+func (p *SecretPane) drawMetadata(buf *ui.Buffer) image.Rectangle {
+	content := p.Inner
+	if len(p.metadata) == 0 {
+		return content
 	}
+
+	style := ui.NewStyle(ui.ColorGrey)
+	for _, line := range p.metadata {
+		if content.Dy() <= 0 {
+			break
+		}
+		buf.SetString(line, style, content.Min)
+		content.Min.Y++
+	}
+
+	// keep gap between metadata and fields
+	if content.Dy() > 0 {
+		content.Min.Y++
+	}
+
+	return content
 }
 
-func (p *SecretPane) drawPlaceholder(buf *ui.Buffer) {
-	if p.placeholder == "" {
+func (p *SecretPane) drawPlaceholder(buf *ui.Buffer, content image.Rectangle) {
+	if p.placeholder == "" || content.Dy() <= 0 {
 		return
 	}
 	style := ui.NewStyle(ui.ColorGrey)
-	buf.SetString(p.placeholder, style, image.Pt(p.Inner.Min.X, p.Inner.Min.Y))
+	buf.SetString(p.placeholder, style, content.Min)
 }
 
 const fieldGap = 1
@@ -289,11 +321,11 @@ func (p *SecretPane) selectionStyle(focused bool) ui.Style {
 	return p.blurredSelection
 }
 
-func (p *SecretPane) drawScrollbar(buf *ui.Buffer, visibleCount int) {
+func (p *SecretPane) drawScrollbar(buf *ui.Buffer, content image.Rectangle, visibleCount int) {
 	p.scrollbar.Max = len(p.fields)
 	p.scrollbar.Current = p.scroll
 	p.scrollbar.PageSize = visibleCount
-	p.scrollbar.SetRect(p.Inner.Max.X-1, p.Inner.Min.Y, p.Inner.Max.X, p.Inner.Max.Y)
+	p.scrollbar.SetRect(p.Inner.Max.X-1, content.Min.Y, p.Inner.Max.X, content.Max.Y)
 	p.scrollbar.Draw(buf)
 }
 
@@ -24,7 +24,7 @@ func TUI(ctx context.Context) error {
 		editService = edit.NewService(container.StoreService, editor)
 	}
 
-	dashboard := newDashboard(ctx, container.C, container.StoreService, editService)
+	dashboard := newDashboard(ctx, container.C, container.StoreService, editService, container.Generate)
 	app := ui.NewApp()
 	app.SetRoot(dashboard, true)
 
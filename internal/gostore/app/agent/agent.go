@@ -0,0 +1,27 @@
+package agent
+
+import (
+	"context"
+	stderrors "errors"
+
+	"github.com/UsingCoding/gostore/internal/gostore/app/encryption"
+)
+
+var (
+	ErrAgentNotRunning = stderrors.New("agent not running")
+)
+
+// Client communicates with running agent.
+// Private keys passed to agent never leave it, agent decrypts data by request instead
+type Client interface {
+	// HasIdentity reports whether agent holds unlocked identity for recipient
+	HasIdentity(ctx context.Context, recipient encryption.Recipient) (bool, error)
+	// AddIdentity passes unlocked identity to agent to hold it until TTL expires
+	AddIdentity(ctx context.Context, identity encryption.Identity) error
+	// Decrypt data with identities held by agent for recipients
+	Decrypt(ctx context.Context, enc encryption.Encryption, data []byte, recipients []encryption.Recipient) ([]byte, error)
+	// Clear removes all identities from agent
+	Clear(ctx context.Context) error
+	// Stop agent
+	Stop(ctx context.Context) error
+}
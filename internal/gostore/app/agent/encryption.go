@@ -0,0 +1,83 @@
+package agent
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/UsingCoding/gostore/internal/gostore/app/encryption"
+)
+
+const (
+	// heldIdentityProvider marks reference to identity held by agent, such identity has no private key
+	heldIdentityProvider = encryption.Provider("agent")
+)
+
+func heldIdentity(recipient encryption.Recipient) encryption.Identity {
+	return encryption.Identity{
+		Provider:  heldIdentityProvider,
+		Recipient: recipient,
+	}
+}
+
+// NewEncryptionManager returns manager which services decrypt data with identities held by agent
+func NewEncryptionManager(client Client, manager encryption.Manager) encryption.Manager {
+	return &encryptionManager{
+		Manager: manager,
+		client:  client,
+	}
+}
+
+type encryptionManager struct {
+	encryption.Manager
+	client Client
+}
+
+func (m *encryptionManager) EncryptService(enc encryption.Encryption) (encryption.Service, error) {
+	s, err := m.Manager.EncryptService(enc)
+	if err != nil {
+		return nil, err
+	}
+
+	return &encryptService{
+		Service:    s,
+		encryption: enc,
+		client:     m.client,
+	}, nil
+}
+
+type encryptService struct {
+	encryption.Service
+	encryption encryption.Encryption
+	client     Client
+}
+
+// Decrypt tries local identities first and asks agent to decrypt with identities it holds
+func (s *encryptService) Decrypt(data []byte, identities []encryption.Identity) ([]byte, error) {
+	var (
+		local []encryption.Identity
+		held  []encryption.Recipient
+	)
+	for _, i := range identities {
+		if i.Provider == heldIdentityProvider {
+			held = append(held, i.Recipient)
+			continue
+		}
+		local = append(local, i)
+	}
+
+	if len(held) == 0 {
+		return s.Service.Decrypt(data, local)
+	}
+
+	if len(local) != 0 {
+		decrypted, err := s.Service.Decrypt(data, local)
+		if err == nil {
+			return decrypted, nil
+		}
+	}
+
+	// service has no ctx, requests to agent bounded by client timeouts
+	decrypted, err := s.client.Decrypt(context.Background(), s.encryption, data, held)
+	return decrypted, errors.Wrap(err, "failed to decrypt with agent")
+}
@@ -0,0 +1,97 @@
+package agent
+
+import (
+	"sync"
+	"time"
+
+	"github.com/UsingCoding/gostore/internal/gostore/app/encryption"
+)
+
+// Keyring holds unlocked identities in memory for ttl since they were added
+type Keyring struct {
+	ttl time.Duration
+	now func() time.Time
+
+	m       sync.Mutex
+	entries map[string]keyringEntry
+}
+
+type keyringEntry struct {
+	identity  encryption.Identity
+	expiresAt time.Time
+}
+
+func NewKeyring(ttl time.Duration) *Keyring {
+	return &Keyring{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: map[string]keyringEntry{},
+	}
+}
+
+func (k *Keyring) Add(identity encryption.Identity) {
+	k.m.Lock()
+	defer k.m.Unlock()
+
+	k.entries[identity.Recipient.String()] = keyringEntry{
+		identity:  identity,
+		expiresAt: k.now().Add(k.ttl),
+	}
+}
+
+// Has reports whether keyring holds not expired identity for recipient
+func (k *Keyring) Has(recipient encryption.Recipient) bool {
+	k.m.Lock()
+	defer k.m.Unlock()
+
+	_, ok := k.get(recipient)
+	return ok
+}
+
+// Identities returns not expired identities for recipients
+func (k *Keyring) Identities(recipients []encryption.Recipient) []encryption.Identity {
+	k.m.Lock()
+	defer k.m.Unlock()
+
+	var res []encryption.Identity
+	for _, r := range recipients {
+		if i, ok := k.get(r); ok {
+			res = append(res, i)
+		}
+	}
+	return res
+}
+
+// Purge removes expired identities
+func (k *Keyring) Purge() {
+	k.m.Lock()
+	defer k.m.Unlock()
+
+	now := k.now()
+	for r, e := range k.entries {
+		if !now.Before(e.expiresAt) {
+			delete(k.entries, r)
+		}
+	}
+}
+
+func (k *Keyring) Clear() {
+	k.m.Lock()
+	defer k.m.Unlock()
+
+	clear(k.entries)
+}
+
+func (k *Keyring) get(recipient encryption.Recipient) (encryption.Identity, bool) {
+	e, ok := k.entries[recipient.String()]
+	if !ok {
+		return encryption.Identity{}, false
+	}
+
+	if !k.now().Before(e.expiresAt) {
+		delete(k.entries, recipient.String())
+		return encryption.Identity{}, false
+	}
+
+	return e.identity, true
+}
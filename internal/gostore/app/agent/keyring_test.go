@@ -0,0 +1,102 @@
+package agent
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/UsingCoding/gostore/internal/gostore/app/encryption"
+)
+
+func TestKeyring(t *testing.T) {
+	var (
+		alice = encryption.Identity{
+			Provider:   "age",
+			Recipient:  encryption.Recipient("alice"),
+			PrivateKey: encryption.PrivateKey("alice-key"),
+		}
+		bob = encryption.Identity{
+			Provider:   "age",
+			Recipient:  encryption.Recipient("bob"),
+			PrivateKey: encryption.PrivateKey("bob-key"),
+		}
+	)
+
+	newKeyring := func() (*Keyring, *time.Time) {
+		now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		k := NewKeyring(time.Minute)
+		k.now = func() time.Time {
+			return now
+		}
+		return k, &now
+	}
+
+	t.Run("holds added identities", func(t *testing.T) {
+		k, _ := newKeyring()
+		k.Add(alice)
+
+		require.True(t, k.Has(alice.Recipient))
+		require.False(t, k.Has(bob.Recipient))
+		require.Equal(
+			t,
+			[]encryption.Identity{alice},
+			k.Identities([]encryption.Recipient{alice.Recipient, bob.Recipient}),
+		)
+		require.Empty(t, k.Identities([]encryption.Recipient{bob.Recipient}))
+	})
+
+	t.Run("identity expires after ttl", func(t *testing.T) {
+		k, now := newKeyring()
+		k.Add(alice)
+
+		*now = now.Add(time.Minute - time.Second)
+		k.Add(bob)
+		require.True(t, k.Has(alice.Recipient))
+
+		*now = now.Add(time.Second)
+		require.False(t, k.Has(alice.Recipient))
+		require.Equal(
+			t,
+			[]encryption.Identity{bob},
+			k.Identities([]encryption.Recipient{alice.Recipient, bob.Recipient}),
+		)
+	})
+
+	t.Run("adding identity again prolongs ttl", func(t *testing.T) {
+		k, now := newKeyring()
+		k.Add(alice)
+
+		*now = now.Add(time.Minute - time.Second)
+		k.Add(alice)
+
+		*now = now.Add(time.Second)
+		require.True(t, k.Has(alice.Recipient))
+	})
+
+	t.Run("purge removes only expired identities", func(t *testing.T) {
+		k, now := newKeyring()
+		k.Add(alice)
+
+		*now = now.Add(time.Second)
+		k.Add(bob)
+
+		*now = now.Add(time.Minute - time.Second)
+		k.Purge()
+
+		require.Len(t, k.entries, 1)
+		require.True(t, k.Has(bob.Recipient))
+	})
+
+	t.Run("clear removes all identities", func(t *testing.T) {
+		k, _ := newKeyring()
+		k.Add(alice)
+		k.Add(bob)
+
+		k.Clear()
+
+		require.False(t, k.Has(alice.Recipient))
+		require.False(t, k.Has(bob.Recipient))
+		require.Empty(t, k.entries)
+	})
+}
@@ -0,0 +1,73 @@
+package agent
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/UsingCoding/gostore/internal/common/maybe"
+	"github.com/UsingCoding/gostore/internal/gostore/app/encryption"
+	"github.com/UsingCoding/gostore/internal/gostore/app/store"
+)
+
+// LocalIdentityProvider resolves identities stored locally
+type LocalIdentityProvider interface {
+	store.IdentityProvider
+	// IdentityProtected reports whether local identity for recipient protected by passphrase, without unlocking it
+	IdentityProtected(ctx context.Context, recipient encryption.Recipient) (bool, error)
+}
+
+// NewIdentityProvider returns provider which uses agent for identities protected by passphrase.
+// Identity held by agent returned as reference and decrypted with by agent through manager from NewEncryptionManager.
+// When agent does not hold identity it unlocked locally and passed to agent.
+// Unprotected identities and identities when agent is not running resolved locally as is
+func NewIdentityProvider(client Client, local LocalIdentityProvider) store.IdentityProvider {
+	return &identityProvider{
+		client: client,
+		local:  local,
+	}
+}
+
+type identityProvider struct {
+	client Client
+	local  LocalIdentityProvider
+}
+
+func (p *identityProvider) IdentityByRecipient(ctx context.Context, recipient encryption.Recipient) (maybe.Maybe[encryption.Identity], error) {
+	protected, err := p.local.IdentityProtected(ctx, recipient)
+	if err != nil {
+		return maybe.Maybe[encryption.Identity]{}, err
+	}
+
+	if !protected {
+		return p.local.IdentityByRecipient(ctx, recipient)
+	}
+
+	held, err := p.client.HasIdentity(ctx, recipient)
+	switch {
+	case errors.Is(err, ErrAgentNotRunning):
+		return p.local.IdentityByRecipient(ctx, recipient)
+	case err != nil:
+		return maybe.Maybe[encryption.Identity]{}, errors.Wrap(err, "failed to ask agent for identity")
+	case held:
+		return maybe.NewJust(heldIdentity(recipient)), nil
+	}
+
+	i, err := p.local.IdentityByRecipient(ctx, recipient)
+	if err != nil {
+		return maybe.Maybe[encryption.Identity]{}, err
+	}
+
+	if identity, ok := maybe.JustValid(i); ok {
+		err = p.client.AddIdentity(ctx, identity)
+		if err != nil && !errors.Is(err, ErrAgentNotRunning) {
+			return maybe.Maybe[encryption.Identity]{}, errors.Wrap(err, "failed to add identity to agent")
+		}
+	}
+
+	return i, nil
+}
+
+func (p *identityProvider) IdentityRecipients(ctx context.Context) ([]encryption.Recipient, error) {
+	return p.local.IdentityRecipients(ctx)
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/UsingCoding/gostore/internal/common/maybe"
 	"github.com/UsingCoding/gostore/internal/gostore/app/encryption"
+	"github.com/UsingCoding/gostore/internal/gostore/app/storage"
 )
 
 type StoreID string
@@ -18,12 +19,51 @@ type Config struct {
 	Context maybe.Maybe[StoreID] // store ID
 	Stores  []Store              // paths to stores
 
-	Identities []encryption.Identity
+	Identities []Identity
+}
+
+type Identity struct {
+	encryption.Identity
+
+	// Protected means that PrivateKey encrypted with passphrase
+	Protected bool
 }
 
 type Store struct {
 	ID   StoreID
 	Path string
+
+	// Auth to access store remote
+	Auth maybe.Maybe[StoreAuth]
+}
+
+// StoreAuth configures credentials for store remote.
+// Secret values not kept in config, only source where to read them
+type StoreAuth struct {
+	Type storage.AuthType
+	User string
+	// KeyPath to SSH private key
+	KeyPath string
+	// Secret is source of password or token for HTTPS and passphrase for SSH key
+	Secret maybe.Maybe[SecretSource]
+}
+
+// SecretSource points to value in environment variable or in gostore store
+type SecretSource struct {
+	Env    maybe.Maybe[string]
+	Secret maybe.Maybe[SecretRef]
+}
+
+// SecretRef points to secret key in store
+type SecretRef struct {
+	StoreID StoreID
+	Path    string
+	Key     maybe.Maybe[string]
+}
+
+// PassphraseProvider asks passphrase to unlock protected identities
+type PassphraseProvider interface {
+	Passphrase(ctx context.Context, recipient encryption.Recipient) ([]byte, error)
 }
 
 type Storage interface {
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	stdslices "slices"
+
+	"github.com/pkg/errors"
+
+	"github.com/UsingCoding/gostore/internal/gostore/app/encryption"
+)
+
+func (s *service) ProtectIdentities(ctx context.Context, passphrase []byte, recipients ...encryption.Recipient) error {
+	if len(passphrase) == 0 {
+		return errors.New("empty passphrase")
+	}
+
+	config, err := s.storage.Load(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to load config")
+	}
+
+	for _, recipient := range recipients {
+		i := stdslices.IndexFunc(config.Identities, func(i Identity) bool {
+			return bytes.Equal(i.Recipient, recipient)
+		})
+		if i == -1 {
+			return errors.Errorf("identity for %s not found", recipient)
+		}
+		if config.Identities[i].Protected {
+			return errors.Errorf("identity for %s already protected", recipient)
+		}
+	}
+
+	var protected int
+	for i, identity := range config.Identities {
+		if identity.Protected {
+			continue
+		}
+
+		if len(recipients) != 0 && !stdslices.ContainsFunc(recipients, func(r encryption.Recipient) bool {
+			return bytes.Equal(r, identity.Recipient)
+		}) {
+			continue
+		}
+
+		key, err2 := s.encryptionManager.ProtectPrivateKey(identity.PrivateKey, passphrase)
+		if err2 != nil {
+			return errors.Wrapf(err2, "failed to protect identity for %s", identity.Recipient)
+		}
+
+		config.Identities[i].PrivateKey = key
+		config.Identities[i].Protected = true
+		protected++
+	}
+
+	if protected == 0 {
+		return errors.New("no identities to protect")
+	}
+
+	return s.storage.Store(ctx, config)
+}
+
+// unlock returns identity with decrypted private key.
+// Passphrase asked once per service lifetime and reused for other protected identities
+func (s *service) unlock(ctx context.Context, identity Identity) (encryption.Identity, error) {
+	if !identity.Protected {
+		return identity.Identity, nil
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if i, ok := s.unlocked[identity.Recipient.String()]; ok {
+		return i, nil
+	}
+
+	if s.passphrase != nil {
+		key, err := s.encryptionManager.UnprotectPrivateKey(identity.PrivateKey, s.passphrase)
+		if err == nil {
+			return s.cacheUnlocked(identity, key), nil
+		}
+		if !errors.Is(err, encryption.ErrInvalidPassphrase) {
+			return encryption.Identity{}, err
+		}
+	}
+
+	passphrase, err := s.passphraseProvider.Passphrase(ctx, identity.Recipient)
+	if err != nil {
+		return encryption.Identity{}, errors.Wrapf(err, "failed to get passphrase for %s", identity.Recipient)
+	}
+
+	key, err := s.encryptionManager.UnprotectPrivateKey(identity.PrivateKey, passphrase)
+	if err != nil {
+		return encryption.Identity{}, errors.Wrapf(err, "failed to unlock identity for %s", identity.Recipient)
+	}
+
+	s.passphrase = passphrase
+
+	return s.cacheUnlocked(identity, key), nil
+}
+
+func (s *service) cacheUnlocked(identity Identity, key encryption.PrivateKey) encryption.Identity {
+	i := identity.Identity
+	i.PrivateKey = key
+
+	s.unlocked[i.Recipient.String()] = i
+
+	return i
+}
@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"context"
 	stderrors "errors"
+	"fmt"
 	stdslices "slices"
+	"sync"
 
 	fpslice "github.com/UsingCoding/fpgo/pkg/slices"
 	"github.com/pkg/errors"
@@ -20,6 +22,7 @@ type StoreView struct {
 	ID      StoreID
 	Path    string
 	Current bool
+	Auth    maybe.Maybe[StoreAuth]
 }
 
 type Service interface {
@@ -35,10 +38,18 @@ type Service interface {
 
 	AddIdentity(ctx context.Context, identities ...encryption.Identity) error
 	AddStore(ctx context.Context, storeID StoreID, path string) error
+	// SetStoreAuth sets credentials to access store remote, none removes them
+	SetStoreAuth(ctx context.Context, storeID StoreID, auth maybe.Maybe[StoreAuth]) error
 
 	ImportRawIdentity(ctx context.Context, provider encryption.Provider, data []byte) error
 	ExportRawIdentity(ctx context.Context, recipients ...encryption.Recipient) ([][]byte, error)
 
+	// ProtectIdentities encrypts private keys of identities with passphrase.
+	// If no recipients passed all unprotected identities will be protected
+	ProtectIdentities(ctx context.Context, passphrase []byte, recipients ...encryption.Recipient) error
+	// IdentityProtected reports whether identity for recipient protected by passphrase, without unlocking it
+	IdentityProtected(ctx context.Context, recipient encryption.Recipient) (bool, error)
+
 	RemoveStore(ctx context.Context, storeID StoreID) error
 
 	store.IdentityProvider
@@ -50,12 +61,15 @@ func NewService(
 	gostoreLocation string,
 	storageManager storage.Manager,
 	encryptionManager encryption.Manager,
+	passphraseProvider PassphraseProvider,
 ) Service {
 	return &service{
-		storage:           s,
-		gostoreLocation:   gostoreLocation,
-		storageManager:    storageManager,
-		encryptionManager: encryptionManager,
+		storage:            s,
+		gostoreLocation:    gostoreLocation,
+		storageManager:     storageManager,
+		encryptionManager:  encryptionManager,
+		passphraseProvider: passphraseProvider,
+		unlocked:           map[string]encryption.Identity{},
 	}
 }
 
@@ -63,8 +77,14 @@ type service struct {
 	storage         Storage
 	gostoreLocation string
 
-	storageManager    storage.Manager
-	encryptionManager encryption.Manager
+	storageManager     storage.Manager
+	encryptionManager  encryption.Manager
+	passphraseProvider PassphraseProvider
+
+	// unlocked protected identities cached per invocation
+	m          sync.Mutex
+	unlocked   map[string]encryption.Identity
+	passphrase []byte
 }
 
 func (s *service) Init(ctx context.Context) error {
@@ -128,6 +148,7 @@ func (s *service) ListStores(ctx context.Context) ([]StoreView, error) {
 			ID:      s.ID,
 			Path:    s.Path,
 			Current: maybe.Valid(config.Context) && maybe.Just(config.Context) == s.ID,
+			Auth:    s.Auth,
 		}
 	}), nil
 }
@@ -151,6 +172,7 @@ func (s *service) StoreByID(ctx context.Context, storeID StoreID) (maybe.Maybe[S
 		ID:      foundStore.ID,
 		Path:    foundStore.Path,
 		Current: maybe.Valid(config.Context) && maybe.Just(config.Context) == foundStore.ID,
+		Auth:    foundStore.Auth,
 	}), nil
 }
 
@@ -161,7 +183,7 @@ func (s *service) AddIdentity(ctx context.Context, identities ...encryption.Iden
 	}
 
 	for _, identity := range identities {
-		i := stdslices.IndexFunc(config.Identities, func(i encryption.Identity) bool {
+		i := stdslices.IndexFunc(config.Identities, func(i Identity) bool {
 			return bytes.Equal(identity.Recipient, i.Recipient)
 		})
 
@@ -170,7 +192,9 @@ func (s *service) AddIdentity(ctx context.Context, identities ...encryption.Iden
 		}
 	}
 
-	config.Identities = append(config.Identities, identities...)
+	config.Identities = append(config.Identities, fpslice.Map(identities, func(i encryption.Identity) Identity {
+		return Identity{Identity: i}
+	})...)
 
 	err = s.storage.Store(ctx, config)
 	if err != nil {
@@ -208,29 +232,86 @@ func (s *service) AddStore(ctx context.Context, storeID StoreID, path string) er
 	return err
 }
 
+func (s *service) SetStoreAuth(ctx context.Context, storeID StoreID, auth maybe.Maybe[StoreAuth]) error {
+	config, err := s.storage.Load(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to load config")
+	}
+
+	i := stdslices.IndexFunc(config.Stores, func(s Store) bool {
+		return s.ID == storeID
+	})
+	if i == -1 {
+		return errors.Errorf("store with id %s not found", storeID)
+	}
+
+	config.Stores[i].Auth = auth
+
+	return s.storage.Store(ctx, config)
+}
+
 func (s *service) ImportRawIdentity(ctx context.Context, provider encryption.Provider, data []byte) error {
 	config, err := s.storage.Load(ctx)
 	if err != nil {
 		return errors.Wrap(err, "failed to load config")
 	}
 
-	identity, err := s.encryptionManager.ImportRawIdentity(provider, data)
+	identity, err := s.encryptionManager.ImportRawIdentity(provider, data, nil)
+	protected := false
+	var passphraseErr encryption.PassphraseRequiredError
+	if errors.As(err, &passphraseErr) {
+		identity, err = s.importProtectedIdentity(ctx, provider, data, passphraseErr.Recipient)
+		protected = true
+	}
 	if err != nil {
 		return err
 	}
 
-	i := stdslices.IndexFunc(config.Identities, func(i encryption.Identity) bool {
+	i := stdslices.IndexFunc(config.Identities, func(i Identity) bool {
 		return bytes.Equal(i.Recipient, identity.Recipient)
 	})
 
 	if i != -1 {
 		return errors.Errorf("identity with recipient %s alrteady added", identity.Recipient)
 	}
-	config.Identities = append(config.Identities, identity)
+	config.Identities = append(config.Identities, Identity{
+		Identity:  identity,
+		Protected: protected,
+	})
 
 	return s.storage.Store(ctx, config)
 }
 
+// importProtectedIdentity imports raw identity protected by passphrase,
+// private key kept protected with the same passphrase
+func (s *service) importProtectedIdentity(
+	ctx context.Context,
+	provider encryption.Provider,
+	data []byte,
+	recipient encryption.Recipient,
+) (encryption.Identity, error) {
+	if len(recipient) == 0 {
+		recipient = encryption.Recipient(fmt.Sprintf("%s key", provider))
+	}
+
+	passphrase, err := s.passphraseProvider.Passphrase(ctx, recipient)
+	if err != nil {
+		return encryption.Identity{}, errors.Wrapf(err, "failed to get passphrase for %s", recipient)
+	}
+
+	identity, err := s.encryptionManager.ImportRawIdentity(provider, data, passphrase)
+	if err != nil {
+		return encryption.Identity{}, err
+	}
+
+	identity.PrivateKey, err = s.encryptionManager.ProtectPrivateKey(identity.PrivateKey, passphrase)
+	if err != nil {
+		return encryption.Identity{}, errors.Wrapf(err, "failed to protect identity for %s", identity.Recipient)
+	}
+
+	return identity, nil
+}
+
 func (s *service) ExportRawIdentity(ctx context.Context, recipients ...encryption.Recipient) ([][]byte, error) {
 	config, err := s.storage.Load(ctx)
 	if err != nil {
@@ -238,7 +319,7 @@ func (s *service) ExportRawIdentity(ctx context.Context, recipients ...encryptio
 	}
 
 	return fpslice.MapErr(recipients, func(recipient encryption.Recipient) ([]byte, error) {
-		i := stdslices.IndexFunc(config.Identities, func(i encryption.Identity) bool {
+		i := stdslices.IndexFunc(config.Identities, func(i Identity) bool {
 			return bytes.Equal(i.Recipient, recipient)
 		})
 
@@ -246,7 +327,11 @@ func (s *service) ExportRawIdentity(ctx context.Context, recipients ...encryptio
 			return nil, errors.Errorf("identity for %s not found", recipient)
 		}
 
-		identity := config.Identities[i]
+		identity, err2 := s.unlock(ctx, config.Identities[i])
+		if err2 != nil {
+			return nil, err2
+		}
+
 		data, err2 := s.encryptionManager.ExportRawIdentity(identity)
 		if err2 != nil {
 			return nil, errors.Wrapf(err2, "failed to export raw identity for %s", identity.Recipient)
@@ -299,7 +384,7 @@ func (s *service) IdentityByRecipient(ctx context.Context, recipient encryption.
 		return maybe.Maybe[encryption.Identity]{}, errors.Wrap(err, "failed to load config")
 	}
 
-	i := stdslices.IndexFunc(config.Identities, func(i encryption.Identity) bool {
+	i := stdslices.IndexFunc(config.Identities, func(i Identity) bool {
 		return bytes.Equal(recipient, i.Recipient)
 	})
 
@@ -307,7 +392,36 @@ func (s *service) IdentityByRecipient(ctx context.Context, recipient encryption.
 		return maybe.Maybe[encryption.Identity]{}, nil
 	}
 
-	return maybe.NewJust(config.Identities[i]), nil
+	identity, err := s.unlock(ctx, config.Identities[i])
+	if err != nil {
+		return maybe.Maybe[encryption.Identity]{}, err
+	}
+
+	return maybe.NewJust(identity), nil
+}
+
+func (s *service) IdentityRecipients(ctx context.Context) ([]encryption.Recipient, error) {
+	config, err := s.storage.Load(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load config")
+	}
+
+	return fpslice.Map(config.Identities, func(i Identity) encryption.Recipient {
+		return i.Recipient
+	}), nil
+}
+
+func (s *service) IdentityProtected(ctx context.Context, recipient encryption.Recipient) (bool, error) {
+	config, err := s.storage.Load(ctx)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to load config")
+	}
+
+	i := stdslices.IndexFunc(config.Identities, func(i Identity) bool {
+		return bytes.Equal(recipient, i.Recipient)
+	})
+
+	return i != -1 && config.Identities[i].Protected, nil
 }
 
 func (s *service) CurrentStorePath(ctx context.Context) (maybe.Maybe[string], error) {
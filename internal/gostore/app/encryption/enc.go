@@ -1,9 +1,16 @@
 package encryption
 
+import (
+	"strings"
+)
+
 type Provider string
 
 const (
 	AgeIdentityProvider = "age"
+	// SSHIdentityProvider imports existing SSH keys as identities
+	SSHIdentityProvider = "ssh"
+	PGPIdentityProvider = "pgp"
 )
 
 type Identity struct {
@@ -18,6 +25,17 @@ func (k Recipient) String() string {
 	return string(k)
 }
 
+// ParseRecipient returns recipient from its text form.
+// Comment of SSH public key dropped, so recipient matches recipient of SSH identity
+func ParseRecipient(s string) Recipient {
+	fields := strings.Fields(s)
+	if len(fields) > 2 && strings.HasPrefix(fields[0], "ssh-") {
+		return Recipient(fields[0] + " " + fields[1])
+	}
+
+	return Recipient(strings.TrimSpace(s))
+}
+
 type PrivateKey []byte
 
 func (k PrivateKey) String() string {
@@ -27,4 +45,6 @@ func (k PrivateKey) String() string {
 type Service interface {
 	Encrypt(data []byte, recipients []Recipient) ([]byte, error)
 	Decrypt(data []byte, identities []Identity) ([]byte, error)
+	// CanonicalRecipient validates recipient and returns it in form equal to recipient of identity
+	CanonicalRecipient(r Recipient) (Recipient, error)
 }
@@ -1,5 +1,27 @@
 package encryption
 
+import (
+	stderrors "errors"
+	"fmt"
+)
+
+var (
+	ErrInvalidPassphrase = stderrors.New("invalid passphrase")
+)
+
+// PassphraseRequiredError returned on import of raw identity protected by passphrase
+type PassphraseRequiredError struct {
+	// Recipient of identity, empty when it can not be read without passphrase
+	Recipient Recipient
+}
+
+func (e PassphraseRequiredError) Error() string {
+	if len(e.Recipient) == 0 {
+		return "identity protected by passphrase"
+	}
+	return fmt.Sprintf("identity for %s protected by passphrase", e.Recipient)
+}
+
 type Manager interface {
 	// GenerateIdentity creates new identity
 	GenerateIdentity(encryption Encryption) (Identity, error)
@@ -7,6 +29,13 @@ type Manager interface {
 	PrivateKey(key Recipient) (PrivateKey, error)
 	EncryptService(encryption Encryption) (Service, error)
 
-	ImportRawIdentity(provider Provider, data []byte) (Identity, error)
+	// ImportRawIdentity parses identity, passphrase used for identities protected in raw form.
+	// PassphraseRequiredError returned when passphrase required but not passed
+	ImportRawIdentity(provider Provider, data, passphrase []byte) (Identity, error)
 	ExportRawIdentity(identity Identity) ([]byte, error)
+
+	// ProtectPrivateKey encrypts private key with passphrase
+	ProtectPrivateKey(key PrivateKey, passphrase []byte) (PrivateKey, error)
+	// UnprotectPrivateKey decrypts private key protected by ProtectPrivateKey
+	UnprotectPrivateKey(key PrivateKey, passphrase []byte) (PrivateKey, error)
 }
@@ -4,4 +4,5 @@ type Encryption string
 
 const (
 	AgeEncryption = Encryption("age")
+	PGPEncryption = Encryption("pgp")
 )
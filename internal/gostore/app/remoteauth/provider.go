@@ -0,0 +1,132 @@
+package remoteauth
+
+import (
+	"context"
+	"os"
+
+	"github.com/pkg/errors"
+
+	"github.com/UsingCoding/gostore/internal/common/maybe"
+	"github.com/UsingCoding/gostore/internal/common/slices"
+	"github.com/UsingCoding/gostore/internal/gostore/app/config"
+	"github.com/UsingCoding/gostore/internal/gostore/app/storage"
+	"github.com/UsingCoding/gostore/internal/gostore/app/store"
+)
+
+// StoreServiceFactory returns store service for store with ID
+type StoreServiceFactory func(storeID config.StoreID) store.Service
+
+// Provider resolves credentials configured for stores
+type Provider interface {
+	store.RemoteAuthProvider
+
+	// ForAuth returns storage auth provider for credentials config
+	ForAuth(auth maybe.Maybe[config.StoreAuth]) storage.AuthProvider
+}
+
+func NewProvider(configService config.Service, storeServiceFactory StoreServiceFactory) Provider {
+	return &provider{
+		configService:       configService,
+		storeServiceFactory: storeServiceFactory,
+	}
+}
+
+type provider struct {
+	configService       config.Service
+	storeServiceFactory StoreServiceFactory
+}
+
+func (p *provider) RemoteAuth(storePath string) storage.AuthProvider {
+	return authProviderFunc(func(ctx context.Context) (maybe.Maybe[storage.Auth], error) {
+		stores, err := p.configService.ListStores(ctx)
+		if err != nil {
+			return maybe.Maybe[storage.Auth]{}, err
+		}
+
+		s, ok := maybe.JustValid(slices.Find(stores, func(s config.StoreView) bool {
+			return s.Path == storePath
+		}))
+		if !ok {
+			return maybe.Maybe[storage.Auth]{}, nil
+		}
+
+		return p.resolve(ctx, s.Auth)
+	})
+}
+
+func (p *provider) ForAuth(auth maybe.Maybe[config.StoreAuth]) storage.AuthProvider {
+	return authProviderFunc(func(ctx context.Context) (maybe.Maybe[storage.Auth], error) {
+		return p.resolve(ctx, auth)
+	})
+}
+
+func (p *provider) resolve(ctx context.Context, a maybe.Maybe[config.StoreAuth]) (maybe.Maybe[storage.Auth], error) {
+	auth, ok := maybe.JustValid(a)
+	if !ok {
+		return maybe.Maybe[storage.Auth]{}, nil
+	}
+
+	res := storage.Auth{
+		Type:    auth.Type,
+		User:    auth.User,
+		KeyPath: auth.KeyPath,
+	}
+
+	if src, ok2 := maybe.JustValid(auth.Secret); ok2 {
+		secret, err := p.secret(ctx, src)
+		if err != nil {
+			return maybe.Maybe[storage.Auth]{}, errors.Wrap(err, "failed to read auth secret")
+		}
+		res.Secret = secret
+	}
+
+	return maybe.NewJust(res), nil
+}
+
+func (p *provider) secret(ctx context.Context, src config.SecretSource) ([]byte, error) {
+	if env, ok := maybe.JustValid(src.Env); ok {
+		v, exists := os.LookupEnv(env)
+		if !exists {
+			return nil, errors.Errorf("environment variable %s not set", env)
+		}
+		return []byte(v), nil
+	}
+
+	ref, ok := maybe.JustValid(src.Secret)
+	if !ok {
+		return nil, errors.New("empty secret source")
+	}
+
+	data, err := p.storeServiceFactory(ref.StoreID).Get(ctx, store.GetParams{
+		SecretIndex: store.SecretIndex{
+			Path: ref.Path,
+			Key:  ref.Key,
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get secret %s from store %s", ref.Path, ref.StoreID)
+	}
+
+	switch len(data) {
+	case 0:
+		return nil, errors.Errorf("secret %s not found in store %s", ref.Path, ref.StoreID)
+	case 1:
+		return data[0].Payload, nil
+	}
+
+	// several keys in secret, use default one
+	d, ok := maybe.JustValid(slices.Find(data, func(d store.SecretData) bool {
+		return d.Default
+	}))
+	if !ok {
+		return nil, errors.Errorf("secret %s in store %s has several keys, specify one", ref.Path, ref.StoreID)
+	}
+
+	return d.Payload, nil
+}
+
+type authProviderFunc func(ctx context.Context) (maybe.Maybe[storage.Auth], error)
+
+func (f authProviderFunc) Auth(ctx context.Context) (maybe.Maybe[storage.Auth], error) {
+	return f(ctx)
+}
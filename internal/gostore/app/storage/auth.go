@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/UsingCoding/gostore/internal/common/maybe"
+)
+
+type AuthType string
+
+const (
+	// SSHKeyAuth authenticates with SSH private key from file
+	SSHKeyAuth = AuthType("ssh-key")
+	// SSHAgentAuth authenticates with keys from running ssh-agent
+	SSHAgentAuth = AuthType("ssh-agent")
+	// BasicAuth authenticates over HTTPS with user and password
+	BasicAuth = AuthType("basic")
+	// TokenAuth authenticates over HTTPS with access token passed as password
+	TokenAuth = AuthType("token")
+)
+
+// Auth describes credentials to access storage remote
+type Auth struct {
+	Type AuthType
+	// User for SSH or HTTPS basic auth
+	User string
+	// KeyPath to SSH private key
+	KeyPath string
+	// Secret is password or token for HTTPS and passphrase for SSH key
+	Secret []byte
+}
+
+// AuthProvider returns credentials for storage remote. Called only when remote accessed
+type AuthProvider interface {
+	Auth(ctx context.Context) (maybe.Maybe[Auth], error)
+}
+
+// StaticAuth returns provider of already known credentials
+func StaticAuth(auth maybe.Maybe[Auth]) AuthProvider {
+	return staticAuth{auth: auth}
+}
+
+type staticAuth struct {
+	auth maybe.Maybe[Auth]
+}
+
+func (s staticAuth) Auth(context.Context) (maybe.Maybe[Auth], error) {
+	return s.auth, nil
+}
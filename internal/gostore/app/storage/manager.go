@@ -10,22 +10,27 @@ type Type string
 
 const (
 	GITType = Type("git")
+	// FSType keeps store as plain files with local journal of commits
+	FSType = Type("fs")
+	// S3Type keeps store in S3-compatible bucket shared by store users
+	S3Type = Type("s3")
 )
 
 // Manager manages stores: create, delete, mount
 type Manager interface {
-	// Init creates storage locally
+	// Init creates storage locally. auth used to access storage remote
 	Init(
 		ctx context.Context,
 		path string,
 		remote maybe.Maybe[string],
 		t Type,
+		auth AuthProvider,
 	) (Storage, error)
 	// Clone copies Storage from remote to path
-	Clone(ctx context.Context, path string, remote string, t Type) (Storage, error)
+	Clone(ctx context.Context, path string, remote string, t Type, auth AuthProvider) (Storage, error)
 
-	// Use local copy of store by path
-	Use(ctx context.Context, path string) (Storage, error)
+	// Use local copy of store by path. auth used to access storage remote
+	Use(ctx context.Context, path string, auth AuthProvider) (Storage, error)
 
 	// Remove local storage copy
 	Remove(ctx context.Context, path string) error
@@ -0,0 +1,13 @@
+package storage
+
+import (
+	"time"
+)
+
+// Revision is committed state of storage
+type Revision struct {
+	ID      string
+	Author  string
+	Time    time.Time
+	Message string
+}
@@ -21,15 +21,29 @@ type Storage interface {
 	Get(ctx context.Context, path string) (maybe.Maybe[[]byte], error)
 	// GetLatest reruns latest version of object
 	GetLatest(ctx context.Context, p string) (maybe.Maybe[[]byte], error)
+	// GetAt returns version of object at revision. Revision format depends on storage implementation
+	GetAt(ctx context.Context, p string, revision string) (maybe.Maybe[[]byte], error)
+	// History returns revisions which changed path, newest first
+	History(ctx context.Context, p string) ([]Revision, error)
+	// ResolveRevision returns full revision ID if revision exists in storage
+	ResolveRevision(ctx context.Context, revision string) (maybe.Maybe[string], error)
 	// List storage entries
 	List(ctx context.Context, path string) (Tree, error)
+	// ListAt lists storage entries at revision
+	ListAt(ctx context.Context, path string, revision string) (Tree, error)
 
 	// AddRemote to storage. remoteAddr depends on storage implementation
 	AddRemote(ctx context.Context, remoteName string, remoteAddr string) error
 	// Push storage to remote if there is one
 	Push(ctx context.Context) error
-	// Pull changes from remote
-	Pull(ctx context.Context) error
+	// Fetch changes from remote without applying them, returns remote revision if remote has one
+	Fetch(ctx context.Context) (maybe.Maybe[string], error)
+	// MergeBase returns common ancestor of revisions if there is one
+	MergeBase(ctx context.Context, revision1, revision2 string) (maybe.Maybe[string], error)
+	// FastForward moves storage to revision which descends from current one
+	FastForward(ctx context.Context, revision string) error
+	// Merge commits current changes as merge of current state and revision
+	Merge(ctx context.Context, revision string, msg string) error
 
 	// Commit changes to storage. Semantics depends on storage implementation
 	Commit(ctx context.Context, msg string) error
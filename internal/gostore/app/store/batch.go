@@ -0,0 +1,62 @@
+package store
+
+import (
+	"context"
+	stderrors "errors"
+
+	"github.com/pkg/errors"
+)
+
+// Tx applies changes to store loaded once for batch, reads see changes made earlier in batch
+type Tx interface {
+	Add(ctx context.Context, params AddParams) error
+	Import(ctx context.Context, params ImportParams) error
+	Copy(ctx context.Context, params CopyParams) error
+	Move(ctx context.Context, params MoveParams) error
+	Remove(ctx context.Context, params RemoveParams) error
+
+	Get(ctx context.Context, params SecretIndex) ([]SecretData, error)
+}
+
+func (service *storeService) Batch(ctx context.Context, f func(tx Tx) error) error {
+	s, err := service.loadStore(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to load store")
+	}
+
+	err = f(storeTx{store: s})
+	if err != nil {
+		rollbackErr := s.rollback(ctx)
+		return stderrors.Join(err, errors.Wrap(rollbackErr, "failed to rollback batch"))
+	}
+
+	return s.close()
+}
+
+type storeTx struct {
+	store *store
+}
+
+func (tx storeTx) Add(ctx context.Context, params AddParams) error {
+	return tx.store.add(ctx, params.Path, params.Key, params.Data)
+}
+
+func (tx storeTx) Import(ctx context.Context, params ImportParams) error {
+	return tx.store.importData(ctx, params)
+}
+
+func (tx storeTx) Copy(ctx context.Context, params CopyParams) error {
+	return tx.store.copy(ctx, params.Src, params.Dst)
+}
+
+func (tx storeTx) Move(ctx context.Context, params MoveParams) error {
+	return tx.store.move(ctx, params.Src, params.Dst)
+}
+
+func (tx storeTx) Remove(ctx context.Context, params RemoveParams) error {
+	return tx.store.remove(ctx, params.Path, params.Key)
+}
+
+func (tx storeTx) Get(ctx context.Context, index SecretIndex) ([]SecretData, error) {
+	return tx.store.get(ctx, index.Path, index.Key)
+}
@@ -0,0 +1,292 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"path"
+	stdslices "slices"
+
+	"github.com/pkg/errors"
+
+	"github.com/UsingCoding/gostore/internal/common/maybe"
+	"github.com/UsingCoding/gostore/internal/gostore/app/encryption"
+	"github.com/UsingCoding/gostore/internal/gostore/app/storage"
+)
+
+const (
+	headRevision = "HEAD"
+)
+
+type DiffStatus string
+
+const (
+	DiffAdded   = DiffStatus("added")
+	DiffRemoved = DiffStatus("removed")
+	DiffChanged = DiffStatus("changed")
+)
+
+type SecretDiff struct {
+	Path   string
+	Status DiffStatus
+	Keys   []KeyDiff
+	// NoAccess is true when local identities can not decrypt secret,
+	// so keys with different encrypted values reported as changed and values not shown
+	NoAccess bool
+}
+
+type KeyDiff struct {
+	Name   string
+	Status DiffStatus
+
+	// Old and New contain decrypted values only when values requested
+	Old maybe.Maybe[[]byte]
+	New maybe.Maybe[[]byte]
+}
+
+func (s *store) resolveStorageRevision(ctx context.Context, revision string) (maybe.Maybe[string], error) {
+	return s.storage.ResolveRevision(ctx, revision)
+}
+
+func (s *store) diff(ctx context.Context, params DiffParams) ([]SecretDiff, error) {
+	err := s.assertPacked()
+	if err != nil {
+		return nil, err
+	}
+
+	if params.Path != "" {
+		err = allowedPaths(params.Path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	from := maybe.MapNone(params.From, func() string {
+		return headRevision
+	})
+
+	fromPaths, err := s.secretPaths(ctx, params.Path, maybe.NewJust(from))
+	if err != nil {
+		return nil, err
+	}
+
+	toPaths, err := s.secretPaths(ctx, params.Path, params.To)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := uniqueSorted(stdslices.Concat(fromPaths, toPaths))
+
+	local, err := s.identityProvider.IdentityRecipients(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	identities, err := s.identities(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []SecretDiff
+	for _, p := range paths {
+		fromData, err2 := s.storage.GetAt(ctx, p, from)
+		if err2 != nil {
+			return nil, err2
+		}
+
+		toData, err2 := s.dataAt(ctx, p, params.To)
+		if err2 != nil {
+			return nil, err2
+		}
+
+		accessible := anyRecipient(local, s.manifest.recipientsFor(p))
+
+		d, err2 := s.diffSecret(p, fromData, toData, identities, accessible, params.ShowValues)
+		if err2 != nil {
+			return nil, errors.Wrapf(err2, "failed to diff secret %s", p)
+		}
+
+		if d, ok := maybe.JustValid(d); ok {
+			res = append(res, d)
+		}
+	}
+
+	return res, nil
+}
+
+// diffSecret compares secret keys, returns none when decrypted values are equal.
+// Values of not accessible secret are not decrypted, its keys compared by encrypted values
+func (s *store) diffSecret(
+	p string,
+	fromData, toData maybe.Maybe[[]byte],
+	identities []encryption.Identity,
+	accessible bool,
+	showValues bool,
+) (maybe.Maybe[SecretDiff], error) {
+	if maybe.Valid(fromData) && maybe.Valid(toData) && bytes.Equal(maybe.Just(fromData), maybe.Just(toData)) {
+		return maybe.Maybe[SecretDiff]{}, nil
+	}
+
+	fromSecret, err := s.deserializeMaybe(fromData)
+	if err != nil {
+		return maybe.Maybe[SecretDiff]{}, err
+	}
+
+	toSecret, err := s.deserializeMaybe(toData)
+	if err != nil {
+		return maybe.Maybe[SecretDiff]{}, err
+	}
+
+	decrypt := func(data []byte) (maybe.Maybe[[]byte], error) {
+		if !showValues || !accessible {
+			return maybe.Maybe[[]byte]{}, nil
+		}
+
+		v, err2 := s.encryption.Decrypt(data, identities)
+		if err2 != nil {
+			return maybe.Maybe[[]byte]{}, err2
+		}
+		return maybe.NewJust(v), nil
+	}
+
+	var keys []KeyDiff
+	for _, k := range unionKeys(fromSecret, toSecret) {
+		fromV, inFrom := fromSecret.Payload[k]
+		toV, inTo := toSecret.Payload[k]
+
+		kd := KeyDiff{Name: k}
+
+		switch {
+		case !inFrom:
+			kd.Status = DiffAdded
+			kd.New, err = decrypt(toV)
+		case !inTo:
+			kd.Status = DiffRemoved
+			kd.Old, err = decrypt(fromV)
+		default:
+			if bytes.Equal(fromV, toV) {
+				continue
+			}
+
+			if !accessible {
+				kd.Status = DiffChanged
+				break
+			}
+
+			// encrypted values differ even for same data, so compare decrypted ones
+			var decryptedFrom, decryptedTo []byte
+			decryptedFrom, err = s.encryption.Decrypt(fromV, identities)
+			if err != nil {
+				return maybe.Maybe[SecretDiff]{}, err
+			}
+			decryptedTo, err = s.encryption.Decrypt(toV, identities)
+			if err != nil {
+				return maybe.Maybe[SecretDiff]{}, err
+			}
+
+			if bytes.Equal(decryptedFrom, decryptedTo) {
+				continue
+			}
+
+			kd.Status = DiffChanged
+			if showValues {
+				kd.Old = maybe.NewJust(decryptedFrom)
+				kd.New = maybe.NewJust(decryptedTo)
+			}
+		}
+		if err != nil {
+			return maybe.Maybe[SecretDiff]{}, err
+		}
+
+		keys = append(keys, kd)
+	}
+
+	d := SecretDiff{
+		Path:     p,
+		Keys:     keys,
+		NoAccess: !accessible,
+	}
+
+	switch {
+	case !maybe.Valid(fromData):
+		d.Status = DiffAdded
+	case !maybe.Valid(toData):
+		d.Status = DiffRemoved
+	case len(keys) == 0:
+		return maybe.Maybe[SecretDiff]{}, nil
+	default:
+		d.Status = DiffChanged
+	}
+
+	return maybe.NewJust(d), nil
+}
+
+// secretPaths lists secrets under path at revision or in current state
+func (s *store) secretPaths(ctx context.Context, p string, revision maybe.Maybe[string]) ([]string, error) {
+	var (
+		tree storage.Tree
+		err  error
+	)
+	if r, ok := maybe.JustValid(revision); ok {
+		tree, err = s.storage.ListAt(ctx, p, r)
+	} else {
+		tree, err = s.storage.List(ctx, p)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	//nolint:prealloc
+	var res []string
+	for _, k := range tree.Inline().Keys() {
+		full := path.Join(p, k)
+		if p == "" && allowedPaths(full) != nil {
+			continue
+		}
+		res = append(res, full)
+	}
+
+	if len(res) == 0 && p != "" {
+		// path may point to secret itself
+		data, err2 := s.dataAt(ctx, p, revision)
+		if err2 != nil {
+			return nil, err2
+		}
+		if maybe.Valid(data) {
+			res = append(res, p)
+		}
+	}
+
+	return res, nil
+}
+
+func (s *store) dataAt(ctx context.Context, p string, revision maybe.Maybe[string]) (maybe.Maybe[[]byte], error) {
+	if r, ok := maybe.JustValid(revision); ok {
+		return s.storage.GetAt(ctx, p, r)
+	}
+
+	return s.storage.Get(ctx, p)
+}
+
+func (s *store) deserializeMaybe(data maybe.Maybe[[]byte]) (Secret, error) {
+	if !maybe.Valid(data) {
+		return initSecret(), nil
+	}
+
+	return s.secretSerializer.Deserialize(maybe.Just(data))
+}
+
+func unionKeys(secrets ...Secret) []string {
+	var keys []string
+	for _, secret := range secrets {
+		for k := range secret.Payload {
+			keys = append(keys, k)
+		}
+	}
+
+	return uniqueSorted(keys)
+}
+
+func uniqueSorted(s []string) []string {
+	stdslices.Sort(s)
+	return stdslices.Compact(s)
+}
@@ -0,0 +1,123 @@
+package store
+
+import (
+	"context"
+	"path"
+
+	"github.com/pkg/errors"
+
+	"github.com/UsingCoding/gostore/internal/common/maybe"
+	"github.com/UsingCoding/gostore/internal/gostore/app/encryption"
+)
+
+// Matcher checks whether string satisfies search query
+type Matcher interface {
+	Match(s string) bool
+}
+
+// FindResult describes secret matched search query
+type FindResult struct {
+	Path string
+	// PathMatched is true when query matched secret path
+	PathMatched bool
+	// Keys names of secret keys matched query
+	Keys []string
+	// Tags of secret matched query
+	Tags []string
+	// Values are names of keys which decrypted values matched query
+	Values []string
+	// NoAccess is true when values of secret not searched since local identities can not decrypt it
+	NoAccess bool
+}
+
+func (r FindResult) matched() bool {
+	return r.PathMatched || len(r.Keys) > 0 || len(r.Tags) > 0 || len(r.Values) > 0 || r.NoAccess
+}
+
+// find searches secrets under path by path, key names and tags.
+// Values decrypted and searched only when grep requested, secrets which local identities can not decrypt reported as NoAccess
+func (s *store) find(ctx context.Context, params FindParams) ([]FindResult, error) {
+	err := s.assertPacked()
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := s.list(ctx, params.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	local, err := s.identityProvider.IdentityRecipients(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get identities recipients")
+	}
+
+	// identities resolved once and only when there is value to decrypt
+	var identities []encryption.Identity
+	decrypt := func(data []byte) ([]byte, error) {
+		if identities == nil {
+			identities, err = s.identities(ctx)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return s.encryption.Decrypt(data, identities)
+	}
+
+	var res []FindResult
+	for _, entryPath := range tree.Inline().Keys() {
+		p := path.Join(params.Path, entryPath)
+
+		data, err2 := s.storage.Get(ctx, p)
+		if err2 != nil {
+			return nil, err2
+		}
+
+		secretBytes, ok := maybe.JustValid(data)
+		if !ok {
+			continue
+		}
+
+		secret, err2 := s.secretSerializer.Deserialize(secretBytes)
+		if err2 != nil {
+			return nil, errors.Wrapf(err2, "failed to deserialize secret at %s", p)
+		}
+
+		r := FindResult{
+			Path:        p,
+			PathMatched: params.Matcher.Match(p),
+			NoAccess:    params.Grep && !anyRecipient(local, s.manifest.recipientsFor(p)),
+		}
+
+		for _, k := range secret.keys() {
+			if params.Matcher.Match(k) {
+				r.Keys = append(r.Keys, k)
+			}
+
+			if !params.Grep || r.NoAccess {
+				continue
+			}
+
+			value, err3 := decrypt(secret.Payload[k])
+			if err3 != nil {
+				return nil, err3
+			}
+
+			if params.Matcher.Match(string(value)) {
+				r.Values = append(r.Values, k)
+			}
+		}
+
+		for _, tag := range secret.Metadata.Tags {
+			if params.Matcher.Match(tag) {
+				r.Tags = append(r.Tags, tag)
+			}
+		}
+
+		if r.matched() {
+			res = append(res, r)
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,140 @@
+package store
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/UsingCoding/gostore/internal/common/maybe"
+	"github.com/UsingCoding/gostore/internal/gostore/app/storage"
+)
+
+func (s *store) history(ctx context.Context, path string) ([]storage.Revision, error) {
+	err := allowedPaths(path)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.storage.History(ctx, path)
+	return revisions, errors.Wrapf(err, "failed to get history of %s", path)
+}
+
+func (s *store) restore(ctx context.Context, path string, point HistoryPoint) error {
+	err := s.assertPacked()
+	if err != nil {
+		return err
+	}
+
+	err = allowedPaths(path)
+	if err != nil {
+		return err
+	}
+
+	resolved, err := s.resolveRevision(ctx, path, point)
+	if err != nil {
+		return err
+	}
+
+	revision, ok := maybe.JustValid(resolved)
+	if !ok {
+		return errors.Errorf("no revision of %s found at %s", path, point)
+	}
+
+	data, err := s.storage.GetAt(ctx, path, revision)
+	if err != nil {
+		return err
+	}
+
+	if !maybe.Valid(data) {
+		return errors.Errorf("secret %s not exists at %s", path, point)
+	}
+
+	secretBytes := maybe.Just(data)
+
+	recipientsChanged, err := s.recipientsChangedSince(ctx, path, revision)
+	if err != nil {
+		return err
+	}
+
+	// re-encrypt restored secret to not grant access to removed recipients
+	if recipientsChanged {
+		identities, err2 := s.identities(ctx)
+		if err2 != nil {
+			return err2
+		}
+
+		secretBytes, err = s.reencryptSecretData(secretBytes, identities, s.manifest.recipientsFor(path))
+		if err != nil {
+			return errors.Wrapf(err, "failed to re-encrypt restored secret %s", path)
+		}
+	}
+
+	err = s.storage.Store(ctx, path, secretBytes)
+	if err != nil {
+		return err
+	}
+
+	s.operations.add(restoreOperation(path, point))
+
+	return nil
+}
+
+// historyData returns secret data at history point
+func (s *store) historyData(ctx context.Context, path string, point HistoryPoint) (maybe.Maybe[[]byte], error) {
+	resolved, err := s.resolveRevision(ctx, path, point)
+	if err != nil {
+		return maybe.Maybe[[]byte]{}, err
+	}
+
+	revision, ok := maybe.JustValid(resolved)
+	if !ok {
+		return maybe.Maybe[[]byte]{}, nil
+	}
+
+	return s.storage.GetAt(ctx, path, revision)
+}
+
+// resolveRevision returns revision of history point.
+// For point in time it is the latest revision changed path made before that time
+func (s *store) resolveRevision(ctx context.Context, path string, point HistoryPoint) (maybe.Maybe[string], error) {
+	if r, ok := maybe.JustValid(point.Revision); ok {
+		return maybe.NewJust(r), nil
+	}
+
+	t, ok := maybe.JustValid(point.Time)
+	if !ok {
+		return maybe.Maybe[string]{}, errors.New("empty history point")
+	}
+
+	revisions, err := s.history(ctx, path)
+	if err != nil {
+		return maybe.Maybe[string]{}, err
+	}
+
+	for _, r := range revisions {
+		if !r.Time.After(t) {
+			return maybe.NewJust(r.ID), nil
+		}
+	}
+
+	return maybe.Maybe[string]{}, nil
+}
+
+// recipientsChangedSince checks that recipients of secret at path changed since revision
+func (s *store) recipientsChangedSince(ctx context.Context, path, revision string) (bool, error) {
+	data, err := s.storage.GetAt(ctx, ManifestPath, revision)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get manifest at %s", revision)
+	}
+
+	if !maybe.Valid(data) {
+		return true, nil
+	}
+
+	m, err := s.manifestSerializer.Deserialize(maybe.Just(data))
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to deserialize manifest at %s", revision)
+	}
+
+	return !sameRecipients(m.recipientsFor(path), s.manifest.recipientsFor(path)), nil
+}
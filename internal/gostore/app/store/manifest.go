@@ -1,6 +1,8 @@
 package store
 
 import (
+	"strings"
+
 	"github.com/UsingCoding/gostore/internal/gostore/app/encryption"
 	"github.com/UsingCoding/gostore/internal/gostore/app/storage"
 )
@@ -14,6 +16,64 @@ type Manifest struct {
 	Encryption  encryption.Encryption
 	Recipients  []encryption.Recipient
 	Unpacked    bool
+	// PasswordPolicy for passwords generated in store
+	PasswordPolicy PasswordPolicy
+	// Scopes override recipients for secrets in subtrees of store
+	Scopes []RecipientsScope
+}
+
+// RecipientsScope replaces store recipients for secrets under Path
+type RecipientsScope struct {
+	Path       string
+	Recipients []encryption.Recipient
+}
+
+// recipientsFor returns recipients of the deepest scope containing path or store recipients
+func (m Manifest) recipientsFor(p string) []encryption.Recipient {
+	recipients := m.Recipients
+	depth := -1
+	for _, scope := range m.Scopes {
+		if !inScope(scope.Path, p) || len(scope.Path) <= depth {
+			continue
+		}
+
+		recipients = scope.Recipients
+		depth = len(scope.Path)
+	}
+
+	return recipients
+}
+
+// allRecipients returns store recipients with recipients of all scopes
+func (m Manifest) allRecipients() []encryption.Recipient {
+	res := append([]encryption.Recipient{}, m.Recipients...)
+	for _, scope := range m.Scopes {
+		for _, r := range scope.Recipients {
+			if !containsRecipient(res, r) {
+				res = append(res, r)
+			}
+		}
+	}
+
+	return res
+}
+
+func (m Manifest) scopeIndex(p string) int {
+	for i, scope := range m.Scopes {
+		if scope.Path == p {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func inScope(scopePath, p string) bool {
+	return p == scopePath || strings.HasPrefix(p, scopePath+"/")
+}
+
+func normalizeScopePath(p string) string {
+	return strings.Trim(p, "/")
 }
 
 type ManifestSerializer interface {
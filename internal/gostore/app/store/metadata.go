@@ -0,0 +1,159 @@
+package store
+
+import (
+	"context"
+	"path"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/UsingCoding/gostore/internal/common/maybe"
+)
+
+// Metadata is non-secret information about secret, stored unencrypted alongside payload
+type Metadata struct {
+	Created maybe.Maybe[time.Time]
+	Updated maybe.Maybe[time.Time]
+	// Author of last change
+	Author      maybe.Maybe[string]
+	Tags        []string
+	Description maybe.Maybe[string]
+}
+
+// touch records change of secret by author
+func (m *Metadata) touch(author string, now time.Time) {
+	if !maybe.Valid(m.Created) {
+		m.Created = maybe.NewJust(now)
+	}
+	m.Updated = maybe.NewJust(now)
+	m.Author = maybe.MapZero(author)
+}
+
+// mergeMetadata keeps metadata of latest changed side and earliest creation time
+func mergeMetadata(ours, theirs Metadata) Metadata {
+	res := ours
+	if updatedAfter(theirs, ours) {
+		res = theirs
+	}
+
+	if c, ok := maybe.JustValid(ours.Created); ok {
+		if tc, ok2 := maybe.JustValid(theirs.Created); !ok2 || c.Before(tc) {
+			res.Created = ours.Created
+		}
+	}
+
+	return res
+}
+
+func updatedAfter(m, other Metadata) bool {
+	u, ok := maybe.JustValid(m.Updated)
+	if !ok {
+		return false
+	}
+
+	o, ok := maybe.JustValid(other.Updated)
+	return !ok || u.After(o)
+}
+
+func (s *store) metadata(ctx context.Context, p string, at maybe.Maybe[HistoryPoint]) (maybe.Maybe[Metadata], error) {
+	err := s.assertPacked()
+	if err != nil {
+		return maybe.Maybe[Metadata]{}, err
+	}
+
+	err = allowedPaths(p)
+	if err != nil {
+		return maybe.Maybe[Metadata]{}, err
+	}
+
+	var secretBytes maybe.Maybe[[]byte]
+	if point, ok := maybe.JustValid(at); ok {
+		secretBytes, err = s.historyData(ctx, p, point)
+	} else {
+		secretBytes, err = s.storage.Get(ctx, p)
+	}
+	if err != nil {
+		return maybe.Maybe[Metadata]{}, err
+	}
+
+	if !maybe.Valid(secretBytes) {
+		return maybe.Maybe[Metadata]{}, nil
+	}
+
+	secret, err := s.secretSerializer.Deserialize(maybe.Just(secretBytes))
+	if err != nil {
+		return maybe.Maybe[Metadata]{}, errors.Wrapf(err, "failed to deserialize secret at %s", p)
+	}
+
+	return maybe.NewJust(secret.Metadata), nil
+}
+
+// listMetadata returns metadata of secrets under path by their path relative to it
+func (s *store) listMetadata(ctx context.Context, p string) (map[string]Metadata, error) {
+	tree, err := s.list(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	res := map[string]Metadata{}
+	for _, entryPath := range tree.Inline().Keys() {
+		m, err2 := s.metadata(ctx, path.Join(p, entryPath), maybe.Maybe[HistoryPoint]{})
+		if err2 != nil {
+			return nil, err2
+		}
+
+		if v, ok := maybe.JustValid(m); ok {
+			res[entryPath] = v
+		}
+	}
+
+	return res, nil
+}
+
+func (s *store) setMetadata(ctx context.Context, params SetMetadataParams) error {
+	err := s.assertPacked()
+	if err != nil {
+		return err
+	}
+
+	err = allowedPaths(params.Path)
+	if err != nil {
+		return err
+	}
+
+	secretBytes, err := s.storage.Get(ctx, params.Path)
+	if err != nil {
+		return err
+	}
+
+	if !maybe.Valid(secretBytes) {
+		return errors.Errorf("secret %s not found", params.Path)
+	}
+
+	secret, err := s.secretSerializer.Deserialize(maybe.Just(secretBytes))
+	if err != nil {
+		return errors.Wrapf(err, "failed to deserialize secret at %s", params.Path)
+	}
+
+	if tags, ok := maybe.JustValid(params.Tags); ok {
+		secret.Metadata.Tags = tags
+	}
+	if d, ok := maybe.JustValid(params.Description); ok {
+		secret.Metadata.Description = maybe.MapZero(d)
+	}
+	secret.Metadata.touch(s.authorProvider.Author(ctx), time.Now())
+
+	data, err := s.secretSerializer.Serialize(secret)
+	if err != nil {
+		return err
+	}
+
+	err = s.storage.Store(ctx, params.Path, data)
+	if err != nil {
+		return err
+	}
+
+	s.operations.add(setMetadataOperation(params.Path))
+
+	return nil
+}
@@ -0,0 +1,163 @@
+package store
+
+import (
+	"context"
+
+	"github.com/UsingCoding/fpgo/pkg/slices"
+	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/UsingCoding/gostore/internal/common/maybe"
+	"github.com/UsingCoding/gostore/internal/gostore/app/encryption"
+	"github.com/UsingCoding/gostore/internal/gostore/app/progress"
+)
+
+// migrateEncryption decrypts every secret value with current encryption and encrypts it with passed one
+// for recipients of store or of scope containing secret.
+// On success manifest encryption, recipients and recipients of scopes replaced
+func (s *store) migrateEncryption(
+	ctx context.Context,
+	enc encryption.Encryption,
+	service encryption.Service,
+	recipients []encryption.Recipient,
+	scopes []RecipientsScope,
+) error {
+	err := s.assertPacked()
+	if err != nil {
+		return err
+	}
+
+	if enc == s.manifest.Encryption {
+		return errors.Errorf("store already encrypted with %s", enc)
+	}
+
+	if len(recipients) == 0 {
+		return errors.New("no recipients passed to migrate")
+	}
+
+	m := s.manifestCopy()
+	m.Encryption = enc
+	m.Recipients, err = slices.MapErr(recipients, service.CanonicalRecipient)
+	if err != nil {
+		return err
+	}
+
+	// recipients of scopes belong to current encryption, so each scope gets recipients for new one
+	for _, scope := range scopes {
+		if m.scopeIndex(normalizeScopePath(scope.Path)) < 0 {
+			return errors.Errorf("no recipients scope for %s", scope.Path)
+		}
+	}
+
+	for i, scope := range m.Scopes {
+		scopeRecipients := findScopeRecipients(scopes, scope.Path)
+		if len(scopeRecipients) == 0 {
+			return errors.Errorf("no recipients passed to migrate scope %s", scope.Path)
+		}
+
+		m.Scopes[i].Recipients, err = slices.MapErr(scopeRecipients, service.CanonicalRecipient)
+		if err != nil {
+			return err
+		}
+	}
+
+	identities, err := s.identities(ctx)
+	if err != nil {
+		return err
+	}
+
+	const root = ""
+	tree, err := s.list(ctx, root)
+	if err != nil {
+		return err
+	}
+
+	inlinedTree := tree.Inline()
+
+	p := progress.FromCtx(ctx).Alter(
+		progress.WithMax(int64(len(inlinedTree.Keys()))),
+		progress.WithDescription("Migrating store encryption"),
+		progress.WithIts(),
+	)
+	defer p.Finish()
+
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(packWorkers)
+
+	for _, entryPath := range inlinedTree.Keys() {
+		eg.Go(func() error {
+			err2 := s.migrateSecret(egCtx, entryPath, identities, service, m.recipientsFor(entryPath))
+			if err2 != nil {
+				return errors.Wrapf(err2, "failed to migrate secret %s", entryPath)
+			}
+
+			p.Inc()
+
+			return nil
+		})
+	}
+
+	err = eg.Wait()
+	if err != nil {
+		return err
+	}
+
+	s.operations.add(migrateEncryptionOperation(s.manifest.Encryption, enc))
+
+	s.manifest = m
+	s.encryption = service
+
+	return nil
+}
+
+func findScopeRecipients(scopes []RecipientsScope, p string) []encryption.Recipient {
+	var res []encryption.Recipient
+	for _, scope := range scopes {
+		if normalizeScopePath(scope.Path) == p {
+			res = append(res, scope.Recipients...)
+		}
+	}
+
+	return res
+}
+
+func (s *store) migrateSecret(
+	ctx context.Context,
+	entryPath string,
+	identities []encryption.Identity,
+	service encryption.Service,
+	recipients []encryption.Recipient,
+) error {
+	data, err := s.storage.Get(ctx, entryPath)
+	if err != nil {
+		return err
+	}
+
+	if !maybe.Valid(data) {
+		return errors.New("secret not found")
+	}
+
+	secret, err := s.secretSerializer.Deserialize(maybe.Just(data))
+	if err != nil {
+		return errors.Wrap(err, "failed to deserialize secret")
+	}
+
+	err = secret.encrypt(func(v []byte) ([]byte, error) {
+		decrypted, err2 := s.encryption.Decrypt(v, identities)
+		if err2 != nil {
+			return nil, err2
+		}
+
+		return service.Encrypt(decrypted, recipients)
+	})
+	if err != nil {
+		return err
+	}
+
+	secretBytes, err := s.secretSerializer.Serialize(secret)
+	if err != nil {
+		return err
+	}
+
+	return s.storage.Store(ctx, entryPath, secretBytes)
+}
@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/UsingCoding/fpgo/pkg/slices"
+
 	"github.com/UsingCoding/gostore/internal/common/maybe"
+	"github.com/UsingCoding/gostore/internal/gostore/app/encryption"
 )
 
 type operations []string
@@ -33,6 +36,10 @@ func addOperation(path string, key maybe.Maybe[string]) string {
 	return fmt.Sprintf(txt, args...)
 }
 
+func importOperation(path string, keys int) string {
+	return fmt.Sprintf("Import %d keys to %s", keys, path)
+}
+
 func copyOperation(src, dst string) string {
 	txt := "Copy %s to %s"
 	args := []any{src, dst}
@@ -65,6 +72,47 @@ func removeEmptyOperation(path string) string {
 	return fmt.Sprintf(txt, args...)
 }
 
+func setMetadataOperation(path string) string {
+	return fmt.Sprintf("Update metadata of %s", path)
+}
+
+func addRecipientsOperation(scopePath maybe.Maybe[string], recipients []encryption.Recipient) string {
+	if p, ok := maybe.JustValid(scopePath); ok {
+		return fmt.Sprintf("Add recipients %s to %s", joinRecipients(recipients), p)
+	}
+	return fmt.Sprintf("Add recipients %s", joinRecipients(recipients))
+}
+
+func removeRecipientsOperation(scopePath maybe.Maybe[string], recipients []encryption.Recipient) string {
+	if p, ok := maybe.JustValid(scopePath); ok {
+		return fmt.Sprintf("Remove recipients %s from %s", joinRecipients(recipients), p)
+	}
+	return fmt.Sprintf("Remove recipients %s", joinRecipients(recipients))
+}
+
+func migrateEncryptionOperation(from, to encryption.Encryption) string {
+	return fmt.Sprintf("Migrate encryption from %s to %s", from, to)
+}
+
+func joinRecipients(recipients []encryption.Recipient) string {
+	return strings.Join(slices.Map(recipients, encryption.Recipient.String), ", ")
+}
+
+func restoreOperation(path string, point HistoryPoint) string {
+	txt := "Restore %s to %s"
+	args := []any{path, point}
+
+	return fmt.Sprintf(txt, args...)
+}
+
 func packOperation() string {
 	return "Pack store"
 }
+
+func mergeOperation(revision string) string {
+	return fmt.Sprintf("Merge remote revision %s", revision)
+}
+
+func setPasswordPolicyOperation() string {
+	return "Update password policy"
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	stderrors "errors"
+	"time"
 
 	"github.com/pkg/errors"
 	"golang.org/x/sync/errgroup"
@@ -166,17 +167,18 @@ func (s *store) packSecret(ctx context.Context, entryPath string, skipChangesChe
 	}
 
 	if maybe.Valid(latest) {
-		secret, err = s.mergeWithLatest(ctx, secret, maybe.Just(latest))
+		secret, err = s.mergeWithLatest(ctx, entryPath, secret, maybe.Just(latest))
 		if err != nil {
 			return errors.Wrapf(err, "failed to merge secret %s", entryPath)
 		}
 	} else {
 		err = secret.encrypt(func(data []byte) ([]byte, error) {
-			return s.encryption.Encrypt(data, s.manifest.Recipients)
+			return s.encryption.Encrypt(data, s.manifest.recipientsFor(entryPath))
 		})
 		if err != nil {
 			return errors.Wrapf(err, "failed to encrypt secret %s", entryPath)
 		}
+		secret.Metadata.touch(s.authorProvider.Author(ctx), time.Now())
 	}
 
 	secretBytes, err := s.secretSerializer.Serialize(secret)
@@ -187,16 +189,21 @@ func (s *store) packSecret(ctx context.Context, entryPath string, skipChangesChe
 	return s.storage.Store(ctx, entryPath, secretBytes)
 }
 
-func (s *store) mergeWithLatest(ctx context.Context, secret Secret, latestData []byte) (Secret, error) {
+func (s *store) mergeWithLatest(ctx context.Context, entryPath string, secret Secret, latestData []byte) (Secret, error) {
 	latest, err := s.secretSerializer.Deserialize(latestData)
 	if err != nil {
 		return Secret{}, errors.Wrap(err, "failed to deserialize latest secret")
 	}
 
+	// unpacked secret has no metadata, so keep latest one
+	secret.Metadata = latest.Metadata
+	changed := len(secret.Payload) != len(latest.Payload)
+
 	err = secret.iterate(func(k string, v []byte) error {
 		latestEncryptedV, ok := maybe.JustValid(latest.getByKey(k))
 		if !ok {
 			// new key in secret, nothing to compare
+			changed = true
 			return nil
 		}
 
@@ -213,7 +220,8 @@ func (s *store) mergeWithLatest(ctx context.Context, secret Secret, latestData [
 		}
 
 		// key value is updated
-		encryptedV, err2 := s.encrypt(v)
+		changed = true
+		encryptedV, err2 := s.encrypt(entryPath, v)
 		if err2 != nil {
 			return err2
 		}
@@ -222,8 +230,15 @@ func (s *store) mergeWithLatest(ctx context.Context, secret Secret, latestData [
 
 		return nil
 	})
+	if err != nil {
+		return Secret{}, err
+	}
+
+	if changed {
+		secret.Metadata.touch(s.authorProvider.Author(ctx), time.Now())
+	}
 
-	return secret, err
+	return secret, nil
 }
 
 func (s *store) assertPacked() error {
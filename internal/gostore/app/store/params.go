@@ -1,6 +1,8 @@
 package store
 
 import (
+	"time"
+
 	"github.com/UsingCoding/gostore/internal/common/maybe"
 	"github.com/UsingCoding/gostore/internal/gostore/app/encryption"
 	"github.com/UsingCoding/gostore/internal/gostore/app/storage"
@@ -60,6 +62,13 @@ type AddParams struct {
 	Data []byte
 }
 
+type ImportParams struct {
+	Path    string
+	Payload map[string][]byte
+	// Replace removes keys of secret missing in payload
+	Replace bool
+}
+
 type CopyParams struct {
 	Src string
 	Dst string
@@ -72,12 +81,62 @@ type MoveParams struct {
 
 type GetParams struct {
 	SecretIndex
+
+	// At points to secret state in history, latest state used by default
+	At maybe.Maybe[HistoryPoint]
+}
+
+// HistoryPoint is one of storage revision or moment of time
+type HistoryPoint struct {
+	Revision maybe.Maybe[string]
+	Time     maybe.Maybe[time.Time]
+}
+
+func (p HistoryPoint) String() string {
+	if r, ok := maybe.JustValid(p.Revision); ok {
+		return r
+	}
+	if t, ok := maybe.JustValid(p.Time); ok {
+		return t.Format(time.RFC3339)
+	}
+	return ""
+}
+
+type HistoryParams struct {
+	Path string
+}
+
+type RestoreParams struct {
+	Path string
+	To   HistoryPoint
 }
 
 type ListParams struct {
 	Path string
 }
 
+type MetadataParams struct {
+	Path string
+	// At points to secret state in history, latest state used by default
+	At maybe.Maybe[HistoryPoint]
+}
+
+type SetMetadataParams struct {
+	Path string
+	// Tags replace existing ones when passed
+	Tags maybe.Maybe[[]string]
+	// Description replaces existing one when passed, empty removes it
+	Description maybe.Maybe[string]
+}
+
+type FindParams struct {
+	// Path of subtree to search in, whole store by default
+	Path    string
+	Matcher Matcher
+	// Grep enables search in decrypted values
+	Grep bool
+}
+
 type RemoveParams struct {
 	Path string
 	Key  maybe.Maybe[string]
@@ -86,3 +145,39 @@ type RemoveParams struct {
 type PackParams struct {
 	SkipChangesCheck bool // all files in storage will be tracked, since encryption may add timestamp when encrypts files
 }
+
+type AddRecipientsParams struct {
+	Recipients []encryption.Recipient
+	// Path of subtree to add recipients to scope of, store recipients changed when not passed
+	Path maybe.Maybe[string]
+}
+
+type RemoveRecipientsParams struct {
+	Recipients []encryption.Recipient
+	// Path of subtree scope, store recipients changed when not passed
+	Path maybe.Maybe[string]
+}
+
+type MigrateEncryptionParams struct {
+	Encryption encryption.Encryption
+	// Recipients of store after migration
+	Recipients []encryption.Recipient
+	// Scopes with recipients for new encryption, required for every existing recipients scope
+	Scopes []RecipientsScope
+}
+
+type DiffParams struct {
+	// From revision, latest committed revision by default
+	From maybe.Maybe[string]
+	// To revision, current store state by default
+	To maybe.Maybe[string]
+
+	Path string
+	// ShowValues decrypts values of changed keys into diff
+	ShowValues bool
+}
+
+type SyncParams struct {
+	// ConflictResolver used to resolve conflicting changes. Sync fails on conflicts without it
+	ConflictResolver maybe.Maybe[ConflictResolver]
+}
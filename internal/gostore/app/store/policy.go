@@ -0,0 +1,73 @@
+package store
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/UsingCoding/gostore/internal/common/maybe"
+)
+
+// CharClass is class of characters used in generated passwords
+type CharClass string
+
+const (
+	LowerClass  CharClass = "lower"
+	UpperClass  CharClass = "upper"
+	DigitClass  CharClass = "digits"
+	SymbolClass CharClass = "symbols"
+)
+
+var CharClasses = []CharClass{LowerClass, UpperClass, DigitClass, SymbolClass}
+
+// PasswordPolicy configures passwords generated for store, zero values mean defaults
+type PasswordPolicy struct {
+	Length maybe.Maybe[int]
+	// Words count in diceware passphrase
+	Words maybe.Maybe[int]
+	// Required classes each generated password contains, password built from all classes when empty
+	Required []CharClass
+	// ExcludeAmbiguous excludes characters looking alike such as 0/O and 1/l/I
+	ExcludeAmbiguous bool
+}
+
+func (p PasswordPolicy) validate() error {
+	if l, ok := maybe.JustValid(p.Length); ok && l <= 0 {
+		return errors.Errorf("invalid password length %d", l)
+	}
+	if w, ok := maybe.JustValid(p.Words); ok && w <= 0 {
+		return errors.Errorf("invalid words count %d", w)
+	}
+	for _, c := range p.Required {
+		if !isCharClass(c) {
+			return errors.Errorf("unknown character class %s", c)
+		}
+	}
+	return nil
+}
+
+func (s *store) setPasswordPolicy(_ context.Context, policy PasswordPolicy) error {
+	err := s.assertPacked()
+	if err != nil {
+		return err
+	}
+
+	err = policy.validate()
+	if err != nil {
+		return err
+	}
+
+	s.manifest.PasswordPolicy = policy
+	s.operations.add(setPasswordPolicyOperation())
+
+	return nil
+}
+
+func isCharClass(c CharClass) bool {
+	for _, class := range CharClasses {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
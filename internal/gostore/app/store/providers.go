@@ -5,6 +5,7 @@ import (
 
 	"github.com/UsingCoding/gostore/internal/common/maybe"
 	"github.com/UsingCoding/gostore/internal/gostore/app/encryption"
+	"github.com/UsingCoding/gostore/internal/gostore/app/storage"
 )
 
 type DataProvider interface {
@@ -14,4 +15,16 @@ type DataProvider interface {
 
 type IdentityProvider interface {
 	IdentityByRecipient(ctx context.Context, recipient encryption.Recipient) (maybe.Maybe[encryption.Identity], error)
+	// IdentityRecipients returns recipients of local identities without unlocking them
+	IdentityRecipients(ctx context.Context) ([]encryption.Recipient, error)
+}
+
+// RemoteAuthProvider provides credentials for remote of store at path
+type RemoteAuthProvider interface {
+	RemoteAuth(storePath string) storage.AuthProvider
+}
+
+// AuthorProvider returns author of store changes recorded in secrets metadata
+type AuthorProvider interface {
+	Author(ctx context.Context) string
 }
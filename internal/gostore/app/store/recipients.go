@@ -0,0 +1,340 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	stderrors "errors"
+	stdslices "slices"
+	"strings"
+
+	"github.com/UsingCoding/fpgo/pkg/slices"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/UsingCoding/gostore/internal/common/maybe"
+	"github.com/UsingCoding/gostore/internal/gostore/app/encryption"
+	"github.com/UsingCoding/gostore/internal/gostore/app/progress"
+)
+
+// ScopeAccess is recipients scope with flag whether local identities can decrypt its secrets
+type ScopeAccess struct {
+	RecipientsScope
+	Accessible bool
+}
+
+func (s *store) addRecipients(ctx context.Context, scopePath maybe.Maybe[string], recipients []encryption.Recipient) error {
+	err := s.assertPacked()
+	if err != nil {
+		return err
+	}
+
+	if len(recipients) == 0 {
+		return errors.New("no recipients passed to add")
+	}
+
+	recipients, err = slices.MapErr(recipients, s.encryption.CanonicalRecipient)
+	if err != nil {
+		return err
+	}
+
+	m := s.manifestCopy()
+	current := &m.Recipients
+	if p, ok := maybe.JustValid(scopePath); ok {
+		if p == "" {
+			return errors.New("empty scope path")
+		}
+
+		err = allowedPaths(p)
+		if err != nil {
+			return err
+		}
+
+		i := m.scopeIndex(p)
+		if i < 0 {
+			m.Scopes = append(m.Scopes, RecipientsScope{Path: p})
+			i = len(m.Scopes) - 1
+		}
+		current = &m.Scopes[i].Recipients
+	}
+
+	for _, r := range recipients {
+		if containsRecipient(*current, r) {
+			return errors.Errorf("recipient %s already exists in %s", r, scopeName(scopePath))
+		}
+		*current = append(*current, r)
+	}
+
+	err = s.reencrypt(ctx, m)
+	if err != nil {
+		return err
+	}
+
+	s.operations.add(addRecipientsOperation(scopePath, recipients))
+
+	return nil
+}
+
+func (s *store) removeRecipients(ctx context.Context, scopePath maybe.Maybe[string], recipients []encryption.Recipient) error {
+	err := s.assertPacked()
+	if err != nil {
+		return err
+	}
+
+	if len(recipients) == 0 {
+		return errors.New("no recipients passed to remove")
+	}
+
+	recipients, err = slices.MapErr(recipients, s.encryption.CanonicalRecipient)
+	if err != nil {
+		return err
+	}
+
+	m := s.manifestCopy()
+	current := &m.Recipients
+	scopeI := -1
+	if p, ok := maybe.JustValid(scopePath); ok {
+		scopeI = m.scopeIndex(p)
+		if scopeI < 0 {
+			return errors.Errorf("no recipients scope for %s", p)
+		}
+		current = &m.Scopes[scopeI].Recipients
+	}
+
+	for _, r := range recipients {
+		if !containsRecipient(*current, r) {
+			return errors.Errorf("recipient %s not found in %s", r, scopeName(scopePath))
+		}
+	}
+
+	*current = stdslices.DeleteFunc(*current, func(r encryption.Recipient) bool {
+		return containsRecipient(recipients, r)
+	})
+
+	if len(*current) == 0 {
+		if scopeI < 0 {
+			return errors.New("unable to remove all recipients from store")
+		}
+
+		// secrets of scope without recipients fall back to recipients of parent scope
+		m.Scopes = stdslices.Delete(m.Scopes, scopeI, scopeI+1)
+	}
+
+	err = s.reencrypt(ctx, m)
+	if err != nil {
+		return err
+	}
+
+	s.operations.add(removeRecipientsOperation(scopePath, recipients))
+
+	return nil
+}
+
+// scopes returns recipients scopes of store with access of local identities
+func (s *store) scopes(ctx context.Context) ([]ScopeAccess, error) {
+	local, err := s.identityProvider.IdentityRecipients(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get identities recipients")
+	}
+
+	return slices.Map(s.manifest.Scopes, func(scope RecipientsScope) ScopeAccess {
+		return ScopeAccess{
+			RecipientsScope: scope,
+			Accessible:      anyRecipient(local, scope.Recipients),
+		}
+	}), nil
+}
+
+// accessible reports for each path whether local identities are among its recipients, identities are not unlocked
+func (s *store) accessible(ctx context.Context, paths []string) (map[string]bool, error) {
+	local, err := s.identityProvider.IdentityRecipients(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get identities recipients")
+	}
+
+	res := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		res[p] = anyRecipient(local, s.manifest.recipientsFor(normalizeScopePath(p)))
+	}
+	return res, nil
+}
+
+// reencrypt decrypts values of secrets which recipients differ in passed manifest and encrypts them for new recipients.
+// On success store manifest replaced with passed one
+func (s *store) reencrypt(ctx context.Context, m Manifest) (err error) {
+	defer func() {
+		if err == nil {
+			return
+		}
+
+		// use background ctx to rollback changes os closed ctx
+		err = stderrors.Join(err, s.rollback(context.Background()))
+	}()
+
+	identities, err := s.identities(ctx)
+	if err != nil {
+		return err
+	}
+
+	const root = ""
+	tree, err := s.list(ctx, root)
+	if err != nil {
+		return err
+	}
+
+	// secrets of other scopes stay untouched, so they may be not accessible by local identities
+	changed := stdslices.DeleteFunc(tree.Inline().Keys(), func(p string) bool {
+		return sameRecipients(s.manifest.recipientsFor(p), m.recipientsFor(p))
+	})
+
+	p := progress.FromCtx(ctx).Alter(
+		progress.WithMax(int64(len(changed))),
+		progress.WithDescription("Re-encrypting store"),
+		progress.WithIts(),
+	)
+	defer p.Finish()
+
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(packWorkers)
+
+	for _, entryPath := range changed {
+		eg.Go(func() error {
+			err2 := s.reencryptSecret(egCtx, entryPath, identities, m.recipientsFor(entryPath))
+			if err2 != nil {
+				return errors.Wrapf(err2, "failed to re-encrypt secret %s", entryPath)
+			}
+
+			p.Inc()
+
+			return nil
+		})
+	}
+
+	err = eg.Wait()
+	if err != nil {
+		return err
+	}
+
+	s.manifest = m
+
+	return nil
+}
+
+// reencryptCrossed re-encrypts secrets copied or moved from src to dst when dst belongs to scope with other recipients
+func (s *store) reencryptCrossed(ctx context.Context, src, dst string, srcPaths []string) error {
+	var identities []encryption.Identity
+	for _, srcPath := range srcPaths {
+		dstPath := dst + strings.TrimPrefix(srcPath, src)
+
+		recipients := s.manifest.recipientsFor(dstPath)
+		if sameRecipients(s.manifest.recipientsFor(srcPath), recipients) {
+			continue
+		}
+
+		if identities == nil {
+			var err error
+			identities, err = s.identities(ctx)
+			if err != nil {
+				return err
+			}
+		}
+
+		err := s.reencryptSecret(ctx, dstPath, identities, recipients)
+		if err != nil {
+			return errors.Wrapf(err, "failed to re-encrypt secret %s", dstPath)
+		}
+	}
+
+	return nil
+}
+
+func (s *store) manifestCopy() Manifest {
+	m := s.manifest
+	m.Recipients = stdslices.Clone(m.Recipients)
+	m.Scopes = slices.Map(m.Scopes, func(scope RecipientsScope) RecipientsScope {
+		scope.Recipients = stdslices.Clone(scope.Recipients)
+		return scope
+	})
+	return m
+}
+
+func (s *store) reencryptSecret(
+	ctx context.Context,
+	entryPath string,
+	identities []encryption.Identity,
+	recipients []encryption.Recipient,
+) error {
+	data, err := s.storage.Get(ctx, entryPath)
+	if err != nil {
+		return err
+	}
+
+	if !maybe.Valid(data) {
+		return errors.New("secret not found")
+	}
+
+	secretBytes, err := s.reencryptSecretData(maybe.Just(data), identities, recipients)
+	if err != nil {
+		return err
+	}
+
+	return s.storage.Store(ctx, entryPath, secretBytes)
+}
+
+func (s *store) reencryptSecretData(
+	data []byte,
+	identities []encryption.Identity,
+	recipients []encryption.Recipient,
+) ([]byte, error) {
+	secret, err := s.secretSerializer.Deserialize(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to deserialize secret")
+	}
+
+	err = secret.encrypt(func(v []byte) ([]byte, error) {
+		decrypted, err2 := s.encryption.Decrypt(v, identities)
+		if err2 != nil {
+			return nil, err2
+		}
+
+		return s.encryption.Encrypt(decrypted, recipients)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.secretSerializer.Serialize(secret)
+}
+
+func sameRecipients(a, b []encryption.Recipient) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for _, r := range a {
+		if !containsRecipient(b, r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func scopeName(scopePath maybe.Maybe[string]) string {
+	if p, ok := maybe.JustValid(scopePath); ok {
+		return p
+	}
+	return "store"
+}
+
+func anyRecipient(recipients, candidates []encryption.Recipient) bool {
+	return stdslices.ContainsFunc(candidates, func(r encryption.Recipient) bool {
+		return containsRecipient(recipients, r)
+	})
+}
+
+func containsRecipient(recipients []encryption.Recipient, recipient encryption.Recipient) bool {
+	return stdslices.ContainsFunc(recipients, func(r encryption.Recipient) bool {
+		return bytes.Equal(r, recipient)
+	})
+}
@@ -16,6 +16,8 @@ const (
 type Secret struct {
 	// Payload is json object with encrypted values
 	Payload map[string][]byte
+	// Metadata stored unencrypted
+	Metadata Metadata
 }
 
 func (s *Secret) addData(key maybe.Maybe[string], data []byte) {
@@ -50,6 +52,17 @@ func (s *Secret) getAll(key maybe.Maybe[string]) []SecretData {
 		}}
 	}
 
+	return slices.Map(s.keys(), func(k string) SecretData {
+		return SecretData{
+			Name:    k,
+			Payload: s.Payload[k],
+			Default: k == defaultKey,
+		}
+	})
+}
+
+// keys returns sorted names of secret keys
+func (s *Secret) keys() []string {
 	keys := make([]string, 0, len(s.Payload))
 	for name := range s.Payload {
 		keys = append(keys, name)
@@ -57,13 +70,7 @@ func (s *Secret) getAll(key maybe.Maybe[string]) []SecretData {
 
 	stdslices.Sort(keys)
 
-	return slices.Map(keys, func(k string) SecretData {
-		return SecretData{
-			Name:    k,
-			Payload: s.Payload[k],
-			Default: k == defaultKey,
-		}
-	})
+	return keys
 }
 
 func (s *Secret) iterate(f func(k string, v []byte) error) error {
@@ -13,20 +13,57 @@ import (
 
 type Service interface {
 	Add(ctx context.Context, params AddParams) error
+	// Import adds several keys to secret in one change
+	Import(ctx context.Context, params ImportParams) error
 
 	Copy(ctx context.Context, params CopyParams) error
 	Move(ctx context.Context, params MoveParams) error
 
 	Get(ctx context.Context, params GetParams) ([]SecretData, error)
 	List(ctx context.Context, params ListParams) (storage.Tree, error)
+	// History returns revisions changed secret, newest first
+	History(ctx context.Context, params HistoryParams) ([]storage.Revision, error)
+	// Metadata returns metadata of secret if secret exists
+	Metadata(ctx context.Context, params MetadataParams) (maybe.Maybe[Metadata], error)
+	// ListMetadata returns metadata of secrets under path by their path relative to it
+	ListMetadata(ctx context.Context, params ListParams) (map[string]Metadata, error)
+	// SetMetadata updates tags and description of secret
+	SetMetadata(ctx context.Context, params SetMetadataParams) error
+	// Find searches secrets by path, key names and tags, and by decrypted values when grep requested
+	Find(ctx context.Context, params FindParams) ([]FindResult, error)
+	// ResolveRevision returns full revision ID if revision exists in store
+	ResolveRevision(ctx context.Context, revision string) (maybe.Maybe[string], error)
+	// Diff returns secrets with keys changed between revisions
+	Diff(ctx context.Context, params DiffParams) ([]SecretDiff, error)
 
 	Remove(ctx context.Context, params RemoveParams) error
+	// Restore secret to state at history point as new change
+	Restore(ctx context.Context, params RestoreParams) error
+
+	// Batch applies all changes made by f in one commit, nothing committed if f fails
+	Batch(ctx context.Context, f func(tx Tx) error) error
 
 	Unpack(ctx context.Context) error
 	Pack(ctx context.Context, params PackParams) error
 
-	Sync(ctx context.Context) error
+	// Sync merges remote changes key by key and pushes local ones
+	Sync(ctx context.Context, params SyncParams) error
 	Rollback(ctx context.Context) error
+
+	Recipients(ctx context.Context) ([]encryption.Recipient, error)
+	// PasswordPolicy returns policy of passwords generated in store
+	PasswordPolicy(ctx context.Context) (PasswordPolicy, error)
+	SetPasswordPolicy(ctx context.Context, policy PasswordPolicy) error
+	// Scopes returns recipients overrides for subtrees of store
+	Scopes(ctx context.Context) ([]ScopeAccess, error)
+	// Accessible reports for each path whether local identities are recipients of its secrets
+	Accessible(ctx context.Context, paths []string) (map[string]bool, error)
+	// AddRecipients to store or subtree scope and re-encrypt affected secrets for new recipients set
+	AddRecipients(ctx context.Context, params AddRecipientsParams) error
+	// RemoveRecipients from store or subtree scope and re-encrypt affected secrets for remaining recipients
+	RemoveRecipients(ctx context.Context, params RemoveRecipientsParams) error
+	// MigrateEncryption re-encrypts all secrets with other encryption in one change
+	MigrateEncryption(ctx context.Context, params MigrateEncryptionParams) error
 }
 
 func NewStoreService(
@@ -37,6 +74,8 @@ func NewStoreService(
 	secretSerializer SecretSerializer,
 	dataProvider DataProvider,
 	identityProvider IdentityProvider,
+	remoteAuthProvider RemoteAuthProvider,
+	authorProvider AuthorProvider,
 ) Service {
 	return &storeService{
 		storeID:            storeID,
@@ -46,6 +85,8 @@ func NewStoreService(
 		secretSerializer:   secretSerializer,
 		dataProvider:       dataProvider,
 		identityProvider:   identityProvider,
+		remoteAuthProvider: remoteAuthProvider,
+		authorProvider:     authorProvider,
 	}
 }
 
@@ -58,8 +99,10 @@ type storeService struct {
 	manifestSerializer ManifestSerializer
 	secretSerializer   SecretSerializer
 
-	dataProvider     DataProvider
-	identityProvider IdentityProvider
+	dataProvider       DataProvider
+	identityProvider   IdentityProvider
+	remoteAuthProvider RemoteAuthProvider
+	authorProvider     AuthorProvider
 }
 
 func (service *storeService) Add(ctx context.Context, params AddParams) (err error) {
@@ -80,6 +123,19 @@ func (service *storeService) Add(ctx context.Context, params AddParams) (err err
 	return err
 }
 
+func (service *storeService) Import(ctx context.Context, params ImportParams) (err error) {
+	s, err := service.loadStore(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to load store")
+	}
+	defer func() {
+		err = stderrors.Join(err, s.close())
+	}()
+
+	err = s.importData(ctx, params)
+	return err
+}
+
 func (service *storeService) Copy(ctx context.Context, params CopyParams) (err error) {
 	s, err := service.loadStore(ctx)
 	if err != nil {
@@ -112,7 +168,7 @@ func (service *storeService) Get(ctx context.Context, params GetParams) ([]Secre
 		return nil, errors.Wrap(err, "failed to load store")
 	}
 
-	return s.get(ctx, params.Path, params.Key)
+	return s.getAt(ctx, params.Path, params.Key, params.At)
 }
 
 func (service *storeService) List(ctx context.Context, params ListParams) (storage.Tree, error) {
@@ -124,6 +180,73 @@ func (service *storeService) List(ctx context.Context, params ListParams) (stora
 	return s.list(ctx, params.Path)
 }
 
+func (service *storeService) History(ctx context.Context, params HistoryParams) ([]storage.Revision, error) {
+	s, err := service.loadStore(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load store")
+	}
+
+	return s.history(ctx, params.Path)
+}
+
+func (service *storeService) Metadata(ctx context.Context, params MetadataParams) (maybe.Maybe[Metadata], error) {
+	s, err := service.loadStore(ctx)
+	if err != nil {
+		return maybe.Maybe[Metadata]{}, errors.Wrap(err, "failed to load store")
+	}
+
+	return s.metadata(ctx, params.Path, params.At)
+}
+
+func (service *storeService) ListMetadata(ctx context.Context, params ListParams) (map[string]Metadata, error) {
+	s, err := service.loadStore(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load store")
+	}
+
+	return s.listMetadata(ctx, params.Path)
+}
+
+func (service *storeService) SetMetadata(ctx context.Context, params SetMetadataParams) (err error) {
+	s, err := service.loadStore(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to load store")
+	}
+	defer func() {
+		err = stderrors.Join(err, s.close())
+	}()
+
+	err = s.setMetadata(ctx, params)
+	return err
+}
+
+func (service *storeService) Find(ctx context.Context, params FindParams) ([]FindResult, error) {
+	s, err := service.loadStore(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load store")
+	}
+
+	return s.find(ctx, params)
+}
+
+func (service *storeService) ResolveRevision(ctx context.Context, revision string) (maybe.Maybe[string], error) {
+	s, err := service.loadStore(ctx)
+	if err != nil {
+		return maybe.Maybe[string]{}, errors.Wrap(err, "failed to load store")
+	}
+
+	return s.resolveStorageRevision(ctx, revision)
+}
+
+func (service *storeService) Diff(ctx context.Context, params DiffParams) ([]SecretDiff, error) {
+	s, err := service.loadStore(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load store")
+	}
+
+	return s.diff(ctx, params)
+}
+
 func (service *storeService) Remove(ctx context.Context, params RemoveParams) (err error) {
 	s, err := service.loadStore(ctx)
 	if err != nil {
@@ -137,6 +260,19 @@ func (service *storeService) Remove(ctx context.Context, params RemoveParams) (e
 	return err
 }
 
+func (service *storeService) Restore(ctx context.Context, params RestoreParams) (err error) {
+	s, err := service.loadStore(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to load store")
+	}
+	defer func() {
+		err = stderrors.Join(err, s.close())
+	}()
+
+	err = s.restore(ctx, params.Path, params.To)
+	return err
+}
+
 func (service *storeService) Unpack(ctx context.Context) (err error) {
 	s, err := service.loadStore(ctx)
 	if err != nil {
@@ -171,13 +307,13 @@ func (service *storeService) Pack(ctx context.Context, params PackParams) (err e
 	return err
 }
 
-func (service *storeService) Sync(ctx context.Context) error {
+func (service *storeService) Sync(ctx context.Context, params SyncParams) error {
 	s, err := service.loadStore(ctx)
 	if err != nil {
 		return errors.Wrap(err, "failed to load store")
 	}
 
-	return s.sync(ctx)
+	return s.sync(ctx, params)
 }
 
 func (service *storeService) Rollback(ctx context.Context) error {
@@ -189,13 +325,118 @@ func (service *storeService) Rollback(ctx context.Context) error {
 	return s.rollback(ctx)
 }
 
+func (service *storeService) Recipients(ctx context.Context) ([]encryption.Recipient, error) {
+	s, err := service.loadStore(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load store")
+	}
+
+	return s.manifest.Recipients, nil
+}
+
+func (service *storeService) Scopes(ctx context.Context) ([]ScopeAccess, error) {
+	s, err := service.loadStore(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load store")
+	}
+
+	return s.scopes(ctx)
+}
+
+func (service *storeService) Accessible(ctx context.Context, paths []string) (map[string]bool, error) {
+	s, err := service.loadStore(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load store")
+	}
+
+	return s.accessible(ctx, paths)
+}
+
+func (service *storeService) PasswordPolicy(ctx context.Context) (PasswordPolicy, error) {
+	s, err := service.loadStore(ctx)
+	if err != nil {
+		return PasswordPolicy{}, errors.Wrap(err, "failed to load store")
+	}
+
+	return s.manifest.PasswordPolicy, nil
+}
+
+func (service *storeService) SetPasswordPolicy(ctx context.Context, policy PasswordPolicy) (err error) {
+	s, err := service.loadStore(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to load store")
+	}
+	defer func() {
+		err = stderrors.Join(err, s.close())
+	}()
+
+	err = s.setPasswordPolicy(ctx, policy)
+	if err == nil {
+		err = service.writeManifest(ctx, s.manifest, s.storage)
+	}
+	return err
+}
+
+func (service *storeService) AddRecipients(ctx context.Context, params AddRecipientsParams) (err error) {
+	s, err := service.loadStore(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to load store")
+	}
+	defer func() {
+		err = stderrors.Join(err, s.close())
+	}()
+
+	err = s.addRecipients(ctx, maybe.Map(params.Path, normalizeScopePath), params.Recipients)
+	if err == nil {
+		err = service.writeManifest(ctx, s.manifest, s.storage)
+	}
+	return err
+}
+
+func (service *storeService) RemoveRecipients(ctx context.Context, params RemoveRecipientsParams) (err error) {
+	s, err := service.loadStore(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to load store")
+	}
+	defer func() {
+		err = stderrors.Join(err, s.close())
+	}()
+
+	err = s.removeRecipients(ctx, maybe.Map(params.Path, normalizeScopePath), params.Recipients)
+	if err == nil {
+		err = service.writeManifest(ctx, s.manifest, s.storage)
+	}
+	return err
+}
+
+func (service *storeService) MigrateEncryption(ctx context.Context, params MigrateEncryptionParams) error {
+	s, err := service.loadStore(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to load store")
+	}
+
+	encryptService, err := service.encryptionManager.EncryptService(params.Encryption)
+	if err == nil {
+		err = s.migrateEncryption(ctx, params.Encryption, encryptService, params.Recipients, params.Scopes)
+	}
+	if err == nil {
+		err = service.writeManifest(ctx, s.manifest, s.storage)
+	}
+	if err != nil {
+		// use background ctx to rollback changes os closed ctx
+		return stderrors.Join(err, s.rollback(context.Background()))
+	}
+
+	return s.close()
+}
+
 func (service *storeService) loadStore(ctx context.Context) (*store, error) {
 	storePath, err := service.resolveStoreLocation(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	s, err := service.storageManager.Use(ctx, storePath)
+	s, err := service.storageManager.Use(ctx, storePath, service.remoteAuthProvider.RemoteAuth(storePath))
 	if err != nil {
 		return nil, err
 	}
@@ -220,11 +461,13 @@ func (service *storeService) loadStore(ctx context.Context) (*store, error) {
 	}
 
 	return &store{
-		manifest:         manifest,
-		storage:          s,
-		encryption:       encryptService,
-		secretSerializer: service.secretSerializer,
-		identityProvider: service.identityProvider,
+		manifest:           manifest,
+		storage:            s,
+		encryption:         encryptService,
+		secretSerializer:   service.secretSerializer,
+		manifestSerializer: service.manifestSerializer,
+		identityProvider:   service.identityProvider,
+		authorProvider:     service.authorProvider,
 	}, nil
 }
 
@@ -2,6 +2,8 @@ package store
 
 import (
 	"context"
+	stderrors "errors"
+	"time"
 
 	"github.com/UsingCoding/fpgo/pkg/slices"
 	"github.com/pkg/errors"
@@ -25,8 +27,10 @@ type store struct {
 	storage          storage.Storage
 	encryption       encryption.Service
 	identityProvider IdentityProvider
+	authorProvider   AuthorProvider
 
-	secretSerializer SecretSerializer
+	secretSerializer   SecretSerializer
+	manifestSerializer ManifestSerializer
 
 	operations operations
 }
@@ -37,22 +41,67 @@ func (s *store) add(
 	key maybe.Maybe[string],
 	data []byte,
 ) error {
-	err := s.assertPacked()
+	err := s.updateSecret(ctx, path, func(secret *Secret) error {
+		encryptedData, err := s.encrypt(path, data)
+		if err != nil {
+			return err
+		}
+
+		secret.addData(key, encryptedData)
+		return nil
+	})
 	if err != nil {
 		return err
 	}
 
-	err = allowedPaths(path)
+	s.operations.add(addOperation(path, key))
+
+	return nil
+}
+
+// importData adds keys to secret in one operation, replace removes keys missing in payload
+func (s *store) importData(ctx context.Context, params ImportParams) error {
+	if len(params.Payload) == 0 {
+		return errors.New("no keys to import")
+	}
+
+	err := s.updateSecret(ctx, params.Path, func(secret *Secret) error {
+		if params.Replace {
+			secret.Payload = map[string][]byte{}
+		}
+
+		for k, v := range params.Payload {
+			encryptedData, err := s.encrypt(params.Path, v)
+			if err != nil {
+				return err
+			}
+
+			secret.addData(maybe.NewJust(k), encryptedData)
+		}
+		return nil
+	})
 	if err != nil {
 		return err
 	}
 
-	existedSecret, err := s.storage.Get(ctx, path)
+	s.operations.add(importOperation(params.Path, len(params.Payload)))
+
+	return nil
+}
+
+// updateSecret applies update to existing or new secret and stores it
+func (s *store) updateSecret(ctx context.Context, path string, update func(secret *Secret) error) error {
+	err := s.assertPacked()
+	if err != nil {
+		return err
+	}
+
+	err = allowedPaths(path)
 	if err != nil {
 		return err
 	}
 
-	encryptedData, err := s.encrypt(data)
+	existedSecret, err := s.storage.Get(ctx, path)
 	if err != nil {
 		return err
 	}
@@ -67,21 +116,19 @@ func (s *store) add(
 		secret = initSecret()
 	}
 
-	secret.addData(key, encryptedData)
-
-	secretBytes, err := s.secretSerializer.Serialize(secret)
+	err = update(&secret)
 	if err != nil {
 		return err
 	}
 
-	err = s.storage.Store(ctx, path, secretBytes)
+	secret.Metadata.touch(s.authorProvider.Author(ctx), time.Now())
+
+	secretBytes, err := s.secretSerializer.Serialize(secret)
 	if err != nil {
 		return err
 	}
 
-	s.operations.add(addOperation(path, key))
-
-	return nil
+	return s.storage.Store(ctx, path, secretBytes)
 }
 
 func (s *store) copy(ctx context.Context, src, dst string) error {
@@ -95,11 +142,22 @@ func (s *store) copy(ctx context.Context, src, dst string) error {
 		return err
 	}
 
+	srcPaths, err := s.secretPaths(ctx, src, maybe.Maybe[string]{})
+	if err != nil {
+		return err
+	}
+
 	err = s.storage.Copy(ctx, src, dst)
 	if err != nil {
 		return err
 	}
 
+	err = s.reencryptCrossed(ctx, src, dst, srcPaths)
+	if err != nil {
+		// use background ctx to rollback changes os closed ctx
+		return stderrors.Join(err, s.rollback(context.Background()))
+	}
+
 	s.operations.add(copyOperation(src, dst))
 
 	return nil
@@ -116,17 +174,37 @@ func (s *store) move(ctx context.Context, src, dst string) error {
 		return err
 	}
 
+	srcPaths, err := s.secretPaths(ctx, src, maybe.Maybe[string]{})
+	if err != nil {
+		return err
+	}
+
 	err = s.storage.Move(ctx, src, dst)
 	if err != nil {
 		return err
 	}
 
+	err = s.reencryptCrossed(ctx, src, dst, srcPaths)
+	if err != nil {
+		// use background ctx to rollback changes os closed ctx
+		return stderrors.Join(err, s.rollback(context.Background()))
+	}
+
 	s.operations.add(moveOperation(src, dst))
 
 	return nil
 }
 
 func (s *store) get(ctx context.Context, path string, key maybe.Maybe[string]) ([]SecretData, error) {
+	return s.getAt(ctx, path, key, maybe.Maybe[HistoryPoint]{})
+}
+
+func (s *store) getAt(
+	ctx context.Context,
+	path string,
+	key maybe.Maybe[string],
+	at maybe.Maybe[HistoryPoint],
+) ([]SecretData, error) {
 	err := s.assertPacked()
 	if err != nil {
 		return nil, err
@@ -137,7 +215,12 @@ func (s *store) get(ctx context.Context, path string, key maybe.Maybe[string]) (
 		return nil, err
 	}
 
-	secretBytes, err := s.storage.Get(ctx, path)
+	var secretBytes maybe.Maybe[[]byte]
+	if point, ok := maybe.JustValid(at); ok {
+		secretBytes, err = s.historyData(ctx, path, point)
+	} else {
+		secretBytes, err = s.storage.Get(ctx, path)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -151,20 +234,9 @@ func (s *store) get(ctx context.Context, path string, key maybe.Maybe[string]) (
 		return nil, errors.Wrapf(err, "failed to deserialize secret at %s", path)
 	}
 
-	var availableIdentities []encryption.Identity
-	for _, recipient := range s.manifest.Recipients {
-		i, err2 := s.identityProvider.IdentityByRecipient(ctx, recipient)
-		if err2 != nil {
-			return nil, errors.Wrap(err2, "failed to get identity")
-		}
-
-		if maybe.Valid(i) {
-			availableIdentities = append(availableIdentities, maybe.Just(i))
-		}
-	}
-
-	if len(availableIdentities) == 0 {
-		return nil, errors.New("no available identities found")
+	_, err = s.identities(ctx)
+	if err != nil {
+		return nil, err
 	}
 
 	secretsData := secret.getAll(key)
@@ -231,6 +303,7 @@ func (s *store) remove(ctx context.Context, path string, key maybe.Maybe[string]
 	}
 
 	secret.remove(maybe.Just(key))
+	secret.Metadata.touch(s.authorProvider.Author(ctx), time.Now())
 
 	// if secret empty - remove from storage
 	if secret.empty() {
@@ -257,21 +330,6 @@ func (s *store) remove(ctx context.Context, path string, key maybe.Maybe[string]
 	return nil
 }
 
-func (s *store) sync(ctx context.Context) error {
-	err := s.assertPacked()
-	if err != nil {
-		return err
-	}
-
-	err = s.storage.Pull(ctx)
-	if err != nil {
-		return errors.Wrap(err, "failed to pull storage")
-	}
-
-	err = s.storage.Push(ctx)
-	return errors.Wrap(err, "failed to push storage")
-}
-
 func (s *store) close() error {
 	if s.operations.len() == 0 {
 		return nil
@@ -283,14 +341,25 @@ func (s *store) rollback(ctx context.Context) error {
 	return s.storage.Rollback(ctx)
 }
 
-func (s *store) encrypt(data []byte) ([]byte, error) {
-	encryptedData, err := s.encryption.Encrypt(data, s.manifest.Recipients)
+// encrypt data of secret at path for recipients of scope containing path
+func (s *store) encrypt(path string, data []byte) ([]byte, error) {
+	encryptedData, err := s.encryption.Encrypt(data, s.manifest.recipientsFor(path))
 	return encryptedData, errors.Wrap(err, "failed to encrypt data")
 }
 
 func (s *store) decrypt(ctx context.Context, data []byte) ([]byte, error) {
+	availableIdentities, err := s.identities(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.encryption.Decrypt(data, availableIdentities)
+}
+
+// identities returns local identities for store and scopes recipients
+func (s *store) identities(ctx context.Context) ([]encryption.Identity, error) {
 	var availableIdentities []encryption.Identity
-	for _, recipient := range s.manifest.Recipients {
+	for _, recipient := range s.manifest.allRecipients() {
 		i, err2 := s.identityProvider.IdentityByRecipient(ctx, recipient)
 		if err2 != nil {
 			return nil, errors.Wrap(err2, "failed to get identity")
@@ -305,7 +374,7 @@ func (s *store) decrypt(ctx context.Context, data []byte) ([]byte, error) {
 		return nil, errors.New("no available identities found")
 	}
 
-	return s.encryption.Decrypt(data, availableIdentities)
+	return availableIdentities, nil
 }
 
 // checks that path is not store internal object
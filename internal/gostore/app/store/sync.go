@@ -0,0 +1,631 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	stderrors "errors"
+	"fmt"
+	stdslices "slices"
+	"strings"
+
+	"github.com/UsingCoding/fpgo/pkg/slices"
+	"github.com/pkg/errors"
+
+	"github.com/UsingCoding/gostore/internal/common/maybe"
+	"github.com/UsingCoding/gostore/internal/gostore/app/encryption"
+)
+
+type Resolution string
+
+const (
+	ResolutionOurs   = Resolution("ours")
+	ResolutionTheirs = Resolution("theirs")
+)
+
+// Conflict describes path or key of secret changed differently locally and in remote
+type Conflict struct {
+	Path string
+	// Key of secret or manifest part. None for conflicts of whole object
+	Key maybe.Maybe[string]
+}
+
+func (c Conflict) String() string {
+	if k, ok := maybe.JustValid(c.Key); ok {
+		return fmt.Sprintf("%s:%s", c.Path, k)
+	}
+	return c.Path
+}
+
+// ConflictResolver chooses which side of conflict keep while merging remote changes
+type ConflictResolver interface {
+	Resolve(ctx context.Context, conflict Conflict) (Resolution, error)
+}
+
+func (s *store) sync(ctx context.Context, params SyncParams) error {
+	err := s.assertPacked()
+	if err != nil {
+		return err
+	}
+
+	remote, err := s.storage.Fetch(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch storage")
+	}
+
+	if r, ok := maybe.JustValid(remote); ok {
+		err = s.mergeRemote(ctx, r, params.ConflictResolver)
+		if err != nil {
+			return errors.Wrap(err, "failed to merge remote changes")
+		}
+	}
+
+	err = s.storage.Push(ctx)
+	return errors.Wrap(err, "failed to push storage")
+}
+
+func (s *store) mergeRemote(
+	ctx context.Context,
+	remote string,
+	resolver maybe.Maybe[ConflictResolver],
+) (err error) {
+	head, err := s.storage.ResolveRevision(ctx, headRevision)
+	if err != nil {
+		return err
+	}
+
+	h, ok := maybe.JustValid(head)
+	if !ok {
+		// nothing committed locally
+		return s.storage.FastForward(ctx, remote)
+	}
+
+	if h == remote {
+		return nil
+	}
+
+	base, err := s.storage.MergeBase(ctx, h, remote)
+	if err != nil {
+		return err
+	}
+
+	if b, ok2 := maybe.JustValid(base); ok2 {
+		switch b {
+		case remote:
+			// remote changes already merged
+			return nil
+		case h:
+			return s.storage.FastForward(ctx, remote)
+		}
+	}
+
+	defer func() {
+		if err == nil {
+			return
+		}
+
+		// use background ctx to rollback changes os closed ctx
+		err = stderrors.Join(err, s.rollback(context.Background()))
+	}()
+
+	m := merger{
+		store:    s,
+		base:     base,
+		ours:     h,
+		theirs:   remote,
+		resolver: resolver,
+	}
+
+	err = m.merge(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(m.conflicts) != 0 {
+		return errors.Errorf(
+			"conflicting changes in: %s",
+			strings.Join(slices.Map(m.conflicts, Conflict.String), ", "),
+		)
+	}
+
+	return s.storage.Merge(ctx, remote, mergeOperation(remote))
+}
+
+// merger applies remote changes to secrets key by key with three-way merge
+type merger struct {
+	store *store
+
+	base         maybe.Maybe[string]
+	ours, theirs string
+
+	resolver  maybe.Maybe[ConflictResolver]
+	conflicts []Conflict
+
+	// manifests on each side and merged one to resolve recipients of secrets
+	oursManifest, theirsManifest, manifest Manifest
+
+	identities []encryption.Identity
+}
+
+type mergeSide int
+
+const (
+	oursSide mergeSide = iota
+	theirsSide
+)
+
+func (m *merger) merge(ctx context.Context) error {
+	paths, err := m.paths(ctx)
+	if err != nil {
+		return err
+	}
+
+	// merge manifest first to encrypt merged values for resulting recipients
+	err = m.mergeManifest(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range paths {
+		if p == ManifestPath {
+			continue
+		}
+
+		if allowedPaths(p) != nil {
+			err = m.mergeObject(ctx, p)
+		} else {
+			err = m.mergeSecret(ctx, p)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to merge %s", p)
+		}
+	}
+
+	return nil
+}
+
+func (m *merger) paths(ctx context.Context) ([]string, error) {
+	revisions := []string{m.ours, m.theirs}
+	if b, ok := maybe.JustValid(m.base); ok {
+		revisions = append(revisions, b)
+	}
+
+	var paths []string
+	for _, r := range revisions {
+		tree, err := m.store.storage.ListAt(ctx, "", r)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, tree.Inline().Keys()...)
+	}
+
+	return uniqueSorted(paths), nil
+}
+
+// mergeManifest merges recipients and scopes as sets, so recipients added on both sides are kept,
+// rest of manifest merged as a whole
+func (m *merger) mergeManifest(ctx context.Context) error {
+	base, _, theirs, err := m.data(ctx, ManifestPath)
+	if err != nil {
+		return err
+	}
+
+	m.oursManifest = m.store.manifest
+
+	theirsManifest, err := m.store.manifestSerializer.Deserialize(maybe.Just(theirs))
+	if err != nil {
+		return errors.Wrap(err, "failed to deserialize remote manifest")
+	}
+	m.theirsManifest = theirsManifest
+
+	var baseManifest maybe.Maybe[Manifest]
+	if b, ok := maybe.JustValid(base); ok {
+		bm, err2 := m.store.manifestSerializer.Deserialize(b)
+		if err2 != nil {
+			return errors.Wrap(err2, "failed to deserialize base manifest")
+		}
+		baseManifest = maybe.NewJust(bm)
+	}
+
+	baseSettings, err := m.manifestSettings(baseManifest)
+	if err != nil {
+		return err
+	}
+	oursSettings, err := m.manifestSettings(maybe.NewJust(m.oursManifest))
+	if err != nil {
+		return err
+	}
+	theirsSettings, err := m.manifestSettings(maybe.NewJust(m.theirsManifest))
+	if err != nil {
+		return err
+	}
+
+	side, err := m.mergeData(ctx, Conflict{Path: ManifestPath}, baseSettings, oursSettings, theirsSettings)
+	if err != nil {
+		return err
+	}
+
+	merged := m.oursManifest
+	if side == theirsSide {
+		merged = m.theirsManifest
+	}
+
+	b := maybe.Just(baseManifest)
+	merged.Recipients = mergeRecipients(b.Recipients, m.oursManifest.Recipients, m.theirsManifest.Recipients)
+	merged.Scopes, err = m.mergeScopes(ctx, b.Scopes, m.oursManifest.Scopes, m.theirsManifest.Scopes)
+	if err != nil {
+		return err
+	}
+
+	m.manifest = merged
+	m.store.manifest = merged
+
+	data, err := m.store.manifestSerializer.Serialize(merged)
+	if err != nil {
+		return err
+	}
+
+	return m.store.storage.Store(ctx, ManifestPath, data)
+}
+
+// manifestSettings serializes manifest without recipients and scopes
+func (m *merger) manifestSettings(manifest maybe.Maybe[Manifest]) (maybe.Maybe[[]byte], error) {
+	mf, ok := maybe.JustValid(manifest)
+	if !ok {
+		return maybe.Maybe[[]byte]{}, nil
+	}
+
+	mf.Recipients = nil
+	mf.Scopes = nil
+
+	data, err := m.store.manifestSerializer.Serialize(mf)
+	if err != nil {
+		return maybe.Maybe[[]byte]{}, err
+	}
+	return maybe.NewJust(data), nil
+}
+
+// mergeScopes merges recipients of scopes existing on both sides,
+// scope removed on one side is removed unless other side changed it
+func (m *merger) mergeScopes(ctx context.Context, base, ours, theirs []RecipientsScope) ([]RecipientsScope, error) {
+	find := func(scopes []RecipientsScope, p string) maybe.Maybe[RecipientsScope] {
+		for _, scope := range scopes {
+			if scope.Path == p {
+				return maybe.NewJust(scope)
+			}
+		}
+		return maybe.Maybe[RecipientsScope]{}
+	}
+
+	var paths []string
+	for _, scopes := range [][]RecipientsScope{ours, theirs} {
+		for _, scope := range scopes {
+			if !stdslices.Contains(paths, scope.Path) {
+				paths = append(paths, scope.Path)
+			}
+		}
+	}
+
+	var res []RecipientsScope
+	for _, p := range paths {
+		b, o, t := find(base, p), find(ours, p), find(theirs, p)
+
+		if maybe.Valid(o) && maybe.Valid(t) {
+			res = append(res, RecipientsScope{
+				Path:       p,
+				Recipients: mergeRecipients(maybe.Just(b).Recipients, maybe.Just(o).Recipients, maybe.Just(t).Recipients),
+			})
+			continue
+		}
+
+		kept := o
+		if !maybe.Valid(o) {
+			kept = t
+		}
+
+		switch {
+		case !maybe.Valid(b):
+			// scope added on one side
+			res = append(res, maybe.Just(kept))
+			continue
+		case sameRecipients(maybe.Just(kept).Recipients, maybe.Just(b).Recipients):
+			// scope removed on one side and not changed on other
+			continue
+		}
+
+		side, err := m.resolve(ctx, Conflict{Path: ManifestPath, Key: maybe.NewJust("scope " + p)})
+		if err != nil {
+			return nil, err
+		}
+
+		if (side == oursSide) == maybe.Valid(o) {
+			res = append(res, maybe.Just(kept))
+		}
+	}
+
+	return res, nil
+}
+
+// mergeObject merges store internal object as a whole
+func (m *merger) mergeObject(ctx context.Context, p string) error {
+	base, ours, theirs, err := m.data(ctx, p)
+	if err != nil {
+		return err
+	}
+
+	merged, err := m.mergeData(ctx, Conflict{Path: p}, base, ours, theirs)
+	if err != nil || merged == oursSide {
+		return err
+	}
+
+	return m.apply(ctx, p, ours, theirs)
+}
+
+func (m *merger) mergeSecret(ctx context.Context, p string) error {
+	base, ours, theirs, err := m.data(ctx, p)
+	if err != nil {
+		return err
+	}
+
+	if side, ok := threeWayMerge(base, ours, theirs); ok {
+		if side == theirsSide {
+			err = m.apply(ctx, p, ours, theirs)
+			if err != nil {
+				return err
+			}
+			return m.reencryptStale(ctx, p, side, theirs)
+		}
+		return m.reencryptStale(ctx, p, side, ours)
+	}
+
+	baseSecret, err := m.store.deserializeMaybe(base)
+	if err != nil {
+		return err
+	}
+	oursSecret, err := m.store.deserializeMaybe(ours)
+	if err != nil {
+		return err
+	}
+	theirsSecret, err := m.store.deserializeMaybe(theirs)
+	if err != nil {
+		return err
+	}
+
+	merged := initSecret()
+	merged.Metadata = mergeMetadata(oursSecret.Metadata, theirsSecret.Metadata)
+	for _, k := range unionKeys(baseSecret, oursSecret, theirsSecret) {
+		oursValue := oursSecret.getByKey(k)
+		theirsValue := theirsSecret.getByKey(k)
+
+		side, err2 := m.mergeData(
+			ctx,
+			Conflict{Path: p, Key: maybe.NewJust(k)},
+			baseSecret.getByKey(k),
+			oursValue,
+			theirsValue,
+		)
+		if err2 != nil {
+			return err2
+		}
+
+		v := oursValue
+		if side == theirsSide {
+			v = theirsValue
+		}
+
+		if !maybe.Valid(v) {
+			// key removed
+			continue
+		}
+
+		data, err2 := m.reencryptValue(ctx, p, maybe.Just(v), side)
+		if err2 != nil {
+			return err2
+		}
+		merged.Payload[k] = data
+	}
+
+	if merged.empty() {
+		if !maybe.Valid(ours) {
+			return nil
+		}
+		return m.store.storage.Remove(ctx, p)
+	}
+
+	data, err := m.store.secretSerializer.Serialize(merged)
+	if err != nil {
+		return err
+	}
+
+	return m.store.storage.Store(ctx, p, data)
+}
+
+// mergeData returns side which changes should be kept.
+// Values compared as is and then decrypted since same value differs after encryption
+func (m *merger) mergeData(
+	ctx context.Context,
+	conflict Conflict,
+	base, ours, theirs maybe.Maybe[[]byte],
+) (mergeSide, error) {
+	if side, ok := threeWayMerge(base, ours, theirs); ok {
+		return side, nil
+	}
+
+	if maybe.Valid(conflict.Key) {
+		var err error
+		base, ours, theirs, err = m.decrypt(ctx, base, ours, theirs)
+		if err != nil {
+			return 0, err
+		}
+
+		if side, ok := threeWayMerge(base, ours, theirs); ok {
+			return side, nil
+		}
+	}
+
+	return m.resolve(ctx, conflict)
+}
+
+// resolve asks resolver which side keep, without resolver conflict recorded and ours side kept
+func (m *merger) resolve(ctx context.Context, conflict Conflict) (mergeSide, error) {
+	r, ok := maybe.JustValid(m.resolver)
+	if !ok {
+		m.conflicts = append(m.conflicts, conflict)
+		return oursSide, nil
+	}
+
+	resolution, err := r.Resolve(ctx, conflict)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to resolve conflict in %s", conflict)
+	}
+
+	switch resolution {
+	case ResolutionOurs:
+		return oursSide, nil
+	case ResolutionTheirs:
+		return theirsSide, nil
+	default:
+		return 0, errors.Errorf("unknown conflict resolution %s", resolution)
+	}
+}
+
+func (m *merger) decrypt(ctx context.Context, values ...maybe.Maybe[[]byte]) (base, ours, theirs maybe.Maybe[[]byte], err error) {
+	err = m.ensureIdentities(ctx)
+	if err != nil {
+		return
+	}
+
+	decrypted := make([]maybe.Maybe[[]byte], 0, len(values))
+	for _, v := range values {
+		if data, ok := maybe.JustValid(v); ok {
+			data, err = m.store.encryption.Decrypt(data, m.identities)
+			if err != nil {
+				return
+			}
+			v = maybe.NewJust(data)
+		}
+		decrypted = append(decrypted, v)
+	}
+
+	return decrypted[0], decrypted[1], decrypted[2], nil
+}
+
+// reencryptStale re-encrypts secret taken from side which recipients differ from merged ones
+func (m *merger) reencryptStale(ctx context.Context, p string, side mergeSide, data maybe.Maybe[[]byte]) error {
+	if !maybe.Valid(data) || !m.stale(p, side) {
+		return nil
+	}
+
+	err := m.ensureIdentities(ctx)
+	if err != nil {
+		return err
+	}
+
+	secretBytes, err := m.store.reencryptSecretData(maybe.Just(data), m.identities, m.manifest.recipientsFor(p))
+	if err != nil {
+		return err
+	}
+
+	return m.store.storage.Store(ctx, p, secretBytes)
+}
+
+func (m *merger) reencryptValue(ctx context.Context, p string, data []byte, side mergeSide) ([]byte, error) {
+	if !m.stale(p, side) {
+		return data, nil
+	}
+
+	err := m.ensureIdentities(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	decrypted, err := m.store.encryption.Decrypt(data, m.identities)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.store.encryption.Encrypt(decrypted, m.manifest.recipientsFor(p))
+}
+
+func (m *merger) stale(p string, side mergeSide) bool {
+	sideManifest := m.oursManifest
+	if side == theirsSide {
+		sideManifest = m.theirsManifest
+	}
+
+	return !sameRecipients(sideManifest.recipientsFor(p), m.manifest.recipientsFor(p))
+}
+
+func (m *merger) ensureIdentities(ctx context.Context) (err error) {
+	if m.identities != nil {
+		return nil
+	}
+
+	m.identities, err = m.store.identities(ctx)
+	return err
+}
+
+// apply replaces local object with remote one
+func (m *merger) apply(ctx context.Context, p string, ours, theirs maybe.Maybe[[]byte]) error {
+	if data, ok := maybe.JustValid(theirs); ok {
+		return m.store.storage.Store(ctx, p, data)
+	}
+
+	if !maybe.Valid(ours) {
+		return nil
+	}
+
+	return m.store.storage.Remove(ctx, p)
+}
+
+func (m *merger) data(ctx context.Context, p string) (base, ours, theirs maybe.Maybe[[]byte], err error) {
+	if b, ok := maybe.JustValid(m.base); ok {
+		base, err = m.store.storage.GetAt(ctx, p, b)
+		if err != nil {
+			return
+		}
+	}
+
+	ours, err = m.store.storage.GetAt(ctx, p, m.ours)
+	if err != nil {
+		return
+	}
+
+	theirs, err = m.store.storage.GetAt(ctx, p, m.theirs)
+	return
+}
+
+// mergeRecipients keeps recipients present on both sides and added on any side
+func mergeRecipients(base, ours, theirs []encryption.Recipient) []encryption.Recipient {
+	var res []encryption.Recipient
+	for _, r := range ours {
+		if containsRecipient(theirs, r) || !containsRecipient(base, r) {
+			res = append(res, r)
+		}
+	}
+	for _, r := range theirs {
+		if !containsRecipient(ours, r) && !containsRecipient(base, r) {
+			res = append(res, r)
+		}
+	}
+	return res
+}
+
+// threeWayMerge returns side to keep if changes not conflicting
+func threeWayMerge(base, ours, theirs maybe.Maybe[[]byte]) (mergeSide, bool) {
+	switch {
+	case equalData(ours, theirs), equalData(base, theirs):
+		return oursSide, true
+	case equalData(base, ours):
+		return theirsSide, true
+	default:
+		return 0, false
+	}
+}
+
+func equalData(a, b maybe.Maybe[[]byte]) bool {
+	if maybe.Valid(a) != maybe.Valid(b) {
+		return false
+	}
+
+	return bytes.Equal(maybe.Just(a), maybe.Just(b))
+}
@@ -17,6 +17,8 @@ type CloneParams struct {
 
 	StorageType storage.Type
 	Remote      string
+	// Auth to access remote, saved for store
+	Auth maybe.Maybe[config.StoreAuth]
 }
 
 func (s service) Clone(ctx context.Context, params CloneParams) error {
@@ -39,11 +41,16 @@ func (s service) Clone(ctx context.Context, params CloneParams) error {
 		storePath,
 		params.Remote,
 		params.StorageType,
+		s.authProvider.ForAuth(params.Auth),
 	)
 	if err != nil {
 		return errors.Wrap(err, "failed to clone repo")
 	}
 
 	err = s.configService.AddStore(ctx, config.StoreID(params.StoreID), storePath)
-	return err
+	if err != nil {
+		return err
+	}
+
+	return s.setStoreAuth(ctx, params.StoreID, params.Auth)
 }
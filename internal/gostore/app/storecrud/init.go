@@ -4,6 +4,7 @@ import (
 	"context"
 	"path"
 
+	fpslice "github.com/UsingCoding/fpgo/pkg/slices"
 	"github.com/pkg/errors"
 
 	"github.com/UsingCoding/gostore/internal/common/maybe"
@@ -24,6 +25,8 @@ type InitParams struct {
 	StorageType maybe.Maybe[storage.Type]
 	Encryption  maybe.Maybe[encryption.Encryption]
 	Remote      maybe.Maybe[string]
+	// Auth to access remote, saved for store
+	Auth maybe.Maybe[config.StoreAuth]
 }
 
 type InitRes struct {
@@ -50,10 +53,13 @@ func (s service) Init(ctx context.Context, params InitParams) (InitRes, error) {
 		StorePath: storePath,
 	}
 
-	if len(params.Recipients) == 0 {
-		const enc = encryption.AgeEncryption
+	// use age as default encryption
+	params.Encryption = maybe.NewJust(maybe.MapNone(params.Encryption, func() encryption.Encryption {
+		return encryption.AgeEncryption
+	}))
 
-		identity, err2 := s.encryptionManager.GenerateIdentity(enc)
+	if len(params.Recipients) == 0 {
+		identity, err2 := s.encryptionManager.GenerateIdentity(maybe.Just(params.Encryption))
 		if err2 != nil {
 			return InitRes{}, err2
 		}
@@ -77,6 +83,11 @@ func (s service) Init(ctx context.Context, params InitParams) (InitRes, error) {
 		return InitRes{}, err
 	}
 
+	err = s.setStoreAuth(ctx, params.StoreID, params.Auth)
+	if err != nil {
+		return InitRes{}, err
+	}
+
 	// write new identity to config
 	if i, ok := maybe.JustValid(res.Identity); ok {
 		err = s.configService.AddIdentity(ctx, i)
@@ -97,16 +108,24 @@ func (s service) writeStore(
 		// use git as default storage type
 		return storage.GITType
 	})
-	enc := maybe.MapNone(params.Encryption, func() encryption.Encryption {
-		// use age as default encryption
-		return encryption.AgeEncryption
-	})
+	enc := maybe.Just(params.Encryption)
+
+	encService, err := s.encryptionManager.EncryptService(enc)
+	if err != nil {
+		return err
+	}
+
+	recipients, err := fpslice.MapErr(params.Recipients, encService.CanonicalRecipient)
+	if err != nil {
+		return err
+	}
 
 	storeStorage, err := s.storageManager.Init(
 		ctx,
 		storePath,
 		params.Remote,
 		storageType,
+		s.authProvider.ForAuth(params.Auth),
 	)
 	if err != nil {
 		return errors.Wrap(err, "failed to initialize storage for store")
@@ -116,7 +135,7 @@ func (s service) writeStore(
 	m := store.Manifest{
 		StorageType: storageType,
 		Encryption:  enc,
-		Recipients:  params.Recipients,
+		Recipients:  recipients,
 	}
 	data, err := s.manifestSerializer.Serialize(m)
 	if err != nil {
@@ -136,6 +155,15 @@ func (s service) writeStore(
 	return nil
 }
 
+func (s service) setStoreAuth(ctx context.Context, storeID string, auth maybe.Maybe[config.StoreAuth]) error {
+	if !maybe.Valid(auth) {
+		return nil
+	}
+
+	err := s.configService.SetStoreAuth(ctx, config.StoreID(storeID), auth)
+	return errors.Wrapf(err, "failed to save auth for store %s", storeID)
+}
+
 func (s service) ensureStoreNotExists(ctx context.Context, storeID string) error {
 	stores, err := s.configService.ListStores(ctx)
 	if err != nil {
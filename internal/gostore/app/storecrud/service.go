@@ -5,6 +5,7 @@ import (
 
 	"github.com/UsingCoding/gostore/internal/gostore/app/config"
 	"github.com/UsingCoding/gostore/internal/gostore/app/encryption"
+	"github.com/UsingCoding/gostore/internal/gostore/app/remoteauth"
 	"github.com/UsingCoding/gostore/internal/gostore/app/storage"
 	"github.com/UsingCoding/gostore/internal/gostore/app/store"
 )
@@ -19,12 +20,14 @@ func NewService(
 	encryptionManager encryption.Manager,
 	storageManager storage.Manager,
 	manifestSerializer store.ManifestSerializer,
+	authProvider remoteauth.Provider,
 ) Service {
 	return &service{
 		configService:      configService,
 		encryptionManager:  encryptionManager,
 		storageManager:     storageManager,
 		manifestSerializer: manifestSerializer,
+		authProvider:       authProvider,
 	}
 }
 
@@ -34,4 +37,5 @@ type service struct {
 	encryptionManager  encryption.Manager
 	storageManager     storage.Manager
 	manifestSerializer store.ManifestSerializer
+	authProvider       remoteauth.Provider
 }
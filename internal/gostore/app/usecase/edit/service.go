@@ -11,6 +11,8 @@ import (
 
 type Service interface {
 	Edit(ctx context.Context, index store.SecretIndex) error
+	// EditWith opens editor with data instead of current value, data stored as is when editor closed without changes
+	EditWith(ctx context.Context, index store.SecretIndex, data []byte) error
 }
 
 func NewService(s store.Service, editor Editor) Service {
@@ -49,6 +51,21 @@ func (s *service) Edit(ctx context.Context, index store.SecretIndex) error {
 	})
 }
 
+func (s *service) EditWith(ctx context.Context, index store.SecretIndex, data []byte) error {
+	edited, err := s.editor.Edit(ctx, index.Path, data)
+	if errors.Is(err, ErrNoChangesMade) {
+		edited, err = data, nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.service.Add(ctx, store.AddParams{
+		SecretIndex: index,
+		Data:        edited,
+	})
+}
+
 func payloadFromData(data []store.SecretData, p string, key maybe.Maybe[string]) ([]byte, error) {
 	if maybe.Valid(key) {
 		for _, d := range data {
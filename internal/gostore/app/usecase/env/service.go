@@ -0,0 +1,144 @@
+package env
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/UsingCoding/gostore/internal/common/maybe"
+	"github.com/UsingCoding/gostore/internal/gostore/app/store"
+)
+
+type Service interface {
+	// Resolve returns environment variables with secret values
+	Resolve(ctx context.Context, params ResolveParams) ([]Var, error)
+}
+
+// Var is environment variable
+type Var struct {
+	Name  string
+	Value []byte
+}
+
+func (v Var) String() string {
+	return v.Name + "=" + string(v.Value)
+}
+
+type ResolveParams struct {
+	// Vars in form NAME=PATH[:KEY]
+	Vars []string
+	// From paths of secrets which keys exported as variables
+	From []string
+}
+
+func NewService(s store.Service) Service {
+	return &service{service: s}
+}
+
+type service struct {
+	service store.Service
+}
+
+func (s *service) Resolve(ctx context.Context, params ResolveParams) ([]Var, error) {
+	var res []Var
+
+	// secrets from env-from goes first to let explicit vars override them
+	for _, p := range params.From {
+		data, err := s.service.Get(ctx, store.GetParams{
+			SecretIndex: store.SecretIndex{Path: p},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(data) == 0 {
+			return nil, errors.Errorf("secret %s not found", p)
+		}
+
+		for _, d := range data {
+			name := d.Name
+			if d.Default {
+				// default value named by secret itself
+				name = path.Base(p)
+			}
+
+			res = append(res, Var{
+				Name:  VarName(name),
+				Value: d.Payload,
+			})
+		}
+	}
+
+	for _, v := range params.Vars {
+		name, ref, found := strings.Cut(v, "=")
+		if !found || name == "" || ref == "" {
+			return nil, errors.Errorf("invalid env %s, expected NAME=PATH[:KEY]", v)
+		}
+
+		index := parseRef(ref)
+
+		data, err := s.service.Get(ctx, store.GetParams{
+			SecretIndex: index,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := singleValue(data, index)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, Var{
+			Name:  name,
+			Value: value,
+		})
+	}
+
+	return res, nil
+}
+
+// VarName converts secret key to environment variable name
+func VarName(key string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, key)
+}
+
+func parseRef(ref string) store.SecretIndex {
+	p, key, found := strings.Cut(ref, ":")
+	if !found {
+		return store.SecretIndex{Path: p}
+	}
+
+	return store.SecretIndex{
+		Path: p,
+		Key:  maybe.NewJust(key),
+	}
+}
+
+func singleValue(data []store.SecretData, index store.SecretIndex) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, errors.Errorf("secret %s not found", index.Path)
+	}
+
+	if maybe.Valid(index.Key) {
+		return data[0].Payload, nil
+	}
+
+	for _, d := range data {
+		if d.Default {
+			return d.Payload, nil
+		}
+	}
+
+	return nil, errors.Errorf("secret %s has no default value, pass key as %s:KEY", index.Path, index.Path)
+}
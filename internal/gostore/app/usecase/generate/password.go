@@ -0,0 +1,129 @@
+package generate
+
+import (
+	"crypto/rand"
+	"math/big"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/UsingCoding/gostore/internal/common/maybe"
+	"github.com/UsingCoding/gostore/internal/gostore/app/store"
+)
+
+const (
+	DefaultLength = 24
+	DefaultWords  = 6
+
+	passphraseSeparator = "-"
+	ambiguousChars      = "0Oo1lI|`'\""
+)
+
+var classChars = map[store.CharClass]string{
+	store.LowerClass:  "abcdefghijklmnopqrstuvwxyz",
+	store.UpperClass:  "ABCDEFGHIJKLMNOPQRSTUVWXYZ",
+	store.DigitClass:  "0123456789",
+	store.SymbolClass: "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~",
+}
+
+// password generates password containing at least one character of each required class
+func password(policy store.PasswordPolicy, charset maybe.Maybe[string]) ([]byte, error) {
+	length := maybe.MapNone(policy.Length, func() int {
+		return DefaultLength
+	})
+
+	var (
+		pool     string
+		required []string
+	)
+	if c, ok := maybe.JustValid(charset); ok {
+		pool = c
+	} else {
+		for _, class := range store.CharClasses {
+			pool += classChars[class]
+		}
+		for _, class := range policy.Required {
+			required = append(required, filterChars(classChars[class], policy.ExcludeAmbiguous))
+		}
+	}
+
+	pool = filterChars(pool, policy.ExcludeAmbiguous)
+	if pool == "" {
+		return nil, errors.New("empty charset for password")
+	}
+
+	if length < len(required) {
+		return nil, errors.Errorf("password length %d less than required classes count %d", length, len(required))
+	}
+
+	res := make([]rune, 0, length)
+	for _, chars := range required {
+		c, err := randomChar(chars)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, c)
+	}
+
+	for len(res) < length {
+		c, err := randomChar(pool)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, c)
+	}
+
+	// move required characters to random positions
+	for i := len(res) - 1; i > 0; i-- {
+		j, err := randomInt(i + 1)
+		if err != nil {
+			return nil, err
+		}
+		res[i], res[j] = res[j], res[i]
+	}
+
+	return []byte(string(res)), nil
+}
+
+func passphrase(generator WordGenerator, words int) ([]byte, error) {
+	if words <= 0 {
+		return nil, errors.Errorf("invalid words count %d", words)
+	}
+
+	res, err := generator.Words(words)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate words")
+	}
+
+	return []byte(strings.Join(res, passphraseSeparator)), nil
+}
+
+func filterChars(chars string, excludeAmbiguous bool) string {
+	if !excludeAmbiguous {
+		return chars
+	}
+
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(ambiguousChars, r) {
+			return -1
+		}
+		return r
+	}, chars)
+}
+
+func randomChar(chars string) (rune, error) {
+	runes := []rune(chars)
+	i, err := randomInt(len(runes))
+	if err != nil {
+		return 0, err
+	}
+	return runes[i], nil
+}
+
+func randomInt(n int) (int, error) {
+	i, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to read random")
+	}
+	return int(i.Int64()), nil
+}
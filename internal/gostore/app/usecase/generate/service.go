@@ -0,0 +1,88 @@
+package generate
+
+import (
+	"context"
+
+	"github.com/UsingCoding/gostore/internal/common/maybe"
+	"github.com/UsingCoding/gostore/internal/gostore/app/store"
+)
+
+type Service interface {
+	// Password generates password or passphrase by params and store password policy
+	Password(ctx context.Context, params PasswordParams) ([]byte, error)
+	// Generate generates password and adds it to store
+	Generate(ctx context.Context, params GenerateParams) ([]byte, error)
+}
+
+// WordGenerator generates random words for passphrases
+type WordGenerator interface {
+	Words(n int) ([]string, error)
+}
+
+type PasswordParams struct {
+	// Length overrides store policy length
+	Length maybe.Maybe[int]
+	// Charset used instead of policy character classes
+	Charset maybe.Maybe[string]
+	// Words generates diceware passphrase with words count instead of password
+	Words maybe.Maybe[int]
+	// Passphrase generates diceware passphrase with policy words count
+	Passphrase bool
+}
+
+type GenerateParams struct {
+	store.SecretIndex
+	PasswordParams
+}
+
+func NewService(s store.Service, wordGenerator WordGenerator) Service {
+	return &service{
+		service:       s,
+		wordGenerator: wordGenerator,
+	}
+}
+
+type service struct {
+	service       store.Service
+	wordGenerator WordGenerator
+}
+
+func (s *service) Password(ctx context.Context, params PasswordParams) ([]byte, error) {
+	policy, err := s.service.PasswordPolicy(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if maybe.Valid(params.Words) || params.Passphrase {
+		words := maybe.MapNone(params.Words, func() int {
+			return maybe.MapNone(policy.Words, func() int {
+				return DefaultWords
+			})
+		})
+
+		return passphrase(s.wordGenerator, words)
+	}
+
+	if l, ok := maybe.JustValid(params.Length); ok {
+		policy.Length = maybe.NewJust(l)
+	}
+
+	return password(policy, params.Charset)
+}
+
+func (s *service) Generate(ctx context.Context, params GenerateParams) ([]byte, error) {
+	data, err := s.Password(ctx, params.PasswordParams)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.service.Add(ctx, store.AddParams{
+		SecretIndex: params.SecretIndex,
+		Data:        data,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
@@ -0,0 +1,128 @@
+package render
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"text/template"
+
+	"github.com/pkg/errors"
+
+	"github.com/UsingCoding/gostore/internal/common/maybe"
+	"github.com/UsingCoding/gostore/internal/gostore/app/store"
+	"github.com/UsingCoding/gostore/internal/gostore/app/usecase/totp"
+)
+
+type Service interface {
+	// Render executes template with functions resolving secrets and writes result only when whole template rendered
+	Render(ctx context.Context, params RenderParams) error
+}
+
+type RenderParams struct {
+	// Name of template used in errors
+	Name     string
+	Template []byte
+	Output   io.Writer
+}
+
+func NewService(s store.Service, totpService totp.Service) Service {
+	return &service{
+		service: s,
+		totp:    totpService,
+	}
+}
+
+type service struct {
+	service store.Service
+	totp    totp.Service
+}
+
+func (s *service) Render(ctx context.Context, params RenderParams) error {
+	r := renderer{
+		ctx:     ctx,
+		service: s,
+		secrets: map[store.SecretIndex]string{},
+	}
+
+	t, err := template.
+		New(params.Name).
+		Option("missingkey=error").
+		Funcs(template.FuncMap{
+			"secret": r.secret,
+			"totp":   r.passcode,
+		}).
+		Parse(string(params.Template))
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse template %s", params.Name)
+	}
+
+	// render to buffer to not leave partially rendered output on failure
+	var buf bytes.Buffer
+	err = t.Execute(&buf, nil)
+	if err != nil {
+		return errors.Wrapf(err, "failed to render template %s", params.Name)
+	}
+
+	_, err = params.Output.Write(buf.Bytes())
+	return errors.Wrap(err, "failed to write rendered template")
+}
+
+// renderer resolves template functions and caches secrets used several times
+type renderer struct {
+	ctx     context.Context
+	service *service
+	secrets map[store.SecretIndex]string
+}
+
+// secret returns value of secret key or default value when key omitted
+func (r renderer) secret(p string, key ...string) (string, error) {
+	if len(key) > 1 {
+		return "", errors.Errorf("too many keys passed for secret %s", p)
+	}
+
+	index := store.SecretIndex{Path: p}
+	if len(key) == 1 {
+		index.Key = maybe.NewJust(key[0])
+	}
+
+	if v, ok := r.secrets[index]; ok {
+		return v, nil
+	}
+
+	data, err := r.service.service.Get(r.ctx, store.GetParams{
+		SecretIndex: index,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if len(data) == 0 {
+		return "", errors.Errorf("secret %s not found", p)
+	}
+
+	var value maybe.Maybe[string]
+	for _, d := range data {
+		if maybe.Valid(index.Key) || d.Default {
+			value = maybe.NewJust(string(d.Payload))
+			break
+		}
+	}
+
+	v, ok := maybe.JustValid(value)
+	if !ok {
+		return "", errors.Errorf("secret %s has no default value, pass key", p)
+	}
+
+	r.secrets[index] = v
+	return v, nil
+}
+
+// passcode returns current passcode of TOTP issuer
+func (r renderer) passcode(name string) (string, error) {
+	pv, err := r.service.totp.PasscodeView(r.ctx, name)
+	if err != nil {
+		return "", err
+	}
+
+	return pv.GeneratePasscode()
+}
@@ -2,6 +2,7 @@ package totp
 
 import (
 	"context"
+	"strconv"
 
 	"github.com/pkg/errors"
 	"github.com/pquerna/otp"
@@ -20,6 +21,21 @@ const (
 	AlgorithmMD5    Algorithm = "MD5"
 )
 
+// Type of one time password
+type Type string
+
+const (
+	// TypeTOTP is time-based one time password
+	TypeTOTP Type = "totp"
+	// TypeHOTP is counter-based one time password
+	TypeHOTP Type = "hotp"
+)
+
+const (
+	DefaultPeriod = 30
+	DefaultDigits = 6
+)
+
 var (
 	alg = mapper.New(map[Algorithm]otp.Algorithm{
 		AlgorithmSHA1:   otp.AlgorithmSHA1,
@@ -33,36 +49,148 @@ type AddParams struct {
 	Name      string
 	Secret    []byte
 	Algorithm Algorithm
+	// Type of passcode, TypeTOTP if none
+	Type maybe.Maybe[Type]
+
+	Issuer  maybe.Maybe[string]
+	Account maybe.Maybe[string]
+	// Digits of passcode, DefaultDigits if none
+	Digits maybe.Maybe[int]
+	// Period of passcode in seconds, DefaultPeriod if none
+	Period maybe.Maybe[int64]
+	// Counter of HOTP issuer, 0 if none
+	Counter maybe.Maybe[uint64]
+}
+
+// ImportParams describes issuers imported in one change
+type ImportParams struct {
+	Issuers []AddParams
+	// Overwrite replaces existing issuers with same name, otherwise import fails on existing issuer
+	Overwrite bool
 }
 
 func (s service) AddIssuer(ctx context.Context, params AddParams) error {
+	keys, err := issuerKeys(params)
+	if err != nil {
+		return err
+	}
+
+	// all keys added in one change
+	return s.service.Batch(ctx, func(tx store.Tx) error {
+		return addIssuerKeys(ctx, tx, params.Name, keys)
+	})
+}
+
+func (s service) ImportIssuers(ctx context.Context, params ImportParams) error {
+	keys := make([][]issuerValue, 0, len(params.Issuers))
+	for _, i := range params.Issuers {
+		k, err := issuerKeys(i)
+		if err != nil {
+			return errors.Wrapf(err, "invalid issuer %s", i.Name)
+		}
+		keys = append(keys, k)
+	}
+
+	// all issuers imported in one change
+	return s.service.Batch(ctx, func(tx store.Tx) error {
+		for n, i := range params.Issuers {
+			index := makeTOTPIndex(store.SecretIndex{
+				Path: i.Name,
+			})
+
+			existing, err := tx.Get(ctx, index)
+			if err != nil {
+				return errors.Wrapf(err, "failed to check issuer %s", i.Name)
+			}
+
+			if len(existing) != 0 {
+				if !params.Overwrite {
+					return errors.Errorf("issuer %s already exists", i.Name)
+				}
+
+				// remove keys of previous issuer to not mix them with imported ones
+				err = tx.Remove(ctx, store.RemoveParams{
+					Path: index.Path,
+				})
+				if err != nil {
+					return errors.Wrapf(err, "failed to remove issuer %s", i.Name)
+				}
+			}
+
+			err = addIssuerKeys(ctx, tx, i.Name, keys[n])
+			if err != nil {
+				return errors.Wrapf(err, "failed to import %s", i.Name)
+			}
+		}
+
+		return nil
+	})
+}
+
+type issuerValue struct {
+	key  string
+	data maybe.Maybe[string]
+}
+
+// issuerKeys validates params and returns keys of issuer secret
+func issuerKeys(params AddParams) ([]issuerValue, error) {
 	_, ok := alg.L()[params.Algorithm]
 	if !ok {
-		return errors.Errorf("unknown algorithm: %s", params.Algorithm)
+		return nil, errors.Errorf("unknown algorithm: %s", params.Algorithm)
 	}
 
-	// store secret
-	err := s.service.Add(ctx, store.AddParams{
-		SecretIndex: makeTOTPIndex(store.SecretIndex{
-			Path: params.Name,
-			Key:  maybe.NewJust(secretKey),
-		}),
-		Data: params.Secret,
+	t := maybe.MapNone(params.Type, func() Type {
+		return TypeTOTP
 	})
-	if err != nil {
-		return errors.Wrap(err, "failed to add totp secret")
+	switch t {
+	case TypeTOTP:
+		if maybe.Valid(params.Counter) {
+			return nil, errors.New("counter supported only for hotp")
+		}
+	case TypeHOTP:
+		if maybe.Valid(params.Period) {
+			return nil, errors.New("period supported only for totp")
+		}
+		if !maybe.Valid(params.Counter) {
+			params.Counter = maybe.NewJust[uint64](0)
+		}
+	default:
+		return nil, errors.Errorf("unknown type: %s", t)
 	}
 
-	// store alg
-	err = s.service.Add(ctx, store.AddParams{
-		SecretIndex: makeTOTPIndex(store.SecretIndex{
-			Path: params.Name,
-			Key:  maybe.NewJust(algKey),
-		}),
-		Data: []byte(params.Algorithm),
-	})
-	if err != nil {
-		return errors.Wrap(err, "failed to add totp algorithm")
+	return []issuerValue{
+		{key: secretKey, data: maybe.NewJust(string(params.Secret))},
+		{key: algKey, data: maybe.NewJust(string(params.Algorithm))},
+		{key: typeKey, data: maybe.NewJust(string(t))},
+		{key: issuerKey, data: params.Issuer},
+		{key: accountKey, data: params.Account},
+		{key: digitsKey, data: maybe.Map(params.Digits, strconv.Itoa)},
+		{key: periodKey, data: maybe.Map(params.Period, func(p int64) string {
+			return strconv.FormatInt(p, 10)
+		})},
+		{key: counterKey, data: maybe.Map(params.Counter, func(c uint64) string {
+			return strconv.FormatUint(c, 10)
+		})},
+	}, nil
+}
+
+func addIssuerKeys(ctx context.Context, tx store.Tx, name string, keys []issuerValue) error {
+	for _, k := range keys {
+		data, ok := maybe.JustValid(k.data)
+		if !ok {
+			continue
+		}
+
+		err := tx.Add(ctx, store.AddParams{
+			SecretIndex: makeTOTPIndex(store.SecretIndex{
+				Path: name,
+				Key:  maybe.NewJust(k.key),
+			}),
+			Data: []byte(data),
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to add totp %s", k.key)
+		}
 	}
 
 	return nil
@@ -0,0 +1,115 @@
+package totp
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/pquerna/otp"
+
+	"github.com/UsingCoding/gostore/internal/common/maybe"
+	"github.com/UsingCoding/gostore/internal/common/slices"
+	"github.com/UsingCoding/gostore/internal/gostore/app/store"
+)
+
+// issuer is totp secret with its parameters stored in store
+type issuer struct {
+	secret    string
+	algorithm otp.Algorithm
+	otpType   Type
+	digits    otp.Digits
+	period    int64
+	counter   uint64
+
+	issuer  maybe.Maybe[string]
+	account maybe.Maybe[string]
+}
+
+func (s service) issuer(ctx context.Context, name string) (issuer, error) {
+	secretData, err := s.service.Get(ctx, store.GetParams{
+		SecretIndex: makeTOTPIndex(store.SecretIndex{
+			Path: name,
+		}),
+	})
+	if err != nil {
+		return issuer{}, err
+	}
+
+	return parseIssuer(secretData)
+}
+
+func parseIssuer(secretData []store.SecretData) (issuer, error) {
+	value := func(key string) maybe.Maybe[string] {
+		return maybe.Map(slices.Find(secretData, func(data store.SecretData) bool {
+			return data.Name == key
+		}), func(data store.SecretData) string {
+			return string(data.Payload)
+		})
+	}
+
+	secret, ok := maybe.JustValid(value(secretKey))
+	if !ok {
+		return issuer{}, errors.New("failed to find secret for totp")
+	}
+
+	algorithm, ok := maybe.JustValid(value(algKey))
+	if !ok {
+		return issuer{}, errors.New("failed to find algorithm for totp")
+	}
+
+	a, ok := alg.L()[Algorithm(algorithm)]
+	if !ok {
+		return issuer{}, errors.Errorf("unknown algorithm for totp %s", algorithm)
+	}
+
+	t := maybe.MapNone(maybe.Map(value(typeKey), func(t string) Type {
+		return Type(t)
+	}), func() Type {
+		// issuers added before types introduced are totp
+		return TypeTOTP
+	})
+	if t != TypeTOTP && t != TypeHOTP {
+		return issuer{}, errors.Errorf("unknown type for totp %s", t)
+	}
+
+	var (
+		counter uint64
+		err     error
+	)
+	if c, ok2 := maybe.JustValid(value(counterKey)); ok2 && t == TypeHOTP {
+		counter, err = strconv.ParseUint(c, 10, 64)
+		if err != nil {
+			return issuer{}, errors.Wrapf(err, "invalid counter for hotp %s", c)
+		}
+	}
+
+	digits := DefaultDigits
+	if d, ok2 := maybe.JustValid(value(digitsKey)); ok2 {
+		digits, err = strconv.Atoi(d)
+		if err != nil {
+			return issuer{}, errors.Wrapf(err, "invalid digits for totp %s", d)
+		}
+	}
+
+	period := int64(DefaultPeriod)
+	if p, ok2 := maybe.JustValid(value(periodKey)); ok2 {
+		period, err = strconv.ParseInt(p, 10, 64)
+		if err != nil {
+			return issuer{}, errors.Wrapf(err, "invalid period for totp %s", p)
+		}
+		if period <= 0 {
+			return issuer{}, errors.Errorf("invalid period for totp %s: must be positive", p)
+		}
+	}
+
+	return issuer{
+		secret:    secret,
+		algorithm: a,
+		otpType:   t,
+		digits:    otp.Digits(digits),
+		period:    period,
+		counter:   counter,
+		issuer:    value(issuerKey),
+		account:   value(accountKey),
+	}, nil
+}
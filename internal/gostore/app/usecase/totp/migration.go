@@ -0,0 +1,226 @@
+package totp
+
+import (
+	"encoding/base32"
+	"encoding/base64"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/UsingCoding/gostore/internal/common/maybe"
+)
+
+const (
+	migrationScheme = "otpauth-migration"
+)
+
+// Google Authenticator migration payload fields
+const (
+	migrationPayloadOTPParametersField = 1
+
+	migrationSecretField    = 1
+	migrationNameField      = 2
+	migrationIssuerField    = 3
+	migrationAlgorithmField = 4
+	migrationDigitsField    = 5
+	migrationTypeField      = 6
+	migrationCounterField   = 7
+)
+
+var (
+	migrationAlgorithms = map[uint64]Algorithm{
+		0: AlgorithmSHA1,
+		1: AlgorithmSHA1,
+		2: AlgorithmSHA256,
+		3: AlgorithmSHA512,
+		4: AlgorithmMD5,
+	}
+	migrationDigits = map[uint64]int{
+		0: DefaultDigits,
+		1: 6,
+		2: 8,
+	}
+	migrationTypes = map[uint64]Type{
+		0: TypeTOTP,
+		1: TypeHOTP,
+		2: TypeTOTP,
+	}
+)
+
+// ParseQRPayload parses text of QR code: otpauth uri or otpauth-migration uri with batch of accounts
+func ParseQRPayload(payload string) ([]AddParams, error) {
+	if !strings.HasPrefix(payload, migrationScheme+"://") {
+		params, err := ParseURI("", payload)
+		if err != nil {
+			return nil, err
+		}
+		return []AddParams{params}, nil
+	}
+
+	u, err := url.Parse(payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse migration uri")
+	}
+
+	// query decoding turns unescaped plus of base64 into space
+	data, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(u.Query().Get("data"), " ", "+"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode migration data")
+	}
+
+	var res []AddParams
+	err = consumeFields(data, func(num protowire.Number, t protowire.Type, v []byte, _ uint64) error {
+		if num != migrationPayloadOTPParametersField || t != protowire.BytesType {
+			return nil
+		}
+
+		params, err2 := parseMigrationParameters(v)
+		if err2 != nil {
+			return err2
+		}
+
+		res = append(res, params)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res) == 0 {
+		return nil, errors.New("no accounts in migration data")
+	}
+
+	return uniqueNames(res), nil
+}
+
+// uniqueNames names issuers with same name by issuer and account
+func uniqueNames(params []AddParams) []AddParams {
+	count := map[string]int{}
+	for _, p := range params {
+		count[p.Name]++
+	}
+
+	for i, p := range params {
+		iss, ok1 := maybe.JustValid(p.Issuer)
+		account, ok2 := maybe.JustValid(p.Account)
+		if count[p.Name] > 1 && ok1 && ok2 {
+			params[i].Name = path.Join(iss, account)
+		}
+	}
+
+	return params
+}
+
+func parseMigrationParameters(data []byte) (AddParams, error) {
+	var (
+		secret                    []byte
+		name, issuer              string
+		algorithm, digits, otpTyp uint64
+		counter                   uint64
+	)
+
+	err := consumeFields(data, func(num protowire.Number, _ protowire.Type, b []byte, v uint64) error {
+		switch num {
+		case migrationSecretField:
+			secret = b
+		case migrationNameField:
+			name = string(b)
+		case migrationIssuerField:
+			issuer = string(b)
+		case migrationAlgorithmField:
+			algorithm = v
+		case migrationDigitsField:
+			digits = v
+		case migrationTypeField:
+			otpTyp = v
+		case migrationCounterField:
+			counter = v
+		}
+		return nil
+	})
+	if err != nil {
+		return AddParams{}, err
+	}
+
+	if len(secret) == 0 {
+		return AddParams{}, errors.Errorf("no secret for account %s in migration data", name)
+	}
+
+	a, ok := migrationAlgorithms[algorithm]
+	if !ok {
+		return AddParams{}, errors.Errorf("unknown algorithm %d for account %s", algorithm, name)
+	}
+	d, ok := migrationDigits[digits]
+	if !ok {
+		return AddParams{}, errors.Errorf("unknown digits %d for account %s", digits, name)
+	}
+	t, ok := migrationTypes[otpTyp]
+	if !ok {
+		return AddParams{}, errors.Errorf("unknown type %d for account %s", otpTyp, name)
+	}
+
+	// name may be prefixed by issuer like in otpauth uri label
+	account := name
+	if i, a2, found := strings.Cut(name, ":"); found {
+		account = strings.TrimSpace(a2)
+		if issuer == "" {
+			issuer = i
+		}
+	}
+
+	params := AddParams{
+		Name:      issuer,
+		Secret:    []byte(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(secret)),
+		Algorithm: a,
+		Type:      maybe.NewJust(t),
+		Issuer:    maybe.MapZero(issuer),
+		Account:   maybe.MapZero(account),
+		Digits:    maybe.NewJust(d),
+	}
+	if params.Name == "" {
+		params.Name = account
+	}
+	if t == TypeHOTP {
+		params.Counter = maybe.NewJust(counter)
+	}
+
+	return params, nil
+}
+
+// consumeFields calls f for each protobuf field with bytes of length-delimited fields or value of varint fields
+func consumeFields(data []byte, f func(num protowire.Number, t protowire.Type, b []byte, v uint64) error) error {
+	for len(data) > 0 {
+		num, t, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return errors.Wrap(protowire.ParseError(n), "invalid migration data")
+		}
+		data = data[n:]
+
+		var (
+			b []byte
+			v uint64
+		)
+		switch t {
+		case protowire.BytesType:
+			b, n = protowire.ConsumeBytes(data)
+		case protowire.VarintType:
+			v, n = protowire.ConsumeVarint(data)
+		default:
+			n = protowire.ConsumeFieldValue(num, t, data)
+		}
+		if n < 0 {
+			return errors.Wrap(protowire.ParseError(n), "invalid migration data")
+		}
+		data = data[n:]
+
+		err := f(num, t, b, v)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
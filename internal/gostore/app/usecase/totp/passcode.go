@@ -2,14 +2,14 @@ package totp
 
 import (
 	"context"
+	"strconv"
 	"time"
 
 	"github.com/pkg/errors"
-	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/hotp"
 	"github.com/pquerna/otp/totp"
 
 	"github.com/UsingCoding/gostore/internal/common/maybe"
-	"github.com/UsingCoding/gostore/internal/common/slices"
 	"github.com/UsingCoding/gostore/internal/gostore/app/store"
 )
 
@@ -17,73 +17,99 @@ type PasscodeView struct {
 	GeneratePasscode func() (string, error)
 	LastCountdown    int64
 	Period           int64
+	// Counter used to generate HOTP passcode, none for TOTP
+	Counter maybe.Maybe[uint64]
 }
 
 type PasscodeGenerator func() (string, error)
 
-const (
-	period = 30
-)
-
 func (s service) PasscodeView(ctx context.Context, name string) (PasscodeView, error) {
 	timepoint := time.Now
 
-	generator, err := s.generator(ctx, name, timepoint)
+	i, err := s.issuer(ctx, name)
 	if err != nil {
 		return PasscodeView{}, err
 	}
 
+	if i.otpType == TypeHOTP {
+		return s.hotpPasscodeView(ctx, name)
+	}
+
 	// calculate estimated time
-	f1 := timepoint().Unix() % period
-	countdown := period - f1
+	f1 := timepoint().Unix() % i.period
+	countdown := i.period - f1
 
 	return PasscodeView{
-		GeneratePasscode: generator,
+		GeneratePasscode: generator(i, timepoint),
 		LastCountdown:    countdown,
-		Period:           period,
+		Period:           i.period,
 	}, nil
 }
 
-func (s service) generator(ctx context.Context, name string, timepoint func() time.Time) (PasscodeGenerator, error) {
-	secretData, err := s.service.Get(ctx, store.GetParams{
-		SecretIndex: makeTOTPIndex(store.SecretIndex{
+// hotpPasscodeView generates passcode for current counter and commits incremented counter in same batch,
+// so passcode never issued twice for same counter
+func (s service) hotpPasscodeView(ctx context.Context, name string) (PasscodeView, error) {
+	var (
+		code    string
+		counter uint64
+	)
+	err := s.service.Batch(ctx, func(tx store.Tx) error {
+		secretData, err2 := tx.Get(ctx, makeTOTPIndex(store.SecretIndex{
 			Path: name,
-		}),
+		}))
+		if err2 != nil {
+			return err2
+		}
+
+		i, err2 := parseIssuer(secretData)
+		if err2 != nil {
+			return err2
+		}
+
+		code, err2 = hotp.GenerateCodeCustom(
+			i.secret,
+			i.counter,
+			hotp.ValidateOpts{
+				Digits:    i.digits,
+				Algorithm: i.algorithm,
+			},
+		)
+		if err2 != nil {
+			return errors.Wrap(err2, "failed to generate hotp passcode")
+		}
+		counter = i.counter
+
+		err2 = tx.Add(ctx, store.AddParams{
+			SecretIndex: makeTOTPIndex(store.SecretIndex{
+				Path: name,
+				Key:  maybe.NewJust(counterKey),
+			}),
+			Data: []byte(strconv.FormatUint(i.counter+1, 10)),
+		})
+		return errors.Wrap(err2, "failed to increment hotp counter")
 	})
 	if err != nil {
-		return nil, err
-	}
-
-	secret, ok := maybe.JustValid(slices.Find(secretData, func(data store.SecretData) bool {
-		return data.Name == secretKey
-	}))
-	if !ok {
-		return nil, errors.New("failed to find secret for totp")
-	}
-
-	algorithm, ok := maybe.JustValid(slices.Find(secretData, func(data store.SecretData) bool {
-		return data.Name == algKey
-	}))
-	if !ok {
-		return nil, errors.New("failed to find algorithm for totp")
+		return PasscodeView{}, err
 	}
 
-	a, ok := alg.L()[Algorithm(algorithm.Payload)]
-	if !ok {
-		return nil, errors.Wrapf(err, "unknown algorithm for totp %s", algorithm.Payload)
-	}
+	return PasscodeView{
+		GeneratePasscode: func() (string, error) {
+			return code, nil
+		},
+		Counter: maybe.NewJust(counter),
+	}, nil
+}
 
-	res := PasscodeGenerator(func() (string, error) {
+func generator(i issuer, timepoint func() time.Time) PasscodeGenerator {
+	return func() (string, error) {
 		return totp.GenerateCodeCustom(
-			string(secret.Payload),
+			i.secret,
 			timepoint(),
 			totp.ValidateOpts{
-				Period:    period,
-				Digits:    otp.DigitsSix,
-				Algorithm: a,
+				Period:    uint(i.period),
+				Digits:    i.digits,
+				Algorithm: i.algorithm,
 			},
 		)
-	})
-
-	return res, nil
+	}
 }
@@ -9,7 +9,11 @@ import (
 
 type Service interface {
 	AddIssuer(ctx context.Context, params AddParams) error
+	// ImportIssuers adds all issuers in one change
+	ImportIssuers(ctx context.Context, params ImportParams) error
 	PasscodeView(ctx context.Context, name string) (PasscodeView, error)
+	// ExportURI returns otpauth uri of issuer
+	ExportURI(ctx context.Context, name string) (string, error)
 }
 
 func NewService(s store.Service) Service {
@@ -24,8 +28,14 @@ const (
 	totpPathPrefix = "totp"
 
 	// totp metadata keys
-	secretKey = "secret"
-	algKey    = "alg"
+	secretKey  = "secret"
+	algKey     = "alg"
+	typeKey    = "type"
+	issuerKey  = "issuer"
+	accountKey = "account"
+	digitsKey  = "digits"
+	periodKey  = "period"
+	counterKey = "counter"
 )
 
 func makeTOTPIndex(index store.SecretIndex) store.SecretIndex {
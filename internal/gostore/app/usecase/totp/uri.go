@@ -0,0 +1,133 @@
+package totp
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/pquerna/otp"
+
+	"github.com/UsingCoding/gostore/internal/common/maybe"
+)
+
+const (
+	uriScheme = "otpauth"
+)
+
+// ParseURI parses otpauth://totp/<issuer>:<account>?secret=... or otpauth://hotp/...&counter=... into params to add issuer.
+// Name defaults to issuer or account when empty
+func ParseURI(name, uri string) (AddParams, error) {
+	key, err := otp.NewKeyFromURL(uri)
+	if err != nil {
+		return AddParams{}, errors.Wrap(err, "failed to parse otpauth uri")
+	}
+
+	params := AddParams{
+		Type: maybe.NewJust(Type(key.Type())),
+	}
+
+	switch Type(key.Type()) {
+	case TypeTOTP:
+		params.Period = maybe.NewJust(int64(key.Period()))
+	case TypeHOTP:
+		params.Counter, err = uriCounter(uri)
+		if err != nil {
+			return AddParams{}, err
+		}
+	default:
+		return AddParams{}, errors.Errorf("unsupported otp type %s", key.Type())
+	}
+
+	if key.Secret() == "" {
+		return AddParams{}, errors.New("otpauth uri has no secret")
+	}
+
+	a, ok := alg.R()[key.Algorithm()]
+	if !ok {
+		return AddParams{}, errors.Errorf("unknown algorithm in otpauth uri: %s", key.Algorithm())
+	}
+
+	issuer := maybe.MapZero(key.Issuer())
+	account := maybe.MapZero(key.AccountName())
+
+	if name == "" {
+		name = maybe.MapNone(issuer, func() string {
+			return maybe.Just(account)
+		})
+	}
+	if name == "" {
+		return AddParams{}, errors.New("failed to derive name from otpauth uri without issuer and account")
+	}
+
+	params.Name = name
+	params.Secret = []byte(key.Secret())
+	params.Algorithm = a
+	params.Issuer = issuer
+	params.Account = account
+	params.Digits = maybe.NewJust(key.Digits().Length())
+
+	return params, nil
+}
+
+func uriCounter(uri string) (maybe.Maybe[uint64], error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return maybe.Maybe[uint64]{}, errors.Wrap(err, "failed to parse otpauth uri")
+	}
+
+	c := u.Query().Get("counter")
+	if c == "" {
+		return maybe.Maybe[uint64]{}, nil
+	}
+
+	counter, err := strconv.ParseUint(c, 10, 64)
+	if err != nil {
+		return maybe.Maybe[uint64]{}, errors.Wrapf(err, "invalid counter in otpauth uri %s", c)
+	}
+
+	return maybe.NewJust(counter), nil
+}
+
+func (s service) ExportURI(ctx context.Context, name string) (string, error) {
+	i, err := s.issuer(ctx, name)
+	if err != nil {
+		return "", err
+	}
+
+	a, ok := alg.R()[i.algorithm]
+	if !ok {
+		return "", errors.Errorf("unknown algorithm %s", i.algorithm)
+	}
+
+	label := maybe.MapNone(i.account, func() string {
+		return name
+	})
+	if iss, ok2 := maybe.JustValid(i.issuer); ok2 {
+		label = iss + ":" + label
+	}
+
+	v := url.Values{}
+	v.Set("secret", strings.ToUpper(i.secret))
+	if iss, ok2 := maybe.JustValid(i.issuer); ok2 {
+		v.Set("issuer", iss)
+	}
+	v.Set("algorithm", string(a))
+	v.Set("digits", strconv.Itoa(i.digits.Length()))
+	switch i.otpType {
+	case TypeHOTP:
+		v.Set("counter", strconv.FormatUint(i.counter, 10))
+	default:
+		v.Set("period", strconv.FormatInt(i.period, 10))
+	}
+
+	u := url.URL{
+		Scheme:   uriScheme,
+		Host:     string(i.otpType),
+		Path:     "/" + label,
+		RawQuery: v.Encode(),
+	}
+
+	return u.String(), nil
+}
@@ -0,0 +1,108 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"time"
+
+	"github.com/pkg/errors"
+
+	appagent "github.com/UsingCoding/gostore/internal/gostore/app/agent"
+	"github.com/UsingCoding/gostore/internal/gostore/app/encryption"
+)
+
+const (
+	dialTimeout = time.Second
+	ioTimeout   = 5 * time.Second
+)
+
+func NewClient(socketPath string) appagent.Client {
+	return &client{socketPath: socketPath}
+}
+
+type client struct {
+	socketPath string
+}
+
+func (c *client) HasIdentity(ctx context.Context, recipient encryption.Recipient) (bool, error) {
+	resp, err := c.do(ctx, request{
+		Op:        hasIdentityOp,
+		Recipient: recipient.String(),
+	})
+	return resp.Held, err
+}
+
+func (c *client) AddIdentity(ctx context.Context, i encryption.Identity) error {
+	_, err := c.do(ctx, request{
+		Op:       addIdentityOp,
+		Identity: toIdentity(i),
+	})
+	return err
+}
+
+func (c *client) Decrypt(
+	ctx context.Context,
+	enc encryption.Encryption,
+	data []byte,
+	recipients []encryption.Recipient,
+) ([]byte, error) {
+	rs := make([]string, 0, len(recipients))
+	for _, r := range recipients {
+		rs = append(rs, r.String())
+	}
+
+	resp, err := c.do(ctx, request{
+		Op:         decryptOp,
+		Encryption: string(enc),
+		Recipients: rs,
+		Data:       data,
+	})
+	return resp.Data, err
+}
+
+func (c *client) Clear(ctx context.Context) error {
+	_, err := c.do(ctx, request{
+		Op: clearOp,
+	})
+	return err
+}
+
+func (c *client) Stop(ctx context.Context) error {
+	_, err := c.do(ctx, request{
+		Op: stopOp,
+	})
+	return err
+}
+
+func (c *client) do(ctx context.Context, req request) (response, error) {
+	dialer := net.Dialer{Timeout: dialTimeout}
+	conn, err := dialer.DialContext(ctx, "unix", c.socketPath)
+	if err != nil {
+		// any dial error means there is no agent to talk with
+		return response{}, errors.WithStack(appagent.ErrAgentNotRunning)
+	}
+	defer conn.Close()
+
+	err = conn.SetDeadline(time.Now().Add(ioTimeout))
+	if err != nil {
+		return response{}, errors.WithStack(err)
+	}
+
+	err = json.NewEncoder(conn).Encode(req)
+	if err != nil {
+		return response{}, errors.Wrap(err, "failed to send request to agent")
+	}
+
+	var resp response
+	err = json.NewDecoder(conn).Decode(&resp)
+	if err != nil {
+		return response{}, errors.Wrap(err, "failed to read response from agent")
+	}
+
+	if resp.Error != "" {
+		return response{}, errors.Errorf("agent: %s", resp.Error)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,19 @@
+//go:build !windows
+
+package agent
+
+import (
+	"context"
+	"net"
+	"syscall"
+)
+
+// listenPrivate listens unix socket accessible only by owner.
+// Socket created with restrictive umask so there is no window when other users may connect to it
+func listenPrivate(ctx context.Context, socketPath string) (net.Listener, error) {
+	old := syscall.Umask(0o177)
+	defer syscall.Umask(old)
+
+	var lc net.ListenConfig
+	return lc.Listen(ctx, "unix", socketPath)
+}
@@ -0,0 +1,11 @@
+package agent
+
+import (
+	"context"
+	"net"
+)
+
+func listenPrivate(ctx context.Context, socketPath string) (net.Listener, error) {
+	var lc net.ListenConfig
+	return lc.Listen(ctx, "unix", socketPath)
+}
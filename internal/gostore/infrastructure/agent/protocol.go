@@ -0,0 +1,54 @@
+package agent
+
+import (
+	"github.com/UsingCoding/gostore/internal/gostore/app/encryption"
+)
+
+type op string
+
+const (
+	hasIdentityOp = op("has-identity")
+	addIdentityOp = op("add-identity")
+	decryptOp     = op("decrypt")
+	clearOp       = op("clear")
+	stopOp        = op("stop")
+)
+
+// request and response are sent as single JSON object per connection.
+// Private keys only sent to agent, agent responds with decrypted data
+type request struct {
+	Op         op        `json:"op"`
+	Recipient  string    `json:"recipient,omitempty"`
+	Identity   *identity `json:"identity,omitempty"`
+	Encryption string    `json:"encryption,omitempty"`
+	Recipients []string  `json:"recipients,omitempty"`
+	Data       []byte    `json:"data,omitempty"`
+}
+
+type response struct {
+	Held  bool   `json:"held,omitempty"`
+	Data  []byte `json:"data,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+type identity struct {
+	Provider   string `json:"provider"`
+	Recipient  string `json:"recipient"`
+	PrivateKey string `json:"privateKey"`
+}
+
+func toIdentity(i encryption.Identity) *identity {
+	return &identity{
+		Provider:   string(i.Provider),
+		Recipient:  string(i.Recipient),
+		PrivateKey: string(i.PrivateKey),
+	}
+}
+
+func fromIdentity(i identity) encryption.Identity {
+	return encryption.Identity{
+		Provider:   encryption.Provider(i.Provider),
+		Recipient:  encryption.Recipient(i.Recipient),
+		PrivateKey: encryption.PrivateKey(i.PrivateKey),
+	}
+}
@@ -0,0 +1,171 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"path"
+	"time"
+
+	"github.com/pkg/errors"
+
+	appagent "github.com/UsingCoding/gostore/internal/gostore/app/agent"
+	"github.com/UsingCoding/gostore/internal/gostore/app/encryption"
+)
+
+const (
+	purgeInterval = time.Minute
+)
+
+// Serve agent on unix socket until ctx is done or agent is stopped by client.
+// Agent decrypts data for clients with identities from keyring
+func Serve(
+	ctx context.Context,
+	socketPath string,
+	keyring *appagent.Keyring,
+	encryptionManager encryption.Manager,
+) error {
+	err := ensureNoAgent(ctx, socketPath)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(path.Dir(socketPath), 0o700)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create dir for agent socket %s", socketPath)
+	}
+
+	l, err := listenPrivate(ctx, socketPath)
+	if err != nil {
+		return errors.Wrapf(err, "failed to listen %s", socketPath)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		ticker := time.NewTicker(purgeInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				_ = l.Close()
+				return
+			case <-ticker.C:
+				keyring.Purge()
+			}
+		}
+	}()
+
+	s := server{
+		keyring:           keyring,
+		encryptionManager: encryptionManager,
+		stop:              cancel,
+	}
+
+	for {
+		conn, err2 := l.Accept()
+		if err2 != nil {
+			if ctx.Err() != nil {
+				// listener closed since agent stopped
+				return nil
+			}
+			return errors.Wrap(err2, "failed to accept connection")
+		}
+
+		go s.handle(conn)
+	}
+}
+
+type server struct {
+	keyring           *appagent.Keyring
+	encryptionManager encryption.Manager
+	stop              func()
+}
+
+func (s server) handle(conn net.Conn) {
+	defer conn.Close()
+
+	_ = conn.SetDeadline(time.Now().Add(ioTimeout))
+
+	var req request
+	err := json.NewDecoder(conn).Decode(&req)
+	if err != nil {
+		_ = json.NewEncoder(conn).Encode(response{Error: "invalid request"})
+		return
+	}
+
+	_ = json.NewEncoder(conn).Encode(s.process(req))
+
+	// stop after response sent to let client know that agent is stopped
+	if req.Op == stopOp {
+		s.stop()
+	}
+}
+
+func (s server) process(req request) response {
+	switch req.Op {
+	case hasIdentityOp:
+		return response{Held: s.keyring.Has(encryption.Recipient(req.Recipient))}
+	case addIdentityOp:
+		if req.Identity == nil {
+			return response{Error: "no identity passed"}
+		}
+		s.keyring.Add(fromIdentity(*req.Identity))
+		return response{}
+	case decryptOp:
+		data, err := s.decrypt(req)
+		if err != nil {
+			return response{Error: err.Error()}
+		}
+		return response{Data: data}
+	case clearOp:
+		s.keyring.Clear()
+		return response{}
+	case stopOp:
+		return response{}
+	default:
+		return response{Error: "unknown op " + string(req.Op)}
+	}
+}
+
+func (s server) decrypt(req request) ([]byte, error) {
+	recipients := make([]encryption.Recipient, 0, len(req.Recipients))
+	for _, r := range req.Recipients {
+		recipients = append(recipients, encryption.Recipient(r))
+	}
+
+	identities := s.keyring.Identities(recipients)
+	if len(identities) == 0 {
+		return nil, errors.New("no identities held for recipients")
+	}
+
+	service, err := s.encryptionManager.EncryptService(encryption.Encryption(req.Encryption))
+	if err != nil {
+		return nil, err
+	}
+
+	return service.Decrypt(req.Data, identities)
+}
+
+// ensureNoAgent checks that socket is not served by other agent and removes stale socket
+func ensureNoAgent(ctx context.Context, socketPath string) error {
+	_, err := os.Stat(socketPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	dialer := net.Dialer{Timeout: dialTimeout}
+	conn, err := dialer.DialContext(ctx, "unix", socketPath)
+	if err == nil {
+		_ = conn.Close()
+		return errors.Errorf("agent already running on %s", socketPath)
+	}
+
+	err = os.Remove(socketPath)
+	return errors.Wrapf(err, "failed to remove stale agent socket %s", socketPath)
+}
@@ -0,0 +1,100 @@
+package clipboard
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/atotto/clipboard"
+	"github.com/pkg/errors"
+)
+
+const (
+	// ClearCommand is hidden gostore command clearing clipboard in detached process
+	ClearCommand = "clipboard-clear"
+
+	DefaultClearTimeout = 45 * time.Second
+)
+
+// Copy writes data to clipboard and clears it after timeout in detached process.
+// Zero timeout disables clearing
+func Copy(data []byte, clearAfter time.Duration) error {
+	err := clipboard.WriteAll(string(data))
+	if err != nil {
+		return errors.Wrap(err, "failed to copy to clipboard")
+	}
+
+	if clearAfter <= 0 {
+		return nil
+	}
+
+	return spawnClear(hash(data), clearAfter)
+}
+
+// ClearAfter waits timeout and clears clipboard only if it still holds data with hash read from r
+func ClearAfter(r io.Reader, timeout time.Duration) error {
+	h, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return errors.Wrap(err, "failed to read clipboard hash")
+	}
+	h = strings.TrimSpace(h)
+
+	time.Sleep(timeout)
+
+	current, err := clipboard.ReadAll()
+	if err != nil {
+		return errors.Wrap(err, "failed to read clipboard")
+	}
+
+	if hash([]byte(current)) != h {
+		// clipboard changed since copy
+		return nil
+	}
+
+	return errors.Wrap(clipboard.WriteAll(""), "failed to clear clipboard")
+}
+
+// spawnClear starts detached gostore process clearing clipboard,
+// hash passed through stdin to not expose it in process list
+func spawnClear(h string, timeout time.Duration) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return errors.Wrap(err, "failed to find gostore executable")
+	}
+
+	cmd := exec.Command(executable, ClearCommand, "--after", timeout.String())
+	detach(cmd)
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return errors.Wrap(err, "failed to open clipboard helper stdin")
+	}
+
+	err = cmd.Start()
+	if err != nil {
+		return errors.Wrap(err, "failed to start clipboard helper")
+	}
+
+	_, err = io.WriteString(stdin, h+"\n")
+	if err != nil {
+		return errors.Wrap(err, "failed to pass hash to clipboard helper")
+	}
+
+	err = stdin.Close()
+	if err != nil {
+		return errors.Wrap(err, "failed to close clipboard helper stdin")
+	}
+
+	// helper outlives command, do not wait for it
+	return errors.Wrap(cmd.Process.Release(), "failed to release clipboard helper")
+}
+
+func hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,15 @@
+package clipboard
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+const (
+	detachedProcess = 0x00000008
+)
+
+// detach runs process without console of parent
+func detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: detachedProcess}
+}
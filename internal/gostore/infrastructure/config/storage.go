@@ -12,6 +12,7 @@ import (
 	"github.com/UsingCoding/gostore/internal/common/maybe"
 	appconfig "github.com/UsingCoding/gostore/internal/gostore/app/config"
 	"github.com/UsingCoding/gostore/internal/gostore/app/encryption"
+	appstorage "github.com/UsingCoding/gostore/internal/gostore/app/storage"
 	"github.com/UsingCoding/gostore/internal/gostore/app/vars"
 )
 
@@ -54,17 +55,39 @@ func (s *storage) Load(context.Context) (appconfig.Config, error) {
 			return appconfig.Store{
 				ID:   appconfig.StoreID(s.ID),
 				Path: s.Path,
+				Auth: maybe.Map(s.Auth, func(a storeAuth) appconfig.StoreAuth {
+					return appconfig.StoreAuth{
+						Type:    appstorage.AuthType(a.Type),
+						User:    a.User,
+						KeyPath: a.KeyPath,
+						Secret: maybe.Map(a.Secret, func(src secretSource) appconfig.SecretSource {
+							return appconfig.SecretSource{
+								Env: src.Env,
+								Secret: maybe.Map(src.Secret, func(r secretRef) appconfig.SecretRef {
+									return appconfig.SecretRef{
+										StoreID: appconfig.StoreID(r.StoreID),
+										Path:    r.Path,
+										Key:     r.Key,
+									}
+								}),
+							}
+						}),
+					}
+				}),
 			}
 		}),
-		Identities: slices.Map(c.Identities, func(i identity) encryption.Identity {
+		Identities: slices.Map(c.Identities, func(i identity) appconfig.Identity {
 			if i.Provider == "" {
 				// fallback to age provider
 				i.Provider = encryption.AgeIdentityProvider
 			}
-			return encryption.Identity{
-				Provider:   encryption.Provider(i.Provider),
-				Recipient:  encryption.Recipient(i.Recipient),
-				PrivateKey: encryption.PrivateKey(i.PrivateKey),
+			return appconfig.Identity{
+				Identity: encryption.Identity{
+					Provider:   encryption.Provider(i.Provider),
+					Recipient:  encryption.Recipient(i.Recipient),
+					PrivateKey: encryption.PrivateKey(i.PrivateKey),
+				},
+				Protected: i.Protected,
 			}
 		}),
 	}, nil
@@ -91,13 +114,33 @@ func (s *storage) Store(_ context.Context, c appconfig.Config) error {
 			return store{
 				ID:   string(s.ID),
 				Path: s.Path,
+				Auth: maybe.Map(s.Auth, func(a appconfig.StoreAuth) storeAuth {
+					return storeAuth{
+						Type:    string(a.Type),
+						User:    a.User,
+						KeyPath: a.KeyPath,
+						Secret: maybe.Map(a.Secret, func(src appconfig.SecretSource) secretSource {
+							return secretSource{
+								Env: src.Env,
+								Secret: maybe.Map(src.Secret, func(r appconfig.SecretRef) secretRef {
+									return secretRef{
+										StoreID: string(r.StoreID),
+										Path:    r.Path,
+										Key:     r.Key,
+									}
+								}),
+							}
+						}),
+					}
+				}),
 			}
 		}),
-		Identities: slices.Map(c.Identities, func(i encryption.Identity) identity {
+		Identities: slices.Map(c.Identities, func(i appconfig.Identity) identity {
 			return identity{
 				Provider:   string(i.Provider),
 				Recipient:  string(i.Recipient),
 				PrivateKey: string(i.PrivateKey),
+				Protected:  i.Protected,
 			}
 		}),
 	}, "", "    ")
@@ -107,9 +150,15 @@ func (s *storage) Store(_ context.Context, c appconfig.Config) error {
 
 	p := path.Join(s.configDir, configName)
 
-	//nolint:gosec
-	err = os.WriteFile(p, data, 0o644)
-	return errors.Wrapf(err, "failed to save config to file %s", p)
+	// config contains private keys, so keep it readable only by owner
+	err = os.WriteFile(p, data, 0o600)
+	if err != nil {
+		return errors.Wrapf(err, "failed to save config to file %s", p)
+	}
+
+	// WriteFile does not change permissions of existing file
+	err = os.Chmod(p, 0o600)
+	return errors.Wrapf(err, "failed to set permissions for config file %s", p)
 }
 
 type config struct {
@@ -120,14 +169,34 @@ type config struct {
 }
 
 type store struct {
-	ID   string `json:"id"`
-	Path string `json:"path"`
+	ID   string                 `json:"id"`
+	Path string                 `json:"path"`
+	Auth maybe.Maybe[storeAuth] `json:"auth,omitzero"`
+}
+
+type storeAuth struct {
+	Type    string                    `json:"type"`
+	User    string                    `json:"user,omitempty"`
+	KeyPath string                    `json:"keyPath,omitempty"`
+	Secret  maybe.Maybe[secretSource] `json:"secret,omitzero"`
+}
+
+type secretSource struct {
+	Env    maybe.Maybe[string]    `json:"env,omitzero"`
+	Secret maybe.Maybe[secretRef] `json:"secret,omitzero"`
+}
+
+type secretRef struct {
+	StoreID string              `json:"storeID"`
+	Path    string              `json:"path"`
+	Key     maybe.Maybe[string] `json:"key,omitzero"`
 }
 
 type identity struct {
 	Provider   string `json:"provider"`
 	Recipient  string `json:"recipient"`
 	PrivateKey string `json:"privateKey"`
+	Protected  bool   `json:"protected,omitempty"`
 }
 
 func exists(p string) (bool, error) {
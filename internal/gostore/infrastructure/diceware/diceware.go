@@ -0,0 +1,18 @@
+package diceware
+
+import (
+	"github.com/sethvargo/go-diceware/diceware"
+
+	"github.com/UsingCoding/gostore/internal/gostore/app/usecase/generate"
+)
+
+// NewWordGenerator returns generator of words from EFF large wordlist
+func NewWordGenerator() generate.WordGenerator {
+	return wordGenerator{}
+}
+
+type wordGenerator struct{}
+
+func (wordGenerator) Words(n int) ([]string, error) {
+	return diceware.Generate(n)
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 
 	"filippo.io/age"
+	"filippo.io/age/agessh"
 	"filippo.io/age/armor"
 	"github.com/UsingCoding/fpgo/pkg/slices"
 	"github.com/pkg/errors"
@@ -22,9 +23,7 @@ type ageService struct{}
 func (s *ageService) Encrypt(data []byte, recipients []encryption.Recipient) ([]byte, error) {
 	var buffer bytes.Buffer
 	w := armor.NewWriter(&buffer)
-	recps, err := slices.MapErr(recipients, func(r encryption.Recipient) (age.Recipient, error) {
-		return age.ParseX25519Recipient(string(r))
-	})
+	recps, err := slices.MapErr(recipients, parseRecipient)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to parse recipients")
 	}
@@ -74,6 +73,12 @@ func (s *ageService) Decrypt(data []byte, identities []encryption.Identity) ([]b
 	return decodedData, nil
 }
 
+func (s *ageService) CanonicalRecipient(r encryption.Recipient) (encryption.Recipient, error) {
+	r = encryption.ParseRecipient(string(r))
+	_, err := parseRecipient(r)
+	return r, errors.Wrapf(err, "invalid recipient %s", r)
+}
+
 func (s *ageService) generateIdentity() (encryption.Identity, error) {
 	identity, err := age.GenerateX25519Identity()
 	if err != nil {
@@ -87,7 +92,7 @@ func (s *ageService) generateIdentity() (encryption.Identity, error) {
 	}, nil
 }
 
-func (s *ageService) loadRawIdentity(data []byte) (encryption.Identity, error) {
+func (s *ageService) loadRawIdentity(data, _ []byte) (encryption.Identity, error) {
 	identities, err := age.ParseIdentities(bytes.NewBuffer(data))
 	if err != nil {
 		return encryption.Identity{}, err
@@ -121,6 +126,20 @@ func (s *ageService) exportRawIdentity(identity encryption.Identity) ([]byte, er
 }
 
 func (s *ageService) mapIdentity(identity encryption.Identity) (age.Identity, error) {
+	if identity.Provider == encryption.SSHIdentityProvider {
+		i, err := agessh.ParseIdentity(identity.PrivateKey)
+		return i, errors.Wrapf(err, "failed to map SSH identity for recipient %s", identity.Recipient)
+	}
+
 	i, err := age.ParseX25519Identity(string(identity.PrivateKey))
 	return i, errors.Wrapf(err, "failed to map identity for recipient %s", identity.Recipient)
 }
+
+// parseRecipient parses X25519 or SSH recipient
+func parseRecipient(r encryption.Recipient) (age.Recipient, error) {
+	if isSSHRecipient(r) {
+		return agessh.ParseRecipient(string(r))
+	}
+
+	return age.ParseX25519Recipient(string(r))
+}
@@ -29,13 +29,13 @@ func (manager *encryptionManager) EncryptService(e encryption.Encryption) (encry
 	return manager.makeEncService(e)
 }
 
-func (manager *encryptionManager) ImportRawIdentity(provider encryption.Provider, data []byte) (encryption.Identity, error) {
+func (manager *encryptionManager) ImportRawIdentity(provider encryption.Provider, data, passphrase []byte) (encryption.Identity, error) {
 	service, err := manager.makeEncServiceForProvider(provider)
 	if err != nil {
 		return encryption.Identity{}, err
 	}
 
-	return service.loadRawIdentity(data)
+	return service.loadRawIdentity(data, passphrase)
 }
 
 func (manager *encryptionManager) ExportRawIdentity(identity encryption.Identity) ([]byte, error) {
@@ -51,6 +51,8 @@ func (manager *encryptionManager) makeEncService(e encryption.Encryption) (encSe
 	switch e {
 	case encryption.AgeEncryption:
 		return newAgeService(), nil
+	case encryption.PGPEncryption:
+		return newPGPService(), nil
 	default:
 		return nil, errors.Errorf("unknown type of encryption %s", e)
 	}
@@ -60,6 +62,10 @@ func (manager *encryptionManager) makeEncServiceForProvider(p encryption.Provide
 	switch p {
 	case encryption.AgeIdentityProvider:
 		return newAgeService(), nil
+	case encryption.SSHIdentityProvider:
+		return newSSHService(), nil
+	case encryption.PGPIdentityProvider:
+		return newPGPService(), nil
 	default:
 		return nil, errors.Errorf("unknown provider %s", p)
 	}
@@ -70,6 +76,6 @@ type encService interface {
 
 	generateIdentity() (encryption.Identity, error)
 
-	loadRawIdentity(data []byte) (encryption.Identity, error)
+	loadRawIdentity(data, passphrase []byte) (encryption.Identity, error)
 	exportRawIdentity(identity encryption.Identity) ([]byte, error)
 }
@@ -0,0 +1,71 @@
+package encryption
+
+import (
+	"bytes"
+	"io"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+	"github.com/pkg/errors"
+
+	"github.com/UsingCoding/gostore/internal/gostore/app/encryption"
+)
+
+func (manager *encryptionManager) ProtectPrivateKey(key encryption.PrivateKey, passphrase []byte) (encryption.PrivateKey, error) {
+	if len(passphrase) == 0 {
+		return nil, errors.New("empty passphrase")
+	}
+
+	recipient, err := age.NewScryptRecipient(string(passphrase))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create passphrase recipient")
+	}
+
+	var buffer bytes.Buffer
+	w := armor.NewWriter(&buffer)
+
+	encryptedWriter, err := age.Encrypt(w, recipient)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	_, err = encryptedWriter.Write(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encrypt private key")
+	}
+
+	err = encryptedWriter.Close()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to close writer after private key encryption")
+	}
+
+	err = w.Close()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to close armored writer after private key encryption")
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func (manager *encryptionManager) UnprotectPrivateKey(key encryption.PrivateKey, passphrase []byte) (encryption.PrivateKey, error) {
+	identity, err := age.NewScryptIdentity(string(passphrase))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create passphrase identity")
+	}
+
+	reader, err := age.Decrypt(armor.NewReader(bytes.NewReader(key)), identity)
+	if err != nil {
+		var noMatch *age.NoIdentityMatchError
+		if errors.As(err, &noMatch) {
+			return nil, errors.WithStack(encryption.ErrInvalidPassphrase)
+		}
+		return nil, errors.Wrap(err, "failed to decrypt private key")
+	}
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read decrypted private key")
+	}
+
+	return data, nil
+}
@@ -0,0 +1,204 @@
+package encryption
+
+import (
+	"bytes"
+	"io"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/UsingCoding/fpgo/pkg/slices"
+	"github.com/pkg/errors"
+
+	"github.com/UsingCoding/gostore/internal/gostore/app/encryption"
+)
+
+const (
+	pgpIdentityName = "gostore"
+)
+
+func newPGPService() encService {
+	return &pgpService{}
+}
+
+// pgpService encrypts with OpenPGP, recipients are armored public keys and identities are armored private keys
+type pgpService struct{}
+
+func (s *pgpService) Encrypt(data []byte, recipients []encryption.Recipient) ([]byte, error) {
+	entities, err := slices.MapErr(recipients, func(r encryption.Recipient) (*openpgp.Entity, error) {
+		return readEntity([]byte(r))
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse recipients")
+	}
+
+	var buffer bytes.Buffer
+	w, err := armor.Encode(&buffer, "PGP MESSAGE", nil)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	encryptedWriter, err := openpgp.Encrypt(w, entities, nil, nil, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encrypt secret")
+	}
+
+	_, err = encryptedWriter.Write(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encrypt secret")
+	}
+
+	err = encryptedWriter.Close()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to close writer after encryption")
+	}
+
+	err = w.Close()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to close armored writer after encryption")
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func (s *pgpService) Decrypt(data []byte, identities []encryption.Identity) ([]byte, error) {
+	keyring, err := slices.MapErr(identities, func(i encryption.Identity) (*openpgp.Entity, error) {
+		e, err2 := readEntity(i.PrivateKey)
+		return e, errors.Wrapf(err2, "failed to map identity for recipient %s", i.Recipient)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := armor.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode armored data")
+	}
+
+	md, err := openpgp.ReadMessage(block.Body, openpgp.EntityList(keyring), nil, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt data")
+	}
+
+	decodedData, err := io.ReadAll(md.UnverifiedBody)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read decoded data")
+	}
+
+	return decodedData, nil
+}
+
+func (s *pgpService) CanonicalRecipient(r encryption.Recipient) (encryption.Recipient, error) {
+	e, err := readEntity(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid PGP public key")
+	}
+
+	return pgpRecipient(e)
+}
+
+func (s *pgpService) generateIdentity() (encryption.Identity, error) {
+	e, err := openpgp.NewEntity(pgpIdentityName, "", "", nil)
+	if err != nil {
+		return encryption.Identity{}, errors.WithStack(err)
+	}
+
+	return pgpIdentity(e)
+}
+
+// loadRawIdentity parses armored private key, passphrase used to decrypt protected key
+func (s *pgpService) loadRawIdentity(data, passphrase []byte) (encryption.Identity, error) {
+	e, err := readEntity(data)
+	if err != nil {
+		return encryption.Identity{}, err
+	}
+
+	if e.PrivateKey == nil {
+		return encryption.Identity{}, errors.New("no PGP private key passed")
+	}
+
+	if e.PrivateKey.Encrypted {
+		if len(passphrase) == 0 {
+			recipient, err2 := pgpRecipient(e)
+			if err2 != nil {
+				return encryption.Identity{}, err2
+			}
+			return encryption.Identity{}, errors.WithStack(encryption.PassphraseRequiredError{Recipient: recipient})
+		}
+
+		err = e.DecryptPrivateKeys(passphrase)
+		if err != nil {
+			return encryption.Identity{}, errors.WithStack(encryption.ErrInvalidPassphrase)
+		}
+	}
+
+	return pgpIdentity(e)
+}
+
+func (s *pgpService) exportRawIdentity(identity encryption.Identity) ([]byte, error) {
+	return identity.PrivateKey, nil
+}
+
+func readEntity(data []byte) (*openpgp.Entity, error) {
+	entities, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read PGP key")
+	}
+
+	if len(entities) != 1 {
+		return nil, errors.Errorf("invalid count of PGP keys: %d", len(entities))
+	}
+
+	return entities[0], nil
+}
+
+// pgpIdentity returns identity with private key stored without passphrase
+func pgpIdentity(e *openpgp.Entity) (encryption.Identity, error) {
+	recipient, err := pgpRecipient(e)
+	if err != nil {
+		return encryption.Identity{}, err
+	}
+
+	privateKey, err := armorKey(openpgp.PrivateKeyType, func(w io.Writer) error {
+		return e.SerializePrivateWithoutSigning(w, nil)
+	})
+	if err != nil {
+		return encryption.Identity{}, errors.Wrap(err, "failed to serialize PGP private key")
+	}
+
+	return encryption.Identity{
+		Provider:   encryption.PGPIdentityProvider,
+		Recipient:  recipient,
+		PrivateKey: encryption.PrivateKey(privateKey),
+	}, nil
+}
+
+// pgpRecipient returns public key armored in canonical form, so recipients of the same key are equal
+func pgpRecipient(e *openpgp.Entity) (encryption.Recipient, error) {
+	publicKey, err := armorKey(openpgp.PublicKeyType, e.Serialize)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to serialize PGP public key")
+	}
+
+	return encryption.Recipient(strings.TrimSpace(publicKey)), nil
+}
+
+func armorKey(blockType string, serialize func(w io.Writer) error) (string, error) {
+	var buffer bytes.Buffer
+	w, err := armor.Encode(&buffer, blockType, nil)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	err = serialize(w)
+	if err != nil {
+		return "", err
+	}
+
+	err = w.Close()
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	return buffer.String(), nil
+}
@@ -0,0 +1,92 @@
+package encryption
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/UsingCoding/gostore/internal/gostore/app/encryption"
+)
+
+// newSSHService returns service for existing SSH keys used as age identities
+func newSSHService() encService {
+	return &sshService{}
+}
+
+type sshService struct {
+	ageService
+}
+
+func (s *sshService) generateIdentity() (encryption.Identity, error) {
+	return encryption.Identity{}, errors.New("generating SSH identities is not supported, import existing key")
+}
+
+// loadRawIdentity parses ed25519 or RSA private key in OpenSSH or PEM format.
+// Key stored without passphrase, so it should be protected with identity protection
+func (s *sshService) loadRawIdentity(data, passphrase []byte) (encryption.Identity, error) {
+	var (
+		key any
+		err error
+	)
+	if len(passphrase) == 0 {
+		key, err = ssh.ParseRawPrivateKey(data)
+	} else {
+		key, err = ssh.ParseRawPrivateKeyWithPassphrase(data, passphrase)
+	}
+
+	var missingErr *ssh.PassphraseMissingError
+	switch {
+	case errors.As(err, &missingErr):
+		var recipient encryption.Recipient
+		if missingErr.PublicKey != nil {
+			recipient = sshRecipient(missingErr.PublicKey)
+		}
+		return encryption.Identity{}, errors.WithStack(encryption.PassphraseRequiredError{Recipient: recipient})
+	case errors.Is(err, x509.IncorrectPasswordError):
+		return encryption.Identity{}, errors.WithStack(encryption.ErrInvalidPassphrase)
+	case err != nil:
+		return encryption.Identity{}, errors.Wrap(err, "failed to parse SSH private key")
+	}
+
+	switch k := key.(type) {
+	case *ed25519.PrivateKey:
+		key = *k
+	case ed25519.PrivateKey, *rsa.PrivateKey:
+	default:
+		return encryption.Identity{}, errors.Errorf("unsupported SSH key type %T, only ed25519 and RSA supported", key)
+	}
+
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		return encryption.Identity{}, errors.WithStack(err)
+	}
+
+	block, err := ssh.MarshalPrivateKey(key, "")
+	if err != nil {
+		return encryption.Identity{}, errors.Wrap(err, "failed to marshal SSH private key")
+	}
+
+	return encryption.Identity{
+		Provider:   encryption.SSHIdentityProvider,
+		Recipient:  sshRecipient(signer.PublicKey()),
+		PrivateKey: pem.EncodeToMemory(block),
+	}, nil
+}
+
+func (s *sshService) exportRawIdentity(identity encryption.Identity) ([]byte, error) {
+	return identity.PrivateKey, nil
+}
+
+// sshRecipient returns public key in authorized_keys format without comment
+func sshRecipient(key ssh.PublicKey) encryption.Recipient {
+	return encryption.Recipient(strings.TrimSpace(string(ssh.MarshalAuthorizedKey(key))))
+}
+
+func isSSHRecipient(r encryption.Recipient) bool {
+	return strings.HasPrefix(string(r), "ssh-")
+}
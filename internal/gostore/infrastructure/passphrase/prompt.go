@@ -0,0 +1,65 @@
+package passphrase
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	"golang.org/x/term"
+
+	"github.com/UsingCoding/gostore/internal/common/maybe"
+	"github.com/UsingCoding/gostore/internal/gostore/app/config"
+	"github.com/UsingCoding/gostore/internal/gostore/app/encryption"
+)
+
+const (
+	// EnvPassphrase allows passing passphrase in non-interactive environments
+	EnvPassphrase = "GOSTORE_PASSPHRASE"
+
+	ttyPath = "/dev/tty"
+)
+
+// NewProvider returns provider that uses preset passphrase or prompts it from terminal
+func NewProvider(preset maybe.Maybe[string]) config.PassphraseProvider {
+	return &provider{preset: preset}
+}
+
+type provider struct {
+	preset maybe.Maybe[string]
+}
+
+func (p *provider) Passphrase(_ context.Context, recipient encryption.Recipient) ([]byte, error) {
+	if s, ok := maybe.JustValid(p.preset); ok {
+		return []byte(s), nil
+	}
+
+	return Prompt(fmt.Sprintf("Enter passphrase for %s: ", recipient))
+}
+
+// Prompt reads passphrase from terminal without echo.
+// Terminal opened directly to not consume stdin which may contain secret data
+func Prompt(msg string) ([]byte, error) {
+	tty, err := os.OpenFile(ttyPath, os.O_RDWR, 0)
+	if err != nil {
+		if !term.IsTerminal(int(os.Stdin.Fd())) {
+			return nil, errors.Errorf("no terminal to prompt passphrase, use %s", EnvPassphrase)
+		}
+		return readPassword(os.Stdin, os.Stderr, msg)
+	}
+	defer tty.Close()
+
+	return readPassword(tty, tty, msg)
+}
+
+func readPassword(in, out *os.File, msg string) ([]byte, error) {
+	_, _ = out.WriteString(msg)
+
+	data, err := term.ReadPassword(int(in.Fd()))
+	_, _ = out.WriteString("\n")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read passphrase")
+	}
+
+	return data, nil
+}
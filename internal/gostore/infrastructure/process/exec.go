@@ -0,0 +1,21 @@
+//go:build !windows
+
+package process
+
+import (
+	"os/exec"
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+// Exec replaces current process with command, returns only on failure
+func Exec(name string, args []string, env []string) error {
+	p, err := exec.LookPath(name)
+	if err != nil {
+		return errors.Wrapf(err, "failed to find %s", name)
+	}
+
+	err = syscall.Exec(p, append([]string{name}, args...), env)
+	return errors.Wrapf(err, "failed to exec %s", name)
+}
@@ -0,0 +1,30 @@
+package process
+
+import (
+	"os"
+	"os/exec"
+
+	"github.com/pkg/errors"
+)
+
+// Exec runs command and exits with its code since Windows can not replace process
+func Exec(name string, args []string, env []string) error {
+	cmd := exec.Command(name, args...)
+	cmd.Env = env
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	err := cmd.Run()
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		os.Exit(exitErr.ExitCode())
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to run %s", name)
+	}
+
+	os.Exit(0)
+	return nil
+}
@@ -0,0 +1,35 @@
+package qrcode
+
+import (
+	"image"
+	// register image formats supported to decode
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/qrcode"
+	"github.com/pkg/errors"
+)
+
+// Decode returns text of QR code in PNG or JPEG image
+func Decode(r io.Reader) (string, error) {
+	img, _, err := image.Decode(r)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to decode image")
+	}
+
+	bmp, err := gozxing.NewBinaryBitmapFromImage(img)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read image")
+	}
+
+	res, err := qrcode.NewQRCodeReader().Decode(bmp, map[gozxing.DecodeHintType]interface{}{
+		gozxing.DecodeHintType_TRY_HARDER: true,
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to find QR code in image")
+	}
+
+	return res.GetText(), nil
+}
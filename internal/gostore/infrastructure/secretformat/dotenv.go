@@ -0,0 +1,133 @@
+package secretformat
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// decodeDotenv parses KEY=VALUE lines with optional export prefix, comments,
+// single quoted literal values and double quoted values with escapes which may span lines
+func decodeDotenv(data []byte) (map[string][]byte, error) {
+	res := map[string][]byte{}
+
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, found := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" || strings.ContainsAny(key, " \t") {
+			return nil, errors.Errorf("invalid dotenv line %d: %s", i+1, lines[i])
+		}
+
+		value = strings.TrimLeft(value, " \t")
+
+		switch {
+		case strings.HasPrefix(value, "'"):
+			end := strings.Index(value[1:], "'")
+			if end == -1 {
+				return nil, errors.Errorf("unterminated single quote at line %d", i+1)
+			}
+			res[key] = []byte(value[1 : end+1])
+		case strings.HasPrefix(value, `"`):
+			// value may continue on next lines
+			rest := value[1:]
+			for {
+				v, ok := unquoteDouble(rest)
+				if ok {
+					res[key] = []byte(v)
+					break
+				}
+				i++
+				if i >= len(lines) {
+					return nil, errors.Errorf("unterminated double quote for %s", key)
+				}
+				rest += "\n" + lines[i]
+			}
+		default:
+			// inline comment starts with whitespace and #
+			if idx := strings.Index(value, " #"); idx != -1 {
+				value = value[:idx]
+			}
+			res[key] = []byte(strings.TrimSpace(value))
+		}
+	}
+
+	return res, nil
+}
+
+// unquoteDouble reads value up to closing quote, ok is false when closing quote not found
+func unquoteDouble(s string) (string, bool) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '"':
+			return b.String(), true
+		case c == '\\' && i+1 < len(s):
+			i++
+			switch s[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			default:
+				b.WriteByte(s[i])
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", false
+}
+
+func encodeDotenv(keys []string, payload map[string][]byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+
+	for _, k := range keys {
+		if strings.ContainsAny(k, "= \t\n#") {
+			return nil, errors.Errorf("key %s can not be written to dotenv", k)
+		}
+
+		_, _ = w.WriteString(k + "=" + quoteDotenv(string(payload[k])) + "\n")
+	}
+
+	err := w.Flush()
+	return buf.Bytes(), errors.Wrap(err, "failed to write dotenv")
+}
+
+func quoteDotenv(v string) string {
+	if v == "" {
+		return ""
+	}
+
+	if !strings.ContainsAny(v, " \t\r\n#'\"\\$`") {
+		return v
+	}
+
+	if !strings.ContainsAny(v, "'\r\n") {
+		return "'" + v + "'"
+	}
+
+	r := strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+		"\n", `\n`,
+		"\r", `\r`,
+		"\t", `\t`,
+		"$", `\$`,
+		"`", "\\`",
+	)
+	return `"` + r.Replace(v) + `"`
+}
@@ -0,0 +1,231 @@
+package secretformat
+
+import (
+	"bytes"
+	"encoding/json"
+	stdslices "slices"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Format of flat key-value document
+type Format string
+
+const (
+	Dotenv Format = "dotenv"
+	JSON   Format = "json"
+	YAML   Format = "yaml"
+)
+
+var Formats = []Format{Dotenv, JSON, YAML}
+
+func Parse(s string) (Format, error) {
+	f := Format(s)
+	if !stdslices.Contains(Formats, f) {
+		return "", errors.Errorf("unknown format %s", s)
+	}
+	return f, nil
+}
+
+// Decode parses document to keys, non-string values kept in their source text,
+// nested objects and arrays kept in their JSON representation
+func Decode(f Format, data []byte) (map[string][]byte, error) {
+	switch f {
+	case Dotenv:
+		return decodeDotenv(data)
+	case JSON:
+		return decodeJSON(data)
+	case YAML:
+		return decodeYAML(data)
+	default:
+		return nil, errors.Errorf("unknown format %s", f)
+	}
+}
+
+// Encode writes keys as document, keys sorted for stable output
+func Encode(f Format, payload map[string][]byte) ([]byte, error) {
+	keys := make([]string, 0, len(payload))
+	for k := range payload {
+		keys = append(keys, k)
+	}
+	stdslices.Sort(keys)
+
+	switch f {
+	case Dotenv:
+		return encodeDotenv(keys, payload)
+	case JSON:
+		doc := make(map[string]string, len(payload))
+		for k, v := range payload {
+			doc[k] = string(v)
+		}
+		data, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal json")
+		}
+		return append(data, '\n'), nil
+	case YAML:
+		var node yaml.Node
+		node.Kind = yaml.MappingNode
+		for _, k := range keys {
+			node.Content = append(node.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Value: k},
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: string(payload[k])},
+			)
+		}
+
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		err := enc.Encode(&node)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal yaml")
+		}
+		return buf.Bytes(), errors.Wrap(enc.Close(), "failed to marshal yaml")
+	default:
+		return nil, errors.Errorf("unknown format %s", f)
+	}
+}
+
+func decodeJSON(data []byte) (map[string][]byte, error) {
+	var doc map[string]json.RawMessage
+	err := json.Unmarshal(data, &doc)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse json object")
+	}
+
+	res := make(map[string][]byte, len(doc))
+	for k, v := range doc {
+		switch {
+		case v[0] == '"':
+			var str string
+			err = json.Unmarshal(v, &str)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to parse value of %s", k)
+			}
+			res[k] = []byte(str)
+		case string(v) == "null":
+			res[k] = nil
+		default:
+			// numbers kept as written to not lose precision
+			var buf bytes.Buffer
+			err = json.Compact(&buf, v)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to parse value of %s", k)
+			}
+			res[k] = buf.Bytes()
+		}
+	}
+	return res, nil
+}
+
+func decodeYAML(data []byte) (map[string][]byte, error) {
+	var doc yaml.Node
+	err := yaml.Unmarshal(data, &doc)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse yaml mapping")
+	}
+
+	if doc.Kind == 0 {
+		// empty document
+		return map[string][]byte{}, nil
+	}
+
+	root := resolveAlias(doc.Content[0])
+	if root.Kind != yaml.MappingNode {
+		return nil, errors.New("failed to parse yaml mapping: document is not a mapping")
+	}
+
+	res := make(map[string][]byte, len(root.Content)/2)
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		k := root.Content[i].Value
+		v := resolveAlias(root.Content[i+1])
+
+		switch {
+		case v.Kind == yaml.ScalarNode && v.ShortTag() == nullTag:
+			res[k] = nil
+		case v.Kind == yaml.ScalarNode:
+			// scalar kept as written, so numbers like 0123 or 1.10 and timestamps not changed
+			res[k] = []byte(v.Value)
+		default:
+			value, err2 := yamlToJSON(v)
+			if err2 != nil {
+				return nil, errors.Wrapf(err2, "failed to marshal value of %s", k)
+			}
+			res[k] = value
+		}
+	}
+	return res, nil
+}
+
+const (
+	nullTag  = "!!null"
+	boolTag  = "!!bool"
+	intTag   = "!!int"
+	floatTag = "!!float"
+)
+
+// yamlToJSON converts nested yaml value to JSON keeping scalars as written
+func yamlToJSON(n *yaml.Node) ([]byte, error) {
+	n = resolveAlias(n)
+
+	var buf bytes.Buffer
+	switch n.Kind {
+	case yaml.MappingNode:
+		buf.WriteByte('{')
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			k, err := json.Marshal(n.Content[i].Value)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			v, err := yamlToJSON(n.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			buf.Write(k)
+			buf.WriteByte(':')
+			buf.Write(v)
+		}
+		buf.WriteByte('}')
+	case yaml.SequenceNode:
+		buf.WriteByte('[')
+		for i, c := range n.Content {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			v, err := yamlToJSON(c)
+			if err != nil {
+				return nil, err
+			}
+			buf.Write(v)
+		}
+		buf.WriteByte(']')
+	case yaml.ScalarNode:
+		switch tag := n.ShortTag(); {
+		case tag == nullTag:
+			buf.WriteString("null")
+		case (tag == boolTag || tag == intTag || tag == floatTag) && json.Valid([]byte(n.Value)):
+			buf.WriteString(n.Value)
+		default:
+			// strings, timestamps and numbers not representable in JSON as is
+			v, err := json.Marshal(n.Value)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			buf.Write(v)
+		}
+	default:
+		return nil, errors.Errorf("unsupported yaml node kind %d", n.Kind)
+	}
+	return buf.Bytes(), nil
+}
+
+func resolveAlias(n *yaml.Node) *yaml.Node {
+	for n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+	return n
+}
@@ -10,6 +10,9 @@ import (
 	"strings"
 
 	"github.com/pkg/errors"
+
+	commonstrings "github.com/UsingCoding/gostore/internal/common/strings"
+	appstorage "github.com/UsingCoding/gostore/internal/gostore/app/storage"
 )
 
 // checks that path is relative and has no upper directories
@@ -17,6 +20,44 @@ func relativePathForStorage(p string) bool {
 	return filepath.IsLocal(p)
 }
 
+// listEntriesRecursively lists files in directory skipping storage internal paths and empty dirs
+func listEntriesRecursively(p string, skipPaths []string) ([]appstorage.Entry, error) {
+	dirEntries, err := os.ReadDir(p)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read dir %s", p)
+	}
+
+	//nolint:prealloc
+	var entries []appstorage.Entry
+
+	for _, entry := range dirEntries {
+		var children []appstorage.Entry
+
+		if commonstrings.HasPrefix(entry.Name(), skipPaths) {
+			continue
+		}
+
+		if entry.IsDir() {
+			children, err = listEntriesRecursively(path.Join(p, entry.Name()), skipPaths)
+			if err != nil {
+				return nil, err
+			}
+
+			if len(children) == 0 {
+				// skip empty dirs
+				continue
+			}
+		}
+
+		entries = append(entries, appstorage.Entry{
+			Name:     entry.Name(),
+			Children: children,
+		})
+	}
+
+	return entries, nil
+}
+
 func move(src, dst string) error {
 	dstDir := path.Dir(dst)
 	e, err := exists(dstDir)
@@ -0,0 +1,397 @@
+package storage
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/UsingCoding/gostore/internal/common/maybe"
+	appstorage "github.com/UsingCoding/gostore/internal/gostore/app/storage"
+)
+
+const (
+	// fsJournalDir keeps committed snapshots of fs storage
+	fsJournalDir = ".gostore-fs"
+)
+
+var (
+	fsStoragePaths = []string{
+		fsJournalDir,
+	}
+)
+
+// fsStorage keeps secrets as plain files in directory.
+// Commits recorded in journal inside storage directory.
+// Uncommitted changes live in directory itself and restored from last snapshot on rollback
+type fsStorage struct {
+	dir     string
+	journal journal
+}
+
+func newFSStorage(dir string) *fsStorage {
+	return &fsStorage{
+		dir: dir,
+		journal: journal{
+			blobs: fsJournalBlobs{dir: path.Join(dir, fsJournalDir)},
+		},
+	}
+}
+
+func initFSStorage(p string) (*fsStorage, error) {
+	s := newFSStorage(p)
+
+	for _, d := range []string{journalObjectsPrefix, journalRevisionsPrefix} {
+		err := os.MkdirAll(path.Join(p, fsJournalDir, d), 0o755)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create fs storage journal dir %s", d)
+		}
+	}
+
+	return s, nil
+}
+
+func (storage *fsStorage) Store(_ context.Context, p string, data []byte) error {
+	fullPath, err := storage.filePath(p)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(path.Dir(fullPath), os.ModePerm)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create subdirectories for path %s", p)
+	}
+
+	//nolint:gosec
+	err = os.WriteFile(fullPath, data, 0o644)
+	return errors.Wrapf(err, "failed to write file to %s", fullPath)
+}
+
+func (storage *fsStorage) Remove(_ context.Context, p string) error {
+	fullPath, err := storage.filePath(p)
+	if err != nil {
+		return err
+	}
+
+	err = os.RemoveAll(fullPath)
+	return errors.Wrapf(err, "failed to remove %s", p)
+}
+
+func (storage *fsStorage) Copy(_ context.Context, src, dst string) error {
+	srcPath, err := storage.filePath(src)
+	if err != nil {
+		return err
+	}
+
+	dstPath, err := storage.filePath(dst)
+	if err != nil {
+		return err
+	}
+
+	err = copyPath(srcPath, dstPath)
+	return errors.Wrapf(err, "failed to copy %s to %s", src, dst)
+}
+
+func (storage *fsStorage) Move(_ context.Context, src, dst string) error {
+	srcPath, err := storage.filePath(src)
+	if err != nil {
+		return err
+	}
+
+	dstPath, err := storage.filePath(dst)
+	if err != nil {
+		return err
+	}
+
+	err = move(srcPath, dstPath)
+	return errors.Wrapf(err, "failed to move %s to %s", src, dst)
+}
+
+func (storage *fsStorage) Get(_ context.Context, p string) (maybe.Maybe[[]byte], error) {
+	fullPath, err := storage.filePath(p)
+	if err != nil {
+		return maybe.Maybe[[]byte]{}, err
+	}
+
+	stat, err := os.Stat(fullPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return maybe.Maybe[[]byte]{}, nil
+		}
+
+		return maybe.Maybe[[]byte]{}, errors.Wrapf(err, "failed to find a path in storage %s", p)
+	}
+
+	if stat.IsDir() {
+		return maybe.Maybe[[]byte]{}, errors.Errorf("found directory from storage, not a file: %s", p)
+	}
+
+	data, err := os.ReadFile(fullPath)
+	if err != nil {
+		return maybe.Maybe[[]byte]{}, errors.WithStack(err)
+	}
+
+	return maybe.NewJust(data), nil
+}
+
+func (storage *fsStorage) GetLatest(ctx context.Context, p string) (maybe.Maybe[[]byte], error) {
+	return storage.GetAt(ctx, p, headRevision)
+}
+
+func (storage *fsStorage) GetAt(ctx context.Context, p, revision string) (maybe.Maybe[[]byte], error) {
+	if !relativePathForStorage(p) {
+		return maybe.Maybe[[]byte]{}, errors.Errorf("path to secret is not local: %s", p)
+	}
+
+	return storage.journal.getAt(ctx, p, revision)
+}
+
+func (storage *fsStorage) History(ctx context.Context, p string) ([]appstorage.Revision, error) {
+	if !relativePathForStorage(p) {
+		return nil, errors.Errorf("path to secret is not local: %s", p)
+	}
+
+	return storage.journal.history(ctx, p)
+}
+
+func (storage *fsStorage) ResolveRevision(ctx context.Context, revision string) (maybe.Maybe[string], error) {
+	return storage.journal.resolve(ctx, revision)
+}
+
+func (storage *fsStorage) List(_ context.Context, p string) (appstorage.Tree, error) {
+	fixedPath := storage.dir
+	if p != "" {
+		var err error
+		fixedPath, err = storage.filePath(p)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	stat, err := os.Stat(fixedPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, errors.Wrapf(err, "failed to find a path in storage %s", p)
+	}
+
+	if !stat.IsDir() {
+		return nil, nil
+	}
+
+	entries, err := listEntriesRecursively(fixedPath, fsStoragePaths)
+	return entries, errors.Wrap(err, "failed to list storage entries")
+}
+
+func (storage *fsStorage) ListAt(ctx context.Context, p, revision string) (appstorage.Tree, error) {
+	if p != "" && !relativePathForStorage(p) {
+		return nil, errors.Errorf("path to list is not local: %s", p)
+	}
+
+	return storage.journal.listAt(ctx, p, revision)
+}
+
+func (storage *fsStorage) AddRemote(context.Context, string, string) error {
+	return errFSNoRemotes
+}
+
+func (storage *fsStorage) Push(context.Context) error {
+	return errFSNoRemotes
+}
+
+func (storage *fsStorage) Fetch(context.Context) (maybe.Maybe[string], error) {
+	return maybe.Maybe[string]{}, errFSNoRemotes
+}
+
+func (storage *fsStorage) MergeBase(context.Context, string, string) (maybe.Maybe[string], error) {
+	return maybe.Maybe[string]{}, errFSNoRemotes
+}
+
+func (storage *fsStorage) FastForward(context.Context, string) error {
+	return errFSNoRemotes
+}
+
+func (storage *fsStorage) Merge(context.Context, string, string) error {
+	return errFSNoRemotes
+}
+
+func (storage *fsStorage) Commit(ctx context.Context, msg string) error {
+	files, err := storage.files()
+	if err != nil {
+		return err
+	}
+
+	tree := make(map[string]string, len(files))
+	for _, p := range files {
+		data, err2 := os.ReadFile(path.Join(storage.dir, p))
+		if err2 != nil {
+			return errors.Wrapf(err2, "failed to read %s", p)
+		}
+
+		tree[p], err2 = storage.journal.putObject(ctx, data)
+		if err2 != nil {
+			return errors.Wrapf(err2, "failed to write object for %s", p)
+		}
+	}
+
+	return storage.journal.commit(ctx, tree, msg)
+}
+
+func (storage *fsStorage) Rollback(ctx context.Context) error {
+	head, err := storage.journal.tree(ctx, headRevision)
+	if err != nil {
+		return err
+	}
+
+	current, err := storage.files()
+	if err != nil {
+		return err
+	}
+
+	for _, p := range current {
+		if _, ok := head.Tree[p]; ok {
+			continue
+		}
+
+		err = os.Remove(path.Join(storage.dir, p))
+		if err != nil {
+			return errors.Wrapf(err, "failed to remove uncommitted %s", p)
+		}
+	}
+
+	for p, hash := range head.Tree {
+		data, err2 := storage.journal.object(ctx, hash)
+		if err2 != nil {
+			return err2
+		}
+
+		fullPath := path.Join(storage.dir, p)
+
+		existed, err2 := os.ReadFile(fullPath)
+		if err2 == nil && string(existed) == string(data) {
+			continue
+		}
+
+		err2 = os.MkdirAll(path.Dir(fullPath), os.ModePerm)
+		if err2 != nil {
+			return errors.Wrapf(err2, "failed to create subdirectories for path %s", p)
+		}
+
+		//nolint:gosec
+		err2 = os.WriteFile(fullPath, data, 0o644)
+		if err2 != nil {
+			return errors.Wrapf(err2, "failed to restore %s", p)
+		}
+	}
+
+	return nil
+}
+
+// files returns paths of all files in storage except journal
+func (storage *fsStorage) files() ([]string, error) {
+	var res []string
+	err := filepath.WalkDir(storage.dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(storage.dir, p)
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if rel == fsJournalDir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		res = append(res, filepath.ToSlash(rel))
+		return nil
+	})
+	return res, errors.Wrap(err, "failed to walk storage files")
+}
+
+func (storage *fsStorage) filePath(p string) (string, error) {
+	if !relativePathForStorage(p) {
+		return "", errors.Errorf("path to secret is not local: %s", p)
+	}
+
+	if p == fsJournalDir || strings.HasPrefix(p, fsJournalDir+"/") {
+		return "", errors.Errorf("access to storage internal objects in %s", p)
+	}
+
+	return path.Join(storage.dir, p), nil
+}
+
+var (
+	errFSNoRemotes = errors.New("fs storage does not support remotes")
+)
+
+// writeFileAtomic writes file through temporary one to not leave partially written file
+func writeFileAtomic(p string, data []byte) error {
+	tmp := p + ".tmp"
+
+	//nolint:gosec
+	err := os.WriteFile(tmp, data, 0o644)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, p)
+}
+
+// fsJournalBlobs keeps journal records as files in dir
+type fsJournalBlobs struct {
+	dir string
+}
+
+func (b fsJournalBlobs) read(_ context.Context, key string) (maybe.Maybe[[]byte], error) {
+	data, err := os.ReadFile(path.Join(b.dir, key))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return maybe.Maybe[[]byte]{}, nil
+		}
+		return maybe.Maybe[[]byte]{}, errors.WithStack(err)
+	}
+
+	return maybe.NewJust(data), nil
+}
+
+func (b fsJournalBlobs) write(_ context.Context, key string, data []byte) error {
+	return writeFileAtomic(path.Join(b.dir, key), data)
+}
+
+func (b fsJournalBlobs) exists(_ context.Context, key string) (bool, error) {
+	return exists(path.Join(b.dir, key))
+}
+
+func (b fsJournalBlobs) keys(_ context.Context, prefix string) ([]string, error) {
+	dir, name := path.Split(prefix)
+
+	entries, err := os.ReadDir(path.Join(b.dir, dir))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var res []string
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), name) && !strings.HasSuffix(entry.Name(), ".tmp") {
+			res = append(res, dir+entry.Name())
+		}
+	}
+
+	return res, nil
+}
+
+// writeHead just replaces HEAD since local storage used by single writer
+func (b fsJournalBlobs) writeHead(_ context.Context, _ maybe.Maybe[string], revision string) error {
+	return writeFileAtomic(path.Join(b.dir, journalHeadKey), []byte(revision))
+}
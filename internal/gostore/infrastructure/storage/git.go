@@ -6,8 +6,11 @@ import (
 	"io"
 	"os"
 	"path"
+	"strings"
 
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
 
 	"github.com/UsingCoding/gostore/internal/gostore/app/progress"
 
@@ -16,7 +19,6 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/UsingCoding/gostore/internal/common/maybe"
-	commonstrings "github.com/UsingCoding/gostore/internal/common/strings"
 	appstorage "github.com/UsingCoding/gostore/internal/gostore/app/storage"
 )
 
@@ -29,6 +31,7 @@ var (
 type gitStorage struct {
 	repo    *git.Repository
 	repoDir string
+	auth    appstorage.AuthProvider
 }
 
 func (storage *gitStorage) Store(_ context.Context, p string, data []byte) error {
@@ -181,17 +184,64 @@ func (storage *gitStorage) GetLatest(_ context.Context, p string) (maybe.Maybe[[
 		return maybe.Maybe[[]byte]{}, nil
 	}
 
-	file, err := commit.File(p)
+	return fileFromCommit(commit, p)
+}
+
+func (storage *gitStorage) GetAt(_ context.Context, p, revision string) (maybe.Maybe[[]byte], error) {
+	if !relativePathForStorage(p) {
+		return maybe.NewNone[[]byte](), errors.Errorf("path to secret is not local: %s", p)
+	}
+
+	commit, err := storage.commitByRevision(revision)
 	if err != nil {
-		return maybe.Maybe[[]byte]{}, errors.Wrap(err, "failed to get file from commit")
+		return maybe.Maybe[[]byte]{}, err
 	}
 
-	content, err := file.Contents()
+	data, err := fileFromCommit(commit, p)
+	if errors.Is(err, object.ErrFileNotFound) {
+		// path not exists at revision
+		return maybe.Maybe[[]byte]{}, nil
+	}
+	return data, err
+}
+
+func (storage *gitStorage) History(_ context.Context, p string) ([]appstorage.Revision, error) {
+	if !relativePathForStorage(p) {
+		return nil, errors.Errorf("path to secret is not local: %s", p)
+	}
+
+	_, err := storage.repo.Head()
 	if err != nil {
-		return maybe.Maybe[[]byte]{}, errors.Wrap(err, "failed to get file content from commit")
+		if errors.Is(err, plumbing.ErrReferenceNotFound) {
+			// no commits in repo
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "failed to get repo head")
 	}
 
-	return maybe.NewJust([]byte(content)), nil
+	// walk parents depth-first instead of ordering by time since commits may have same time
+	iter, err := storage.repo.Log(&git.LogOptions{
+		Order: git.LogOrderDFS,
+		PathFilter: func(s string) bool {
+			return s == p
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get history of %s", p)
+	}
+	defer iter.Close()
+
+	var res []appstorage.Revision
+	err = iter.ForEach(func(commit *object.Commit) error {
+		res = append(res, appstorage.Revision{
+			ID:      commit.Hash.String(),
+			Author:  fmt.Sprintf("%s <%s>", commit.Author.Name, commit.Author.Email),
+			Time:    commit.Author.When,
+			Message: strings.TrimSpace(commit.Message),
+		})
+		return nil
+	})
+	return res, errors.Wrap(err, "failed to iterate commits")
 }
 
 func (storage *gitStorage) List(_ context.Context, p string) (appstorage.Tree, error) {
@@ -217,7 +267,48 @@ func (storage *gitStorage) List(_ context.Context, p string) (appstorage.Tree, e
 		return nil, nil
 	}
 
-	entries, err := storage.listEntriesRecursively(fixedPath)
+	entries, err := listEntriesRecursively(fixedPath, storagePaths)
+	return entries, errors.Wrap(err, "failed to list storage entries")
+}
+
+func (storage *gitStorage) ResolveRevision(_ context.Context, revision string) (maybe.Maybe[string], error) {
+	hash, err := storage.repo.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+		if errors.Is(err, plumbing.ErrReferenceNotFound) || errors.Is(err, plumbing.ErrObjectNotFound) {
+			return maybe.Maybe[string]{}, nil
+		}
+		return maybe.Maybe[string]{}, errors.Wrapf(err, "failed to resolve revision %s", revision)
+	}
+
+	return maybe.NewJust(hash.String()), nil
+}
+
+func (storage *gitStorage) ListAt(_ context.Context, p, revision string) (appstorage.Tree, error) {
+	if p != "" && !relativePathForStorage(p) {
+		return nil, errors.Errorf("path to list is not local: %s", p)
+	}
+
+	commit, err := storage.commitByRevision(revision)
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get tree of commit %s", commit.Hash)
+	}
+
+	if p != "" {
+		tree, err = tree.Tree(p)
+		if err != nil {
+			if errors.Is(err, object.ErrDirectoryNotFound) {
+				return nil, nil
+			}
+			return nil, errors.Wrapf(err, "failed to get tree of %s", p)
+		}
+	}
+
+	entries, err := storage.listTreeEntriesRecursively(tree)
 	return entries, errors.Wrap(err, "failed to list storage entries")
 }
 
@@ -239,6 +330,11 @@ func (storage *gitStorage) Push(ctx context.Context) error {
 		return errors.New("no remotes found to push")
 	}
 
+	authMethod, err := gitAuthMethod(ctx, storage.auth)
+	if err != nil {
+		return err
+	}
+
 	for _, remote := range remotes {
 		err2 := func() error {
 			name := remote.Config().Name
@@ -249,7 +345,7 @@ func (storage *gitStorage) Push(ctx context.Context) error {
 
 			err2 := storage.repo.PushContext(ctx, &git.PushOptions{
 				RemoteName: name,
-				Auth:       nil,
+				Auth:       authMethod,
 				Progress:   p,
 			})
 			if errors.Is(err2, git.NoErrAlreadyUpToDate) {
@@ -265,11 +361,16 @@ func (storage *gitStorage) Push(ctx context.Context) error {
 	return nil
 }
 
-func (storage *gitStorage) Pull(ctx context.Context) error {
+func (storage *gitStorage) Fetch(ctx context.Context) (maybe.Maybe[string], error) {
 	// ensure remote exists
 	_, err := storage.repo.Remote(remoteName)
 	if err != nil {
-		return errors.Wrap(err, "failed to get remote from repo")
+		return maybe.Maybe[string]{}, errors.Wrap(err, "failed to get remote from repo")
+	}
+
+	authMethod, err := gitAuthMethod(ctx, storage.auth)
+	if err != nil {
+		return maybe.Maybe[string]{}, err
 	}
 
 	// enclose fetching in function to correct defer behavior
@@ -280,13 +381,62 @@ func (storage *gitStorage) Pull(ctx context.Context) error {
 
 		return storage.repo.FetchContext(ctx, &git.FetchOptions{
 			RemoteName: remoteName,
+			Auth:       authMethod,
 			Progress:   p,
 		})
 	}()
+	if err != nil &&
+		!errors.Is(err, git.NoErrAlreadyUpToDate) &&
+		!errors.Is(err, transport.ErrEmptyRemoteRepository) {
+		return maybe.Maybe[string]{}, errors.Wrap(err, "failed to fetch from repo")
+	}
+
+	head, err := storage.repo.Reference(plumbing.HEAD, false)
+	if err != nil {
+		return maybe.Maybe[string]{}, errors.Wrap(err, "failed to get repo head")
+	}
+
+	remoteRef, err := storage.repo.Reference(
+		plumbing.NewRemoteReferenceName(remoteName, head.Target().Short()),
+		true,
+	)
 	if err != nil {
-		if errors.Is(err, git.NoErrAlreadyUpToDate) {
-			return nil
+		if errors.Is(err, plumbing.ErrReferenceNotFound) {
+			// remote branch not exists yet
+			return maybe.Maybe[string]{}, nil
 		}
+		return maybe.Maybe[string]{}, errors.Wrap(err, "failed to get remote branch")
+	}
+
+	return maybe.NewJust(remoteRef.Hash().String()), nil
+}
+
+func (storage *gitStorage) MergeBase(_ context.Context, revision1, revision2 string) (maybe.Maybe[string], error) {
+	commit1, err := storage.commitByRevision(revision1)
+	if err != nil {
+		return maybe.Maybe[string]{}, err
+	}
+
+	commit2, err := storage.commitByRevision(revision2)
+	if err != nil {
+		return maybe.Maybe[string]{}, err
+	}
+
+	bases, err := commit1.MergeBase(commit2)
+	if err != nil {
+		return maybe.Maybe[string]{}, errors.Wrapf(err, "failed to find merge base of %s and %s", revision1, revision2)
+	}
+
+	if len(bases) == 0 {
+		return maybe.Maybe[string]{}, nil
+	}
+
+	return maybe.NewJust(bases[0].Hash.String()), nil
+}
+
+func (storage *gitStorage) FastForward(_ context.Context, revision string) error {
+	commit, err := storage.commitByRevision(revision)
+	if err != nil {
 		return err
 	}
 
@@ -295,14 +445,63 @@ func (storage *gitStorage) Pull(ctx context.Context) error {
 		return errors.WithStack(err)
 	}
 
-	p := defaultProgress(ctx).Alter(progress.WithDescription("Pulling store"))
-	defer p.Finish()
+	// hard reset discards uncommitted changes, so do not move over them
+	status, err := worktree.Status()
+	if err != nil {
+		return errors.Wrap(err, "failed to get worktree status")
+	}
+	if !status.IsClean() {
+		return errors.Errorf("worktree has uncommitted changes, commit or rollback them before moving to %s", revision)
+	}
+
+	head, err := storage.repo.Reference(plumbing.HEAD, false)
+	if err != nil {
+		return errors.Wrap(err, "failed to get repo head")
+	}
+
+	// set branch directly since it may be not born yet
+	err = storage.repo.Storer.SetReference(plumbing.NewHashReference(head.Target(), commit.Hash))
+	if err != nil {
+		return errors.Wrapf(err, "failed to move %s to %s", head.Target().Short(), revision)
+	}
 
-	err = worktree.PullContext(ctx, &git.PullOptions{
-		RemoteName: remoteName,
-		Progress:   p,
+	err = worktree.Reset(&git.ResetOptions{
+		Commit: commit.Hash,
+		Mode:   git.HardReset,
 	})
-	return errors.Wrap(err, "failed to pull from repo")
+	return errors.Wrapf(err, "failed to reset worktree to %s", revision)
+}
+
+func (storage *gitStorage) Merge(_ context.Context, revision, msg string) error {
+	commit, err := storage.commitByRevision(revision)
+	if err != nil {
+		return err
+	}
+
+	head, err := storage.repo.Head()
+	if err != nil {
+		return errors.Wrap(err, "failed to get repo head")
+	}
+
+	worktree, err := storage.repo.Worktree()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = worktree.AddWithOptions(&git.AddOptions{
+		All: true,
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = worktree.Commit(msg, &git.CommitOptions{
+		All:     true,
+		Parents: []plumbing.Hash{head.Hash(), commit.Hash},
+		// merged state may be equal to current one
+		AllowEmptyCommits: true,
+	})
+	return errors.Wrap(err, "failed to commit merge")
 }
 
 func (storage *gitStorage) Commit(_ context.Context, msg string) error {
@@ -346,24 +545,20 @@ func (storage *gitStorage) Rollback(_ context.Context) error {
 	return errors.Wrap(err, "failed to rollback storage")
 }
 
-func (storage *gitStorage) listEntriesRecursively(p string) ([]appstorage.Entry, error) {
-	dirEntries, err := os.ReadDir(p)
-	if err != nil {
-		return nil, errors.Wrapf(err, "failed to read dir %s", p)
-	}
-
+func (storage *gitStorage) listTreeEntriesRecursively(tree *object.Tree) ([]appstorage.Entry, error) {
 	//nolint:prealloc
 	var entries []appstorage.Entry
 
-	for _, entry := range dirEntries {
+	for _, entry := range tree.Entries {
 		var children []appstorage.Entry
 
-		if commonstrings.HasPrefix(entry.Name(), storagePaths) {
-			continue
-		}
+		if !entry.Mode.IsFile() {
+			subtree, err := tree.Tree(entry.Name)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to get subtree %s", entry.Name)
+			}
 
-		if entry.IsDir() {
-			children, err = storage.listEntriesRecursively(path.Join(p, entry.Name()))
+			children, err = storage.listTreeEntriesRecursively(subtree)
 			if err != nil {
 				return nil, err
 			}
@@ -375,7 +570,7 @@ func (storage *gitStorage) listEntriesRecursively(p string) ([]appstorage.Entry,
 		}
 
 		entries = append(entries, appstorage.Entry{
-			Name:     entry.Name(),
+			Name:     entry.Name,
 			Children: children,
 		})
 	}
@@ -413,6 +608,30 @@ func (storage *gitStorage) getLastCommit(p maybe.Maybe[string]) (*object.Commit,
 	return next, nil
 }
 
+func (storage *gitStorage) commitByRevision(revision string) (*object.Commit, error) {
+	hash, err := storage.repo.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to resolve revision %s", revision)
+	}
+
+	commit, err := storage.repo.CommitObject(*hash)
+	return commit, errors.Wrapf(err, "failed to get commit %s", hash)
+}
+
+func fileFromCommit(commit *object.Commit, p string) (maybe.Maybe[[]byte], error) {
+	file, err := commit.File(p)
+	if err != nil {
+		return maybe.Maybe[[]byte]{}, errors.Wrap(err, "failed to get file from commit")
+	}
+
+	content, err := file.Contents()
+	if err != nil {
+		return maybe.Maybe[[]byte]{}, errors.Wrap(err, "failed to get file content from commit")
+	}
+
+	return maybe.NewJust([]byte(content)), nil
+}
+
 func defaultProgress(ctx context.Context) progress.Progress {
 	return progress.FromCtx(ctx).Alter(
 		progress.WithDescription("Manipulating with Git"),
@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/pkg/errors"
+
+	"github.com/UsingCoding/gostore/internal/common/maybe"
+	appstorage "github.com/UsingCoding/gostore/internal/gostore/app/storage"
+)
+
+const (
+	defaultSSHUser = "git"
+	// defaultTokenUser sent with token since hosts check only password of token auth
+	defaultTokenUser = "x-access-token"
+)
+
+// gitAuthMethod converts credentials to go-git auth. Nil auth method lets go-git pick up auth implicitly
+func gitAuthMethod(ctx context.Context, provider appstorage.AuthProvider) (transport.AuthMethod, error) {
+	if provider == nil {
+		return nil, nil
+	}
+
+	a, err := provider.Auth(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get storage auth")
+	}
+
+	auth, ok := maybe.JustValid(a)
+	if !ok {
+		return nil, nil
+	}
+
+	switch auth.Type {
+	case appstorage.SSHKeyAuth:
+		keys, err2 := ssh.NewPublicKeysFromFile(sshUser(auth), auth.KeyPath, string(auth.Secret))
+		return keys, errors.Wrapf(err2, "failed to load ssh key %s", auth.KeyPath)
+	case appstorage.SSHAgentAuth:
+		agentAuth, err2 := ssh.NewSSHAgentAuth(sshUser(auth))
+		return agentAuth, errors.Wrap(err2, "failed to connect to ssh-agent")
+	case appstorage.BasicAuth:
+		return &http.BasicAuth{
+			Username: auth.User,
+			Password: string(auth.Secret),
+		}, nil
+	case appstorage.TokenAuth:
+		// token passed as basic auth password, bearer tokens are rejected by git hosts
+		user := auth.User
+		if user == "" {
+			user = defaultTokenUser
+		}
+		return &http.BasicAuth{
+			Username: user,
+			Password: string(auth.Secret),
+		}, nil
+	default:
+		return nil, errors.Errorf("unknown auth type %s", auth.Type)
+	}
+}
+
+func sshUser(auth appstorage.Auth) string {
+	if auth.User == "" {
+		return defaultSSHUser
+	}
+	return auth.User
+}
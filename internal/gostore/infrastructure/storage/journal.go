@@ -0,0 +1,352 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"os/user"
+	stdslices "slices"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/UsingCoding/gostore/internal/common/maybe"
+	appstorage "github.com/UsingCoding/gostore/internal/gostore/app/storage"
+)
+
+const (
+	journalObjectsPrefix   = "objects/"
+	journalRevisionsPrefix = "revisions/"
+	journalHeadKey         = "HEAD"
+
+	headRevision = "HEAD"
+	// minimal length of revision prefix to resolve
+	minRevisionPrefixLen = 4
+)
+
+// journalBlobs keeps journal records for storage without own history
+type journalBlobs interface {
+	read(ctx context.Context, key string) (maybe.Maybe[[]byte], error)
+	write(ctx context.Context, key string, data []byte) error
+	exists(ctx context.Context, key string) (bool, error)
+	// keys returns keys with prefix
+	keys(ctx context.Context, prefix string) ([]string, error)
+	// writeHead points HEAD to revision only if it still points to expected one
+	writeHead(ctx context.Context, expected maybe.Maybe[string], revision string) error
+}
+
+// journal records commits as snapshots of objects content hashes, contents kept in objects by hash
+type journal struct {
+	blobs journalBlobs
+}
+
+// journalRevision is journal record of commit
+type journalRevision struct {
+	ID      string    `json:"id"`
+	Parent  string    `json:"parent,omitempty"`
+	Author  string    `json:"author"`
+	Time    time.Time `json:"time"`
+	Message string    `json:"message"`
+	// Tree maps object path to content hash
+	Tree map[string]string `json:"tree"`
+}
+
+func (j journal) commit(ctx context.Context, tree map[string]string, msg string) error {
+	head, err := j.revision(ctx, headRevision)
+	if err != nil {
+		return err
+	}
+
+	return j.commitOnto(ctx, head, tree, msg)
+}
+
+// commitOnto records tree as child of head, HEAD is not moved if it no longer points to head
+func (j journal) commitOnto(
+	ctx context.Context,
+	head maybe.Maybe[journalRevision],
+	tree map[string]string,
+	msg string,
+) error {
+	var parent maybe.Maybe[string]
+	if h, ok := maybe.JustValid(head); ok {
+		if maps.Equal(h.Tree, tree) {
+			// nothing changed
+			return nil
+		}
+		parent = maybe.NewJust(h.ID)
+	}
+
+	rev := journalRevision{
+		Parent:  maybe.Just(parent),
+		Author:  journalAuthor(),
+		Time:    time.Now(),
+		Message: msg,
+		Tree:    tree,
+	}
+
+	data, err := json.Marshal(rev)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal revision")
+	}
+
+	rev.ID = contentHash(data)
+
+	data, err = json.Marshal(rev)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal revision")
+	}
+
+	err = j.blobs.write(ctx, journalRevisionsPrefix+rev.ID, data)
+	if err != nil {
+		return errors.Wrap(err, "failed to write revision")
+	}
+
+	// moving HEAD is the commit point, revision without HEAD pointing to it is ignored
+	err = j.blobs.writeHead(ctx, parent, rev.ID)
+	return errors.Wrap(err, "failed to update HEAD")
+}
+
+// putObject stores content if it not stored yet and returns its hash
+func (j journal) putObject(ctx context.Context, data []byte) (string, error) {
+	hash := contentHash(data)
+
+	e, err := j.blobs.exists(ctx, journalObjectsPrefix+hash)
+	if err != nil || e {
+		return hash, err
+	}
+
+	return hash, j.blobs.write(ctx, journalObjectsPrefix+hash, data)
+}
+
+func (j journal) object(ctx context.Context, hash string) ([]byte, error) {
+	data, err := j.blobs.read(ctx, journalObjectsPrefix+hash)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read object %s", hash)
+	}
+
+	if !maybe.Valid(data) {
+		return nil, errors.Errorf("object %s not found", hash)
+	}
+
+	return maybe.Just(data), nil
+}
+
+// tree returns snapshot of revision, empty for storage without commits
+func (j journal) tree(ctx context.Context, revision string) (journalRevision, error) {
+	r, err := j.revision(ctx, revision)
+	if err != nil {
+		return journalRevision{}, err
+	}
+
+	return maybe.MapNone(r, func() journalRevision {
+		return journalRevision{Tree: map[string]string{}}
+	}), nil
+}
+
+func (j journal) getAt(ctx context.Context, p, revision string) (maybe.Maybe[[]byte], error) {
+	r, err := j.revision(ctx, revision)
+	if err != nil {
+		return maybe.Maybe[[]byte]{}, err
+	}
+
+	rev, ok := maybe.JustValid(r)
+	if !ok {
+		// no commits in storage
+		return maybe.Maybe[[]byte]{}, nil
+	}
+
+	hash, ok := rev.Tree[p]
+	if !ok {
+		return maybe.Maybe[[]byte]{}, nil
+	}
+
+	data, err := j.object(ctx, hash)
+	if err != nil {
+		return maybe.Maybe[[]byte]{}, err
+	}
+
+	return maybe.NewJust(data), nil
+}
+
+func (j journal) history(ctx context.Context, p string) ([]appstorage.Revision, error) {
+	r, err := j.revision(ctx, headRevision)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []appstorage.Revision
+	for rev, ok := maybe.JustValid(r); ok; {
+		var parent journalRevision
+		if rev.Parent != "" {
+			parent, err = j.readRevision(ctx, rev.Parent)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if rev.Tree[p] != parent.Tree[p] {
+			res = append(res, appstorage.Revision{
+				ID:      rev.ID,
+				Author:  rev.Author,
+				Time:    rev.Time,
+				Message: rev.Message,
+			})
+		}
+
+		rev, ok = parent, rev.Parent != ""
+	}
+
+	return res, nil
+}
+
+func (j journal) resolve(ctx context.Context, revision string) (maybe.Maybe[string], error) {
+	r, err := j.revision(ctx, revision)
+	if err != nil {
+		return maybe.Maybe[string]{}, err
+	}
+
+	return maybe.Map(r, func(r journalRevision) string {
+		return r.ID
+	}), nil
+}
+
+func (j journal) listAt(ctx context.Context, p, revision string) (appstorage.Tree, error) {
+	r, err := j.revision(ctx, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	rev, ok := maybe.JustValid(r)
+	if !ok {
+		return nil, errors.Errorf("revision %s not found", revision)
+	}
+
+	var paths []string
+	for objectPath := range rev.Tree {
+		if p == "" {
+			paths = append(paths, objectPath)
+			continue
+		}
+
+		if rel, found := strings.CutPrefix(objectPath, p+"/"); found {
+			paths = append(paths, rel)
+		}
+	}
+
+	return treeFromPaths(paths), nil
+}
+
+// revision resolves HEAD, full revision ID or its unique prefix
+func (j journal) revision(ctx context.Context, revision string) (maybe.Maybe[journalRevision], error) {
+	if revision == headRevision {
+		data, err := j.blobs.read(ctx, journalHeadKey)
+		if err != nil {
+			return maybe.Maybe[journalRevision]{}, errors.Wrap(err, "failed to read HEAD")
+		}
+
+		head, ok := maybe.JustValid(data)
+		if !ok {
+			return maybe.Maybe[journalRevision]{}, nil
+		}
+
+		revision = strings.TrimSpace(string(head))
+	}
+
+	if !isRevisionPrefix(revision) {
+		// not a revision, e.g. path to secret
+		return maybe.Maybe[journalRevision]{}, nil
+	}
+
+	keys, err := j.blobs.keys(ctx, journalRevisionsPrefix+revision)
+	if err != nil {
+		return maybe.Maybe[journalRevision]{}, errors.Wrap(err, "failed to list revisions")
+	}
+
+	switch len(keys) {
+	case 0:
+		return maybe.Maybe[journalRevision]{}, nil
+	case 1:
+		rev, err2 := j.readRevision(ctx, strings.TrimPrefix(keys[0], journalRevisionsPrefix))
+		if err2 != nil {
+			return maybe.Maybe[journalRevision]{}, err2
+		}
+		return maybe.NewJust(rev), nil
+	default:
+		return maybe.Maybe[journalRevision]{}, errors.Errorf("ambiguous revision %s", revision)
+	}
+}
+
+func (j journal) readRevision(ctx context.Context, id string) (journalRevision, error) {
+	data, err := j.blobs.read(ctx, journalRevisionsPrefix+id)
+	if err != nil {
+		return journalRevision{}, errors.Wrapf(err, "failed to read revision %s", id)
+	}
+
+	if !maybe.Valid(data) {
+		return journalRevision{}, errors.Errorf("revision %s not found", id)
+	}
+
+	var rev journalRevision
+	err = json.Unmarshal(maybe.Just(data), &rev)
+	return rev, errors.Wrapf(err, "failed to unmarshal revision %s", id)
+}
+
+// treeFromPaths builds tree from file paths
+func treeFromPaths(paths []string) appstorage.Tree {
+	stdslices.Sort(paths)
+
+	var tree appstorage.Tree
+	for _, p := range paths {
+		tree = addTreePath(tree, strings.Split(p, "/"))
+	}
+	return tree
+}
+
+func addTreePath(entries []appstorage.Entry, parts []string) []appstorage.Entry {
+	i := stdslices.IndexFunc(entries, func(e appstorage.Entry) bool {
+		return e.Name == parts[0]
+	})
+	if i == -1 {
+		entries = append(entries, appstorage.Entry{Name: parts[0]})
+		i = len(entries) - 1
+	}
+
+	if len(parts) > 1 {
+		entries[i].Children = addTreePath(entries[i].Children, parts[1:])
+	}
+
+	return entries
+}
+
+// isRevisionPrefix reports whether s may be prefix of content hash, so it is safe to use as key prefix
+func isRevisionPrefix(s string) bool {
+	if len(s) < minRevisionPrefixLen || len(s) > hex.EncodedLen(sha256.Size) {
+		return false
+	}
+
+	return strings.Trim(s, "0123456789abcdef") == ""
+}
+
+func contentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func journalAuthor() string {
+	username := "unknown"
+	if u, err := user.Current(); err == nil {
+		username = u.Username
+	}
+
+	host, err := os.Hostname()
+	if err != nil {
+		return username
+	}
+
+	return fmt.Sprintf("%s@%s", username, host)
+}
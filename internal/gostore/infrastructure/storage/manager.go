@@ -24,10 +24,11 @@ func NewManager() storage.Manager {
 type manager struct{}
 
 func (m *manager) Init(
-	_ context.Context,
+	ctx context.Context,
 	p string,
 	remote maybe.Maybe[string],
 	t storage.Type,
+	auth storage.AuthProvider,
 ) (storage.Storage, error) {
 	ok, err := exists(p)
 	if err != nil {
@@ -52,13 +53,25 @@ func (m *manager) Init(
 
 	switch t {
 	case storage.GITType:
-		return m.initGitStorage(p, remote)
+		return m.initGitStorage(p, remote, auth)
+	case storage.FSType:
+		if maybe.Valid(remote) {
+			return nil, errors.Errorf("%s storage does not support remotes", t)
+		}
+		return initFSStorage(p)
+	case storage.S3Type:
+		return initS3Storage(ctx, p, remote, auth)
 	default:
 		return nil, errors.Errorf("unsupported storage type %s", t)
 	}
 }
 
-func (m *manager) Clone(ctx context.Context, p, remote string, t storage.Type) (storage.Storage, error) {
+func (m *manager) Clone(
+	ctx context.Context,
+	p, remote string,
+	t storage.Type,
+	auth storage.AuthProvider,
+) (storage.Storage, error) {
 	ok, err := exists(p)
 	if err != nil {
 		return nil, err
@@ -82,13 +95,15 @@ func (m *manager) Clone(ctx context.Context, p, remote string, t storage.Type) (
 
 	switch t {
 	case storage.GITType:
-		return m.cloneGitStorage(ctx, p, remote)
+		return m.cloneGitStorage(ctx, p, remote, auth)
+	case storage.S3Type:
+		return cloneS3Storage(ctx, p, remote, auth)
 	default:
 		return nil, errors.Errorf("unsupported storage type %s", t)
 	}
 }
 
-func (m *manager) Use(_ context.Context, p string) (storage.Storage, error) {
+func (m *manager) Use(_ context.Context, p string, auth storage.AuthProvider) (storage.Storage, error) {
 	ok, err := exists(p)
 	if err != nil {
 		return nil, err
@@ -98,6 +113,24 @@ func (m *manager) Use(_ context.Context, p string) (storage.Storage, error) {
 		return nil, errors.Errorf("path %s not exists", p)
 	}
 
+	isFS, err := exists(path.Join(p, fsJournalDir))
+	if err != nil {
+		return nil, err
+	}
+
+	if isFS {
+		return newFSStorage(p), nil
+	}
+
+	isS3, err := exists(path.Join(p, s3ConfigFile))
+	if err != nil {
+		return nil, err
+	}
+
+	if isS3 {
+		return openS3Storage(p, auth)
+	}
+
 	repo, err := git.PlainOpen(p)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to open repo")
@@ -106,12 +139,13 @@ func (m *manager) Use(_ context.Context, p string) (storage.Storage, error) {
 	g := &gitStorage{
 		repo:    repo,
 		repoDir: p,
+		auth:    auth,
 	}
 	return newSyncGit(g), nil
 }
 
 func (m *manager) Remove(ctx context.Context, p string) error {
-	_, err := m.Use(ctx, p)
+	_, err := m.Use(ctx, p, storage.StaticAuth(maybe.Maybe[storage.Auth]{}))
 	if err != nil {
 		return err
 	}
@@ -120,7 +154,11 @@ func (m *manager) Remove(ctx context.Context, p string) error {
 	return errors.Wrapf(err, "failed to remote storage at %s", p)
 }
 
-func (m *manager) initGitStorage(p string, remote maybe.Maybe[string]) (storage.Storage, error) {
+func (m *manager) initGitStorage(
+	p string,
+	remote maybe.Maybe[string],
+	auth storage.AuthProvider,
+) (storage.Storage, error) {
 	repo, err := git.PlainInit(p, false)
 	if err != nil {
 		return nil, errors.New("failed to init store repo")
@@ -139,18 +177,28 @@ func (m *manager) initGitStorage(p string, remote maybe.Maybe[string]) (storage.
 	g := &gitStorage{
 		repo:    repo,
 		repoDir: p,
+		auth:    auth,
 	}
 	return newSyncGit(g), nil
 }
 
-func (m *manager) cloneGitStorage(ctx context.Context, p, remote string) (storage.Storage, error) {
+func (m *manager) cloneGitStorage(
+	ctx context.Context,
+	p, remote string,
+	auth storage.AuthProvider,
+) (storage.Storage, error) {
+	authMethod, err := gitAuthMethod(ctx, auth)
+	if err != nil {
+		return nil, err
+	}
+
 	repo, err := git.PlainCloneContext(
 		ctx,
 		p,
 		false,
 		&git.CloneOptions{
 			URL:        remote,
-			Auth:       nil, // use ssh-agent as auth
+			Auth:       authMethod,
 			RemoteName: remoteName,
 		},
 	)
@@ -161,6 +209,7 @@ func (m *manager) cloneGitStorage(ctx context.Context, p, remote string) (storag
 	g := &gitStorage{
 		repo:    repo,
 		repoDir: p,
+		auth:    auth,
 	}
 	return newSyncGit(g), nil
 }
@@ -0,0 +1,834 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"maps"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/pkg/errors"
+
+	"github.com/UsingCoding/gostore/internal/common/maybe"
+	appstorage "github.com/UsingCoding/gostore/internal/gostore/app/storage"
+)
+
+const (
+	// s3ConfigFile keeps bucket location in local store directory
+	s3ConfigFile = ".gostore-s3.json"
+	// s3StageDir keeps uncommitted changes of client in local store directory
+	s3StageDir        = ".gostore-s3-stage"
+	s3StageIndexFile  = "index.json"
+	s3StageObjectsDir = "objects"
+
+	s3JournalPrefix = "journal/"
+
+	// s3CommitAttempts limits rebases on HEAD moved by other clients during commit
+	s3CommitAttempts = 5
+
+	// s3Scheme is remote scheme for bucket accessed over https
+	s3Scheme = "s3"
+	// s3HTTPScheme is remote scheme for bucket accessed over plain http, like local MinIO
+	s3HTTPScheme = "s3+http"
+)
+
+// s3Storage keeps secrets in S3-compatible bucket shared by all store users.
+// Bucket contains only committed state: journal of revisions with content addressed objects under store prefix.
+// Uncommitted changes staged in local store directory, so other clients never see them and rollback discards only them
+type s3Storage struct {
+	dir     string
+	remote  s3Remote
+	auth    appstorage.AuthProvider
+	journal journal
+
+	// mu guards stage since storage accessed concurrently
+	mu    sync.Mutex
+	stage s3Stage
+	// base is tree of stage base revision
+	base map[string]string
+	// tree is base with staged changes applied, nil until stage loaded
+	tree map[string]string
+
+	clientMu sync.Mutex
+	client   *minio.Client
+}
+
+// s3Stage is index of changes staged by client
+type s3Stage struct {
+	// Base is revision changes staged on top of, empty for store without commits
+	Base string `json:"base"`
+	// Changes maps path to staged content hash, empty hash marks removed object
+	Changes map[string]string `json:"changes"`
+}
+
+// s3Remote is location of store in bucket parsed from s3://<endpoint>/<bucket>[/<prefix>]
+type s3Remote struct {
+	Endpoint string
+	Secure   bool
+	Bucket   string
+	// Prefix of store objects, empty or ends with slash
+	Prefix string
+}
+
+type s3Config struct {
+	Remote string `json:"remote"`
+}
+
+func newS3Storage(dir string, remote s3Remote, auth appstorage.AuthProvider) *s3Storage {
+	s := &s3Storage{
+		dir:    dir,
+		remote: remote,
+		auth:   auth,
+	}
+	s.journal = journal{blobs: s3JournalBlobs{storage: s}}
+	return s
+}
+
+func initS3Storage(
+	ctx context.Context,
+	p string,
+	remote maybe.Maybe[string],
+	auth appstorage.AuthProvider,
+) (*s3Storage, error) {
+	r, ok := maybe.JustValid(remote)
+	if !ok {
+		return nil, errors.New("s3 storage requires remote with bucket location")
+	}
+
+	s, err := createS3Storage(p, r, auth)
+	if err != nil {
+		return nil, err
+	}
+
+	head, err := s.journal.resolve(ctx, headRevision)
+	if err != nil {
+		return nil, err
+	}
+	if maybe.Valid(head) {
+		return nil, errors.Errorf("store already exists in %s, clone it instead", r)
+	}
+
+	return s, nil
+}
+
+func cloneS3Storage(
+	ctx context.Context,
+	p, remote string,
+	auth appstorage.AuthProvider,
+) (*s3Storage, error) {
+	s, err := createS3Storage(p, remote, auth)
+	if err != nil {
+		return nil, err
+	}
+
+	head, err := s.journal.resolve(ctx, headRevision)
+	if err != nil {
+		return nil, err
+	}
+	if !maybe.Valid(head) {
+		return nil, errors.Errorf("no store found in %s", remote)
+	}
+
+	return s, nil
+}
+
+// createS3Storage writes bucket location to local store directory
+func createS3Storage(p, remote string, auth appstorage.AuthProvider) (*s3Storage, error) {
+	r, err := parseS3Remote(remote)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(s3Config{Remote: remote})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal s3 storage config")
+	}
+
+	err = os.MkdirAll(p, 0o755)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create store dir %s", p)
+	}
+
+	//nolint:gosec
+	err = os.WriteFile(path.Join(p, s3ConfigFile), data, 0o644)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to write s3 storage config")
+	}
+
+	return newS3Storage(p, r, auth), nil
+}
+
+func openS3Storage(p string, auth appstorage.AuthProvider) (*s3Storage, error) {
+	data, err := os.ReadFile(path.Join(p, s3ConfigFile))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read s3 storage config")
+	}
+
+	var c s3Config
+	err = json.Unmarshal(data, &c)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal s3 storage config")
+	}
+
+	r, err := parseS3Remote(c.Remote)
+	if err != nil {
+		return nil, err
+	}
+
+	return newS3Storage(p, r, auth), nil
+}
+
+func parseS3Remote(remote string) (s3Remote, error) {
+	u, err := url.Parse(remote)
+	if err != nil {
+		return s3Remote{}, errors.Wrapf(err, "failed to parse s3 remote %s", remote)
+	}
+
+	var secure bool
+	switch u.Scheme {
+	case s3Scheme:
+		secure = true
+	case s3HTTPScheme:
+		secure = false
+	default:
+		return s3Remote{}, errors.Errorf("unknown s3 remote scheme %s, expected %s or %s", u.Scheme, s3Scheme, s3HTTPScheme)
+	}
+
+	bucket, prefix, _ := strings.Cut(strings.Trim(u.Path, "/"), "/")
+	if u.Host == "" || bucket == "" {
+		return s3Remote{}, errors.Errorf("s3 remote should be %s://<endpoint>/<bucket>[/<prefix>]: %s", s3Scheme, remote)
+	}
+
+	if prefix != "" {
+		prefix += "/"
+	}
+
+	return s3Remote{
+		Endpoint: u.Host,
+		Secure:   secure,
+		Bucket:   bucket,
+		Prefix:   prefix,
+	}, nil
+}
+
+func (storage *s3Storage) Store(ctx context.Context, p string, data []byte) error {
+	if !relativePathForStorage(p) {
+		return errors.Errorf("path to secret is not local: %s", p)
+	}
+
+	storage.mu.Lock()
+	defer storage.mu.Unlock()
+
+	err := storage.loadStage(ctx)
+	if err != nil {
+		return err
+	}
+
+	hash := contentHash(data)
+
+	err = storage.writeStagedObject(hash, data)
+	if err != nil {
+		return errors.Wrapf(err, "failed to store %s", p)
+	}
+
+	return storage.stageChanges(map[string]string{p: hash})
+}
+
+func (storage *s3Storage) Remove(ctx context.Context, p string) error {
+	if !relativePathForStorage(p) {
+		return errors.Errorf("path to secret is not local: %s", p)
+	}
+
+	storage.mu.Lock()
+	defer storage.mu.Unlock()
+
+	err := storage.loadStage(ctx)
+	if err != nil {
+		return err
+	}
+
+	changes := map[string]string{}
+	// path may be object itself or directory of objects
+	for objectPath := range storage.tree {
+		if objectPath == p || strings.HasPrefix(objectPath, p+"/") {
+			changes[objectPath] = ""
+		}
+	}
+
+	return storage.stageChanges(changes)
+}
+
+func (storage *s3Storage) Copy(ctx context.Context, src, dst string) error {
+	for _, p := range []string{src, dst} {
+		if !relativePathForStorage(p) {
+			return errors.Errorf("path to secret is not local: %s", p)
+		}
+	}
+
+	storage.mu.Lock()
+	defer storage.mu.Unlock()
+
+	err := storage.loadStage(ctx)
+	if err != nil {
+		return err
+	}
+
+	changes := map[string]string{}
+	for objectPath, hash := range storage.tree {
+		if objectPath == src {
+			changes[dst] = hash
+			continue
+		}
+
+		if rel, found := strings.CutPrefix(objectPath, src+"/"); found {
+			changes[dst+"/"+rel] = hash
+		}
+	}
+
+	if len(changes) == 0 {
+		return errors.Errorf("%s not found in storage", src)
+	}
+
+	return storage.stageChanges(changes)
+}
+
+func (storage *s3Storage) Move(ctx context.Context, src, dst string) error {
+	err := storage.Copy(ctx, src, dst)
+	if err != nil {
+		return err
+	}
+
+	return storage.Remove(ctx, src)
+}
+
+func (storage *s3Storage) Get(ctx context.Context, p string) (maybe.Maybe[[]byte], error) {
+	if !relativePathForStorage(p) {
+		return maybe.Maybe[[]byte]{}, errors.Errorf("path to secret is not local: %s", p)
+	}
+
+	storage.mu.Lock()
+	err := storage.loadStage(ctx)
+	hash, ok := storage.tree[p]
+	storage.mu.Unlock()
+	if err != nil || !ok {
+		return maybe.Maybe[[]byte]{}, err
+	}
+
+	data, err := storage.object(ctx, hash)
+	if err != nil {
+		return maybe.Maybe[[]byte]{}, errors.Wrapf(err, "failed to get %s", p)
+	}
+
+	return maybe.NewJust(data), nil
+}
+
+func (storage *s3Storage) GetLatest(ctx context.Context, p string) (maybe.Maybe[[]byte], error) {
+	return storage.GetAt(ctx, p, headRevision)
+}
+
+func (storage *s3Storage) GetAt(ctx context.Context, p, revision string) (maybe.Maybe[[]byte], error) {
+	if !relativePathForStorage(p) {
+		return maybe.Maybe[[]byte]{}, errors.Errorf("path to secret is not local: %s", p)
+	}
+
+	return storage.journal.getAt(ctx, p, revision)
+}
+
+func (storage *s3Storage) History(ctx context.Context, p string) ([]appstorage.Revision, error) {
+	if !relativePathForStorage(p) {
+		return nil, errors.Errorf("path to secret is not local: %s", p)
+	}
+
+	return storage.journal.history(ctx, p)
+}
+
+func (storage *s3Storage) ResolveRevision(ctx context.Context, revision string) (maybe.Maybe[string], error) {
+	return storage.journal.resolve(ctx, revision)
+}
+
+func (storage *s3Storage) List(ctx context.Context, p string) (appstorage.Tree, error) {
+	if p != "" && !relativePathForStorage(p) {
+		return nil, errors.Errorf("path to list is not local: %s", p)
+	}
+
+	storage.mu.Lock()
+	defer storage.mu.Unlock()
+
+	err := storage.loadStage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for objectPath := range storage.tree {
+		if p == "" {
+			paths = append(paths, objectPath)
+			continue
+		}
+
+		if rel, found := strings.CutPrefix(objectPath, p+"/"); found {
+			paths = append(paths, rel)
+		}
+	}
+
+	return treeFromPaths(paths), nil
+}
+
+func (storage *s3Storage) ListAt(ctx context.Context, p, revision string) (appstorage.Tree, error) {
+	if p != "" && !relativePathForStorage(p) {
+		return nil, errors.Errorf("path to list is not local: %s", p)
+	}
+
+	return storage.journal.listAt(ctx, p, revision)
+}
+
+func (storage *s3Storage) AddRemote(context.Context, string, string) error {
+	return errors.New("s3 storage is shared through bucket and does not support remotes")
+}
+
+// Push does nothing since commits already written to bucket
+func (storage *s3Storage) Push(context.Context) error {
+	return nil
+}
+
+// Fetch returns nothing to merge since bucket is the only copy of storage
+func (storage *s3Storage) Fetch(context.Context) (maybe.Maybe[string], error) {
+	return maybe.Maybe[string]{}, nil
+}
+
+func (storage *s3Storage) MergeBase(context.Context, string, string) (maybe.Maybe[string], error) {
+	return maybe.Maybe[string]{}, errS3NoMerge
+}
+
+func (storage *s3Storage) FastForward(context.Context, string) error {
+	return errS3NoMerge
+}
+
+func (storage *s3Storage) Merge(context.Context, string, string) error {
+	return errS3NoMerge
+}
+
+// Commit uploads staged objects and records them on top of current HEAD.
+// Objects changed by other clients since staging started are kept, commit fails only when same object changed
+func (storage *s3Storage) Commit(ctx context.Context, msg string) error {
+	storage.mu.Lock()
+	defer storage.mu.Unlock()
+
+	err := storage.loadStage(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(storage.stage.Changes) == 0 {
+		return nil
+	}
+
+	for p, hash := range storage.stage.Changes {
+		if hash == "" {
+			continue
+		}
+
+		data, err2 := storage.object(ctx, hash)
+		if err2 != nil {
+			return errors.Wrapf(err2, "failed to read staged %s", p)
+		}
+
+		_, err2 = storage.journal.putObject(ctx, data)
+		if err2 != nil {
+			return errors.Wrapf(err2, "failed to write object for %s", p)
+		}
+	}
+
+	for attempt := 1; ; attempt++ {
+		head, err2 := storage.journal.revision(ctx, headRevision)
+		if err2 != nil {
+			return err2
+		}
+
+		tree, err2 := storage.rebase(head)
+		if err2 != nil {
+			return err2
+		}
+
+		err2 = storage.journal.commitOnto(ctx, head, tree, msg)
+		if errors.Is(err2, errS3ConcurrentCommit) && attempt < s3CommitAttempts {
+			// other client moved HEAD meanwhile, rebase on it again
+			continue
+		}
+		if err2 != nil {
+			return err2
+		}
+
+		break
+	}
+
+	return storage.resetStage()
+}
+
+// Rollback discards changes staged by this client, bucket is not touched
+func (storage *s3Storage) Rollback(context.Context) error {
+	storage.mu.Lock()
+	defer storage.mu.Unlock()
+
+	return storage.resetStage()
+}
+
+// loadStage reads staged changes of client or starts staging on top of current HEAD
+func (storage *s3Storage) loadStage(ctx context.Context) error {
+	if storage.tree != nil {
+		return nil
+	}
+
+	stage := s3Stage{
+		Base:    headRevision,
+		Changes: map[string]string{},
+	}
+
+	data, err := os.ReadFile(path.Join(storage.dir, s3StageDir, s3StageIndexFile))
+	switch {
+	case err == nil:
+		err = json.Unmarshal(data, &stage)
+		if err != nil {
+			return errors.Wrap(err, "failed to unmarshal s3 stage index")
+		}
+	case !os.IsNotExist(err):
+		return errors.Wrap(err, "failed to read s3 stage index")
+	}
+
+	base, err := storage.journal.tree(ctx, stage.Base)
+	if err != nil {
+		return err
+	}
+	stage.Base = base.ID
+
+	tree := maps.Clone(base.Tree)
+	if tree == nil {
+		tree = map[string]string{}
+	}
+	for p, hash := range stage.Changes {
+		if hash == "" {
+			delete(tree, p)
+			continue
+		}
+		tree[p] = hash
+	}
+
+	storage.stage = stage
+	storage.base = base.Tree
+	storage.tree = tree
+
+	return nil
+}
+
+// stageChanges applies changes of objects hashes, empty hash removes object
+func (storage *s3Storage) stageChanges(changes map[string]string) error {
+	for p, hash := range changes {
+		if baseHash, ok := storage.base[p]; (ok && baseHash == hash) || (!ok && hash == "") {
+			// object returned to base state
+			delete(storage.stage.Changes, p)
+		} else {
+			storage.stage.Changes[p] = hash
+		}
+
+		if hash == "" {
+			delete(storage.tree, p)
+		} else {
+			storage.tree[p] = hash
+		}
+	}
+
+	data, err := json.Marshal(storage.stage)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal s3 stage index")
+	}
+
+	err = os.MkdirAll(path.Join(storage.dir, s3StageDir), 0o700)
+	if err != nil {
+		return errors.Wrap(err, "failed to create s3 stage dir")
+	}
+
+	err = writeFileAtomic(path.Join(storage.dir, s3StageDir, s3StageIndexFile), data)
+	return errors.Wrap(err, "failed to write s3 stage index")
+}
+
+// rebase applies staged changes to head tree, fails if object changed both in stage and by other client
+func (storage *s3Storage) rebase(head maybe.Maybe[journalRevision]) (map[string]string, error) {
+	headRev := maybe.MapNone(head, func() journalRevision {
+		return journalRevision{Tree: map[string]string{}}
+	})
+
+	if headRev.ID == storage.stage.Base {
+		return storage.tree, nil
+	}
+
+	tree := maps.Clone(headRev.Tree)
+	for p, hash := range storage.stage.Changes {
+		headHash := headRev.Tree[p]
+		if headHash != storage.base[p] && headHash != hash {
+			return nil, errors.Wrapf(errS3Conflict, "%s", p)
+		}
+
+		if hash == "" {
+			delete(tree, p)
+			continue
+		}
+		tree[p] = hash
+	}
+
+	return tree, nil
+}
+
+// resetStage removes staged changes, next access starts staging on top of actual HEAD
+func (storage *s3Storage) resetStage() error {
+	err := os.RemoveAll(path.Join(storage.dir, s3StageDir))
+	if err != nil {
+		return errors.Wrap(err, "failed to remove s3 stage")
+	}
+
+	storage.stage = s3Stage{}
+	storage.base = nil
+	storage.tree = nil
+
+	return nil
+}
+
+func (storage *s3Storage) writeStagedObject(hash string, data []byte) error {
+	dir := path.Join(storage.dir, s3StageDir, s3StageObjectsDir)
+
+	e, err := exists(path.Join(dir, hash))
+	if err != nil || e {
+		return err
+	}
+
+	err = os.MkdirAll(dir, 0o700)
+	if err != nil {
+		return errors.Wrap(err, "failed to create s3 stage objects dir")
+	}
+
+	return writeFileAtomic(path.Join(dir, hash), data)
+}
+
+// object reads content staged locally or committed to bucket
+func (storage *s3Storage) object(ctx context.Context, hash string) ([]byte, error) {
+	data, err := os.ReadFile(path.Join(storage.dir, s3StageDir, s3StageObjectsDir, hash))
+	if err == nil {
+		return data, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, errors.WithStack(err)
+	}
+
+	return storage.journal.object(ctx, hash)
+}
+
+func (storage *s3Storage) objectKeys(ctx context.Context, prefix string) ([]string, error) {
+	client, err := storage.s3Client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []string
+	for o := range client.ListObjects(ctx, storage.remote.Bucket, minio.ListObjectsOptions{
+		Prefix:    prefix,
+		Recursive: true,
+	}) {
+		if o.Err != nil {
+			return nil, errors.Wrap(o.Err, "failed to list objects")
+		}
+
+		res = append(res, o.Key)
+	}
+
+	return res, nil
+}
+
+func (storage *s3Storage) get(ctx context.Context, key string) (maybe.Maybe[[]byte], error) {
+	data, _, err := storage.getWithETag(ctx, key)
+	return data, err
+}
+
+func (storage *s3Storage) getWithETag(ctx context.Context, key string) (maybe.Maybe[[]byte], string, error) {
+	client, err := storage.s3Client(ctx)
+	if err != nil {
+		return maybe.Maybe[[]byte]{}, "", err
+	}
+
+	o, err := client.GetObject(ctx, storage.remote.Bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return maybe.Maybe[[]byte]{}, "", errors.WithStack(err)
+	}
+	defer o.Close()
+
+	// object fetched lazily, so missing object reported by first read
+	stat, err := o.Stat()
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == minio.NoSuchKey {
+			return maybe.Maybe[[]byte]{}, "", nil
+		}
+		return maybe.Maybe[[]byte]{}, "", errors.WithStack(err)
+	}
+
+	data, err := io.ReadAll(o)
+	if err != nil {
+		return maybe.Maybe[[]byte]{}, "", errors.WithStack(err)
+	}
+
+	return maybe.NewJust(data), stat.ETag, nil
+}
+
+func (storage *s3Storage) put(
+	ctx context.Context,
+	key string,
+	data []byte,
+	opts minio.PutObjectOptions,
+) (minio.UploadInfo, error) {
+	client, err := storage.s3Client(ctx)
+	if err != nil {
+		return minio.UploadInfo{}, err
+	}
+
+	info, err := client.PutObject(ctx, storage.remote.Bucket, key, bytes.NewReader(data), int64(len(data)), opts)
+	return info, errors.WithStack(err)
+}
+
+// s3Client creates client on first access to bucket, since credentials may require access to other stores.
+// Failed creation is not cached, so cancelled ctx of one caller does not break others
+func (storage *s3Storage) s3Client(ctx context.Context) (*minio.Client, error) {
+	storage.clientMu.Lock()
+	defer storage.clientMu.Unlock()
+
+	if storage.client != nil {
+		return storage.client, nil
+	}
+
+	creds, err := s3Credentials(ctx, storage.auth)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := minio.New(storage.remote.Endpoint, &minio.Options{
+		Creds:  creds,
+		Secure: storage.remote.Secure,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create s3 client")
+	}
+
+	storage.client = client
+	return client, nil
+}
+
+// s3Credentials uses basic auth as access key and secret key.
+// Without auth credentials taken from environment like AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY
+func s3Credentials(ctx context.Context, provider appstorage.AuthProvider) (*credentials.Credentials, error) {
+	var a maybe.Maybe[appstorage.Auth]
+	if provider != nil {
+		var err error
+		a, err = provider.Auth(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get storage auth")
+		}
+	}
+
+	auth, ok := maybe.JustValid(a)
+	if !ok {
+		return credentials.NewChainCredentials([]credentials.Provider{
+			&credentials.EnvAWS{},
+			&credentials.EnvMinio{},
+			&credentials.FileAWSCredentials{},
+		}), nil
+	}
+
+	if auth.Type != appstorage.BasicAuth {
+		return nil, errors.Errorf("s3 storage supports only %s auth with access key as user", appstorage.BasicAuth)
+	}
+
+	return credentials.NewStaticV4(auth.User, string(auth.Secret), ""), nil
+}
+
+// s3JournalBlobs keeps journal records as objects under store prefix
+type s3JournalBlobs struct {
+	storage *s3Storage
+}
+
+func (b s3JournalBlobs) read(ctx context.Context, key string) (maybe.Maybe[[]byte], error) {
+	return b.storage.get(ctx, b.key(key))
+}
+
+func (b s3JournalBlobs) write(ctx context.Context, key string, data []byte) error {
+	_, err := b.storage.put(ctx, b.key(key), data, minio.PutObjectOptions{})
+	return err
+}
+
+func (b s3JournalBlobs) exists(ctx context.Context, key string) (bool, error) {
+	client, err := b.storage.s3Client(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = client.StatObject(ctx, b.storage.remote.Bucket, b.key(key), minio.StatObjectOptions{})
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == minio.NoSuchKey {
+			return false, nil
+		}
+		return false, errors.WithStack(err)
+	}
+
+	return true, nil
+}
+
+func (b s3JournalBlobs) keys(ctx context.Context, prefix string) ([]string, error) {
+	keys, err := b.storage.objectKeys(ctx, b.key(prefix))
+	if err != nil {
+		return nil, err
+	}
+
+	for i, k := range keys {
+		keys[i] = strings.TrimPrefix(k, b.key(""))
+	}
+
+	return keys, nil
+}
+
+// writeHead uses conditional write so concurrent commits of store users do not overwrite each other
+func (b s3JournalBlobs) writeHead(ctx context.Context, expected maybe.Maybe[string], revision string) error {
+	opts := minio.PutObjectOptions{}
+
+	if e, ok := maybe.JustValid(expected); ok {
+		data, etag, err := b.storage.getWithETag(ctx, b.key(journalHeadKey))
+		if err != nil {
+			return err
+		}
+
+		if string(maybe.Just(data)) != e {
+			return errS3ConcurrentCommit
+		}
+
+		opts.SetMatchETag(etag)
+	} else {
+		opts.SetMatchETagExcept("*")
+	}
+
+	_, err := b.storage.put(ctx, b.key(journalHeadKey), []byte(revision), opts)
+	if minio.ToErrorResponse(errors.Cause(err)).Code == minio.PreconditionFailed {
+		return errS3ConcurrentCommit
+	}
+	return err
+}
+
+func (b s3JournalBlobs) key(k string) string {
+	return b.storage.remote.Prefix + s3JournalPrefix + k
+}
+
+var (
+	errS3NoMerge          = errors.New("s3 storage has no remote revisions to merge")
+	errS3ConcurrentCommit = errors.New("store changed concurrently, retry operation")
+	errS3Conflict         = errors.New("secret changed concurrently by other client, rollback and retry operation")
+)
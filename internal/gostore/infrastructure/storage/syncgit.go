@@ -26,3 +26,38 @@ func (g *syncGit) GetLatest(ctx context.Context, p string) (maybe.Maybe[[]byte],
 
 	return g.gitStorage.GetLatest(ctx, p)
 }
+
+func (g *syncGit) GetAt(ctx context.Context, p, revision string) (maybe.Maybe[[]byte], error) {
+	g.m.Lock()
+	defer g.m.Unlock()
+
+	return g.gitStorage.GetAt(ctx, p, revision)
+}
+
+func (g *syncGit) History(ctx context.Context, p string) ([]storage.Revision, error) {
+	g.m.Lock()
+	defer g.m.Unlock()
+
+	return g.gitStorage.History(ctx, p)
+}
+
+func (g *syncGit) ResolveRevision(ctx context.Context, revision string) (maybe.Maybe[string], error) {
+	g.m.Lock()
+	defer g.m.Unlock()
+
+	return g.gitStorage.ResolveRevision(ctx, revision)
+}
+
+func (g *syncGit) ListAt(ctx context.Context, p, revision string) (storage.Tree, error) {
+	g.m.Lock()
+	defer g.m.Unlock()
+
+	return g.gitStorage.ListAt(ctx, p, revision)
+}
+
+func (g *syncGit) MergeBase(ctx context.Context, revision1, revision2 string) (maybe.Maybe[string], error) {
+	g.m.Lock()
+	defer g.m.Unlock()
+
+	return g.gitStorage.MergeBase(ctx, revision1, revision2)
+}
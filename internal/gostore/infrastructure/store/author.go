@@ -0,0 +1,31 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/user"
+
+	"github.com/UsingCoding/gostore/internal/gostore/app/store"
+)
+
+// NewAuthorProvider returns provider of current user as user@host
+func NewAuthorProvider() store.AuthorProvider {
+	return authorProvider{}
+}
+
+type authorProvider struct{}
+
+func (authorProvider) Author(context.Context) string {
+	username := "unknown"
+	if u, err := user.Current(); err == nil {
+		username = u.Username
+	}
+
+	host, err := os.Hostname()
+	if err != nil {
+		return username
+	}
+
+	return fmt.Sprintf("%s@%s", username, host)
+}
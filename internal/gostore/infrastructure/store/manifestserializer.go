@@ -6,6 +6,7 @@ import (
 	"github.com/UsingCoding/fpgo/pkg/slices"
 	"github.com/pkg/errors"
 
+	"github.com/UsingCoding/gostore/internal/common/maybe"
 	"github.com/UsingCoding/gostore/internal/gostore/app/encryption"
 	"github.com/UsingCoding/gostore/internal/gostore/app/storage"
 	"github.com/UsingCoding/gostore/internal/gostore/app/store"
@@ -26,7 +27,14 @@ func (serializer manifestSerializer) Serialize(m store.Manifest) ([]byte, error)
 		Recipients: slices.Map(m.Recipients, func(r encryption.Recipient) string {
 			return string(r)
 		}),
-		Unpacked: m.Unpacked,
+		Unpacked:       m.Unpacked,
+		PasswordPolicy: serializePasswordPolicy(m.PasswordPolicy),
+		Scopes: slices.Map(m.Scopes, func(s store.RecipientsScope) recipientsScope {
+			return recipientsScope{
+				Path:       s.Path,
+				Recipients: slices.Map(s.Recipients, encryption.Recipient.String),
+			}
+		}),
 	})
 	return data, errors.Wrap(err, "failed to serialize manifest")
 }
@@ -48,14 +56,71 @@ func (serializer manifestSerializer) Deserialize(data []byte) (store.Manifest, e
 		Recipients: slices.Map(m.Recipients, func(r string) encryption.Recipient {
 			return encryption.Recipient(r)
 		}),
-		Unpacked: m.Unpacked,
+		Unpacked:       m.Unpacked,
+		PasswordPolicy: deserializePasswordPolicy(m.PasswordPolicy),
+		Scopes: slices.Map(m.Scopes, func(s recipientsScope) store.RecipientsScope {
+			return store.RecipientsScope{
+				Path: s.Path,
+				Recipients: slices.Map(s.Recipients, func(r string) encryption.Recipient {
+					return encryption.Recipient(r)
+				}),
+			}
+		}),
 	}, nil
 }
 
+func serializePasswordPolicy(p store.PasswordPolicy) *passwordPolicy {
+	res := passwordPolicy{
+		Length: maybe.ToPtr(p.Length),
+		Words:  maybe.ToPtr(p.Words),
+		Required: slices.Map(p.Required, func(c store.CharClass) string {
+			return string(c)
+		}),
+		ExcludeAmbiguous: p.ExcludeAmbiguous,
+	}
+
+	if res.Length == nil && res.Words == nil && len(res.Required) == 0 && !res.ExcludeAmbiguous {
+		// keep manifest without policy for stores with defaults
+		return nil
+	}
+
+	return &res
+}
+
+func deserializePasswordPolicy(p *passwordPolicy) store.PasswordPolicy {
+	if p == nil {
+		return store.PasswordPolicy{}
+	}
+
+	return store.PasswordPolicy{
+		Length: maybe.FromPtr(p.Length),
+		Words:  maybe.FromPtr(p.Words),
+		Required: slices.Map(p.Required, func(c string) store.CharClass {
+			return store.CharClass(c)
+		}),
+		ExcludeAmbiguous: p.ExcludeAmbiguous,
+	}
+}
+
 type manifest struct {
 	Kind        string   `json:"kind"`
 	StorageType string   `json:"storageType"`
 	Encryption  string   `json:"encryption"`
 	Recipients  []string `json:"recipients"`
 	Unpacked    bool     `json:"unpacked,omitempty"`
+
+	PasswordPolicy *passwordPolicy   `json:"passwordPolicy,omitempty"`
+	Scopes         []recipientsScope `json:"scopes,omitempty"`
+}
+
+type recipientsScope struct {
+	Path       string   `json:"path"`
+	Recipients []string `json:"recipients"`
+}
+
+type passwordPolicy struct {
+	Length           *int     `json:"length,omitempty"`
+	Words            *int     `json:"words,omitempty"`
+	Required         []string `json:"required,omitempty"`
+	ExcludeAmbiguous bool     `json:"excludeAmbiguous,omitempty"`
 }
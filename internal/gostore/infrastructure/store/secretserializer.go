@@ -2,6 +2,7 @@ package store
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/UsingCoding/gostore/internal/common/maybe"
 
@@ -24,8 +25,9 @@ func (s secretSerializer) Serialize(sec store.Secret) ([]byte, error) {
 	}
 
 	data, err := json.Marshal(secret{
-		Kind:    string(vars.SecretKind),
-		Payload: p,
+		Kind:     string(vars.SecretKind),
+		Payload:  p,
+		Metadata: serializeMetadata(sec.Metadata),
 	})
 	return data, errors.Wrap(err, "failed to serialize secret")
 }
@@ -50,7 +52,8 @@ func (s secretSerializer) Deserialize(b []byte) (store.Secret, error) {
 	}
 
 	return store.Secret{
-		Payload: p,
+		Payload:  p,
+		Metadata: deserializeMetadata(sec.Metadata),
 	}, nil
 }
 
@@ -83,7 +86,46 @@ func (s secretSerializer) RawDeserialize(data []byte) (store.RawSecret, error) {
 	}, nil
 }
 
+func serializeMetadata(m store.Metadata) *metadata {
+	res := metadata{
+		Created:     maybe.ToPtr(m.Created),
+		Updated:     maybe.ToPtr(m.Updated),
+		Author:      maybe.Just(m.Author),
+		Tags:        m.Tags,
+		Description: maybe.Just(m.Description),
+	}
+	if res.Created == nil && res.Updated == nil && res.Author == "" && len(res.Tags) == 0 && res.Description == "" {
+		// keep secrets without metadata as is
+		return nil
+	}
+	return &res
+}
+
+func deserializeMetadata(m *metadata) store.Metadata {
+	if m == nil {
+		return store.Metadata{}
+	}
+
+	return store.Metadata{
+		Created:     maybe.FromPtr(m.Created),
+		Updated:     maybe.FromPtr(m.Updated),
+		Author:      maybe.MapZero(m.Author),
+		Tags:        m.Tags,
+		Description: maybe.MapZero(m.Description),
+	}
+}
+
 type secret struct {
 	Kind    string            `json:"kind"`
 	Payload map[string]string `json:"payload"` // convert to map[string]string to avoid unnecessary base64 conversion
+	// Metadata kept unencrypted
+	Metadata *metadata `json:"metadata,omitempty"`
+}
+
+type metadata struct {
+	Created     *time.Time `json:"created,omitempty"`
+	Updated     *time.Time `json:"updated,omitempty"`
+	Author      string     `json:"author,omitempty"`
+	Tags        []string   `json:"tags,omitempty"`
+	Description string     `json:"description,omitempty"`
 }